@@ -20,6 +20,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Hub marks Dataplane.v1 as a conversion hub.
@@ -43,6 +44,7 @@ const (
 // +genclient:nonNamespaced
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories=stunner,scope=Cluster,shortName=dps
+// +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 
 // Dataplane is a collection of configuration parameters that can be used for spawning a `stunnerd`
@@ -55,6 +57,9 @@ type Dataplane struct {
 
 	// Spec defines the behavior of a Dataplane resource.
 	Spec DataplaneSpec `json:"spec,omitempty"`
+
+	// Status defines the current state of the Dataplane.
+	Status DataplaneStatus `json:"status,omitempty"`
 }
 
 // this must be kept in sync with Renderer.createDeployment and generateDaemonSet, as well as
@@ -134,11 +139,28 @@ type DataplaneSpec struct {
 
 	// Number of desired pods. If empty or set to 1, use whatever is in the target Deployment,
 	// otherwise overwite whatever is in the Deployment (this may block autoscaling the
-	// dataplane though). Ignored if the dataplane is deployed into a DaemonSet. Defaults to 1.
+	// dataplane though). Ignored if the dataplane is deployed into a DaemonSet or if
+	// Autoscaling is set. Defaults to 1.
 	//
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling, if set, makes the operator generate a HorizontalPodAutoscaler for the
+	// dataplane Deployment of each Gateway. The number of replicas is then managed by the
+	// autoscaler and Replicas is ignored. Ignored if the dataplane is deployed into a
+	// DaemonSet. Default is no autoscaling.
+	//
+	// +optional
+	Autoscaling *DataplaneAutoscaling `json:"autoscaling,omitempty"`
+
+	// PodDisruptionBudget, if set, makes the operator generate a PodDisruptionBudget for the
+	// dataplane pods of each Gateway, which limits the number of stunnerd pods that can be
+	// evicted at once, e.g., during node drains. The PodDisruptionBudget is named after the
+	// Gateway and is removed along with the Gateway. Default is no PodDisruptionBudget.
+	//
+	// +optional
+	PodDisruptionBudget *DataplanePodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
 	// Resources required by stunnerd.
 	//
 	// +optional
@@ -176,6 +198,17 @@ type DataplaneSpec struct {
 	// +optional
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// PodTemplatePatch is a patch applied to the pod template generated by the operator for the
+	// dataplane Deployment or DaemonSet. This allows to customize the pod template fields that
+	// are not exposed in the Dataplane spec. The mandatory pod labels and annotations, the
+	// stunnerd container with its image, command and arguments, and the environment the stunnerd
+	// container uses to connect to the operator are enforced after the patch is applied. A patch
+	// that sets a pod template field the operator does not keep in sync, e.g., hostPID, is
+	// rejected.
+	//
+	// +optional
+	PodTemplatePatch *DataplanePodTemplatePatch `json:"podTemplatePatch,omitempty"`
+
 	// Disable health-checking. Default is to enable HTTP health-checks on port 8086: a
 	// liveness probe responder will be exposed on path `/live` and readiness probe on path
 	// `/ready`.
@@ -205,6 +238,184 @@ type DataplaneSpec struct {
 	OffloadInterfaces []string `json:"offloadInterfaces,omitempty"`
 }
 
+// DataplanePodTemplatePatchType is the type of a pod template patch.
+//
+// +kubebuilder:validation:Enum=StrategicMerge;JSONPatch
+type DataplanePodTemplatePatchType string
+
+const (
+	// DataplanePodTemplatePatchStrategicMerge is a Kubernetes strategic merge patch.
+	DataplanePodTemplatePatchStrategicMerge DataplanePodTemplatePatchType = "StrategicMerge"
+
+	// DataplanePodTemplatePatchJSONPatch is an RFC 6902 JSON patch.
+	DataplanePodTemplatePatchJSONPatch DataplanePodTemplatePatchType = "JSONPatch"
+)
+
+// DataplanePodTemplatePatch defines a patch to the pod template of the dataplane resource of a
+// Gateway.
+type DataplanePodTemplatePatch struct {
+	// Type is the type of the patch, either StrategicMerge (default) or JSONPatch.
+	//
+	// +optional
+	// +kubebuilder:default=StrategicMerge
+	Type *DataplanePodTemplatePatchType `json:"type,omitempty"`
+
+	// Patch is the patch to apply to the pod template. For strategic merge patches this is a
+	// partial PodTemplateSpec, e.g., `{"spec":{"nodeSelector":{"role":"turn"}}}`, and for JSON
+	// patches this is a list of RFC 6902 operations, e.g., `[{"op":"add","path":
+	// "/spec/priorityClassName","value":"high"}]`, with paths relative to the pod template.
+	//
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
+}
+
+// DataplanePodDisruptionBudget defines the disruption budget of the dataplane pods of a Gateway.
+// Exactly one of MinAvailable and MaxUnavailable must be set.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.minAvailable) != has(self.maxUnavailable)",message="exactly one of minAvailable and maxUnavailable must be set"
+type DataplanePodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of the dataplane pods of a Gateway that must
+	// remain available after an eviction.
+	//
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of the dataplane pods of a Gateway that can
+	// be unavailable after an eviction.
+	//
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// DataplaneAutoscaling defines the HorizontalPodAutoscaler of the dataplane Deployment of a
+// Gateway. The autoscaler scales on all the metric targets that are set, and on the average CPU
+// utilization with a target of 80% if none is set.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+type DataplaneAutoscaling struct {
+	// MinReplicas is the lower limit for the number of dataplane pods. Defaults to 1.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of dataplane pods.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of the dataplane
+	// pods, in percent of the requested CPU.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetActiveAllocations is the target average number of active TURN allocations per
+	// dataplane pod. Requires the stunnerd metrics endpoint to be enabled and a custom metrics
+	// adapter (e.g., prometheus-adapter) that exposes the `stunner_allocations_active` metric
+	// of the pods via the custom metrics API.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetActiveAllocations *int32 `json:"targetActiveAllocations,omitempty"`
+}
+
+// DataplaneConditionType is a type of condition associated with a Dataplane.
+type DataplaneConditionType string
+
+// DataplaneConditionReason defines the set of reasons that explain why a particular Dataplane
+// condition type has been raised.
+type DataplaneConditionReason string
+
+const (
+	// DataplaneConditionReady indicates whether the dataplane resources (Deployments or
+	// DaemonSets) generated from the Dataplane for each Gateway have rolled out the current
+	// spec.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "Ready"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "Pending"
+	// * "NotFound"
+	//
+	// Possible reasons for this condition to be Unknown are:
+	//
+	// * "NotInUse"
+	DataplaneConditionReady DataplaneConditionType = "Ready"
+
+	// DataplaneReasonReady is used with the "Ready" condition when the condition is True.
+	DataplaneReasonReady DataplaneConditionReason = "Ready"
+
+	// DataplaneReasonPending is used with the "Ready" condition when the rollout of at least
+	// one of the dataplane resources is still in progress.
+	DataplaneReasonPending DataplaneConditionReason = "Pending"
+
+	// DataplaneReasonNotFound is used with the "Ready" condition when the dataplane resource
+	// for at least one of the Gateways has not been created yet.
+	DataplaneReasonNotFound DataplaneConditionReason = "NotFound"
+
+	// DataplaneReasonNotInUse is used with the "Ready" condition when no Gateway uses the
+	// Dataplane, so there is no dataplane resource to report on.
+	DataplaneReasonNotInUse DataplaneConditionReason = "NotInUse"
+)
+
+// DataplaneGatewayStatus describes the rollout state of the dataplane resource generated for a
+// Gateway.
+type DataplaneGatewayStatus struct {
+	// Namespace is the namespace of the Gateway.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+
+	// Kind is the kind of the dataplane resource generated for the Gateway, either Deployment
+	// or DaemonSet. Empty if the resource does not exist.
+	//
+	// +optional
+	Kind DataplaneResourceType `json:"kind,omitempty"`
+
+	// Replicas is the number of desired pods for the Gateway.
+	//
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of pods that are ready.
+	//
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpdatedReplicas is the number of pods running the current pod template.
+	//
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+}
+
+// DataplaneStatus defines the observed state of the Dataplane.
+type DataplaneStatus struct {
+	// Conditions describe the current conditions of the Dataplane.
+	//
+	// Known condition types are "Ready".
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Gateways lists the Gateways that use the Dataplane, along with the rollout state of
+	// the dataplane resource generated for each.
+	//
+	// +optional
+	Gateways []DataplaneGatewayStatus `json:"gateways,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // DataplaneList holds a list of static services.
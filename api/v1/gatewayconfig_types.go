@@ -37,6 +37,7 @@ func (*GatewayConfig) Hub() {}
 // +kubebuilder:printcolumn:name="Realm",type=string,JSONPath=`.spec.realm`
 // +kubebuilder:printcolumn:name="Dataplane",type=string,JSONPath=`.spec.dataplane`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 
 // GatewayConfig is the Schema for the gatewayconfigs API
@@ -45,12 +46,16 @@ type GatewayConfig struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec GatewayConfigSpec `json:"spec,omitempty"`
+
+	// Status defines the current state of GatewayConfig.
+	Status GatewayConfigStatus `json:"status,omitempty"`
 }
 
 // GatewayConfigSpec defines the desired state of GatewayConfig
 type GatewayConfigSpec struct {
 	// Dataplane defines the dataplane (stunnerd image, version, etc) for STUNner gateways
-	// using this GatewayConfig.
+	// using this GatewayConfig. Can be overridden per Gateway with the infrastructure
+	// parameters of the Gateway or the `stunner.l7mp.io/dataplane` Gateway annotation.
 	//
 	// +optional
 	// +kubebuilder:default:="default"
@@ -127,13 +132,12 @@ type GatewayConfigSpec struct {
 	// LoadBalancerServiceAnnotations is a list of annotations that will go into the
 	// LoadBalancer services created automatically by the operator to wrap Gateways.
 	//
-	// NOTE: removing annotations from a GatewayConfig will not result in the removal of the
-	// corresponding annotations from the LoadBalancer service, in order to prevent the
-	// accidental removal of an annotation installed there by Kubernetes or the cloud
-	// provider. If you really want to remove an annotation, do this manually or simply remove
-	// all Gateways (which will remove the corresponding LoadBalancer services), update the
-	// GatewayConfig and then recreate the Gateways, so that the newly created LoadBalancer
-	// services will contain the required annotations.
+	// NOTE: the operator records the annotations it applies to the LoadBalancer service in the
+	// "stunner.l7mp.io/last-applied-metadata" annotation of the service. Removing an
+	// annotation from the GatewayConfig removes the annotation from the LoadBalancer service,
+	// while the annotations installed there by Kubernetes or the cloud provider are left
+	// alone. Annotations applied by an older operator version, which did not record the
+	// applied annotations, must be removed manually.
 	//
 	// +optional
 	LoadBalancerServiceAnnotations map[string]string `json:"loadBalancerServiceAnnotations,omitempty"`
@@ -162,6 +166,94 @@ type GatewayConfigSpec struct {
 	STUNMode bool `json:"stunMode,omitempty"`
 }
 
+// GatewayConfigConditionType is a type of condition associated with a GatewayConfig.
+type GatewayConfigConditionType string
+
+// GatewayConfigConditionReason defines the set of reasons that explain why a particular
+// GatewayConfig condition type has been raised.
+type GatewayConfigConditionReason string
+
+const (
+	// GatewayConfigConditionAccepted indicates whether the GatewayConfig could be used to
+	// render a valid dataplane config.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "Accepted"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "Invalid"
+	GatewayConfigConditionAccepted GatewayConfigConditionType = "Accepted"
+
+	// GatewayConfigReasonAccepted is used with the "Accepted" condition when the condition
+	// is True.
+	GatewayConfigReasonAccepted GatewayConfigConditionReason = "Accepted"
+
+	// GatewayConfigReasonInvalid is used with the "Accepted" condition when the GatewayConfig
+	// is syntactically or semantically invalid, e.g., the authentication settings are
+	// incomplete.
+	GatewayConfigReasonInvalid GatewayConfigConditionReason = "Invalid"
+
+	// GatewayConfigConditionResolvedRefs indicates whether the controller was able to resolve
+	// all the object references of the GatewayConfig, most importantly, the authRef.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "ResolvedRefs"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "InvalidAuthRef"
+	// * "RefNotPermitted"
+	GatewayConfigConditionResolvedRefs GatewayConfigConditionType = "ResolvedRefs"
+
+	// GatewayConfigReasonResolvedRefs is used with the "ResolvedRefs" condition when the
+	// condition is True.
+	GatewayConfigReasonResolvedRefs GatewayConfigConditionReason = "ResolvedRefs"
+
+	// GatewayConfigReasonInvalidAuthRef is used with the "ResolvedRefs" condition when the
+	// authRef points to a nonexistent or invalid Secret.
+	GatewayConfigReasonInvalidAuthRef GatewayConfigConditionReason = "InvalidAuthRef"
+
+	// GatewayConfigReasonRefNotPermitted is used with the "ResolvedRefs" condition when the
+	// authRef points to a Secret in another namespace and no ReferenceGrant allows the
+	// reference.
+	GatewayConfigReasonRefNotPermitted GatewayConfigConditionReason = "RefNotPermitted"
+)
+
+// GatewayConfigStatus defines the observed state of GatewayConfig
+type GatewayConfigStatus struct {
+	// Conditions describe the current conditions of the GatewayConfig.
+	//
+	// Known condition types are "Accepted" and "ResolvedRefs".
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// AuthType is the authentication type resolved from the inline settings or from the
+	// Secret referenced by the authRef.
+	//
+	// +optional
+	AuthType string `json:"authType,omitempty"`
+
+	// AuthSecretResourceVersion is the resourceVersion of the Secret referenced by the
+	// authRef that was used last time the dataplane config was rendered. Empty if no authRef
+	// is given or the Secret could not be resolved.
+	//
+	// +optional
+	AuthSecretResourceVersion string `json:"authSecretResourceVersion,omitempty"`
+
+	// GatewayClasses is the list of the names of the GatewayClasses that refer to this
+	// GatewayConfig in their parametersRef.
+	//
+	// +optional
+	GatewayClasses []string `json:"gatewayClasses,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // GatewayConfigList contains a list of GatewayConfig
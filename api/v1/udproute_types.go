@@ -207,3 +207,86 @@ func ConvertV1A2UDPRouteToV1ListInto(src *gwapiv1a2.UDPRouteList, dst *UDPRouteL
 		ConvertV1A2UDPRouteToV1Into(&src.Items[i], &dst.Items[i])
 	}
 }
+
+// V1A2Route is a Gateway API route kind with plain backend references that is rendered using
+// the canonical UDPRoute representation, e.g., TCPRoute or TLSRoute.
+type V1A2Route interface {
+	*gwapiv1a2.TCPRoute | *gwapiv1a2.TLSRoute
+	metav1.Object
+	runtime.Object
+}
+
+// ConvertV1A2RouteToV1 converts a Gateway API route into the canonical UDPRoute representation
+// used by the renderer. The route kind is not preserved, this must be tracked by the caller. The
+// hostnames of TLSRoutes are ignored: STUNner terminates TLS at the listener and does not route
+// on SNI.
+func ConvertV1A2RouteToV1[R V1A2Route](src R) *UDPRoute {
+	meta, spec, rules := v1a2RouteSpec(src)
+	if meta == nil {
+		return nil
+	}
+
+	dst := new(UDPRoute)
+	meta.DeepCopyInto(&dst.ObjectMeta)
+	spec.DeepCopyInto(&dst.Spec.CommonRouteSpec)
+	V1A2RouteStatus(src).DeepCopyInto(&dst.Status.RouteStatus)
+
+	dst.Spec.Rules = make([]UDPRouteRule, len(rules))
+	for i := range rules {
+		dst.Spec.Rules[i].BackendRefs = convertGwapiBackendRefs(rules[i])
+	}
+	return dst
+}
+
+// V1A2RouteStatus returns the status of a Gateway API route, or nil if the route is nil.
+func V1A2RouteStatus[R V1A2Route](ro R) *gwapiv1.RouteStatus {
+	switch ro := any(ro).(type) {
+	case *gwapiv1a2.TCPRoute:
+		if ro != nil {
+			return &ro.Status.RouteStatus
+		}
+	case *gwapiv1a2.TLSRoute:
+		if ro != nil {
+			return &ro.Status.RouteStatus
+		}
+	}
+	return nil
+}
+
+// v1a2RouteSpec returns the metadata, the common spec and the backend references per rule of a
+// Gateway API route. The metadata is nil if the route is nil.
+func v1a2RouteSpec[R V1A2Route](ro R) (*metav1.ObjectMeta, *gwapiv1.CommonRouteSpec, [][]gwapiv1.BackendRef) {
+	rules := [][]gwapiv1.BackendRef{}
+	switch ro := any(ro).(type) {
+	case *gwapiv1a2.TCPRoute:
+		if ro != nil {
+			for _, rule := range ro.Spec.Rules {
+				rules = append(rules, rule.BackendRefs)
+			}
+			return &ro.ObjectMeta, &ro.Spec.CommonRouteSpec, rules
+		}
+	case *gwapiv1a2.TLSRoute:
+		if ro != nil {
+			for _, rule := range ro.Spec.Rules {
+				rules = append(rules, rule.BackendRefs)
+			}
+			return &ro.ObjectMeta, &ro.Spec.CommonRouteSpec, rules
+		}
+	}
+	return nil, nil, nil
+}
+
+func convertGwapiBackendRefs(src []gwapiv1.BackendRef) []BackendRef {
+	dst := make([]BackendRef, len(src))
+	for j := range src {
+		b := src[j].BackendObjectReference
+		dst[j].BackendObjectReference = BackendObjectReference{
+			Group:     b.Group,
+			Kind:      b.Kind,
+			Name:      b.Name,
+			Namespace: b.Namespace,
+			// ignore port!
+		}
+	}
+	return dst
+}
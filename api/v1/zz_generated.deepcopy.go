@@ -22,7 +22,9 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apisv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -88,6 +90,7 @@ func (in *Dataplane) DeepCopyInto(out *Dataplane) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dataplane.
@@ -108,6 +111,51 @@ func (in *Dataplane) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneAutoscaling) DeepCopyInto(out *DataplaneAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetActiveAllocations != nil {
+		in, out := &in.TargetActiveAllocations, &out.TargetActiveAllocations
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneAutoscaling.
+func (in *DataplaneAutoscaling) DeepCopy() *DataplaneAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneGatewayStatus) DeepCopyInto(out *DataplaneGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneGatewayStatus.
+func (in *DataplaneGatewayStatus) DeepCopy() *DataplaneGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataplaneList) DeepCopyInto(out *DataplaneList) {
 	*out = *in
@@ -140,6 +188,52 @@ func (in *DataplaneList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplanePodDisruptionBudget) DeepCopyInto(out *DataplanePodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplanePodDisruptionBudget.
+func (in *DataplanePodDisruptionBudget) DeepCopy() *DataplanePodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplanePodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplanePodTemplatePatch) DeepCopyInto(out *DataplanePodTemplatePatch) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(DataplanePodTemplatePatchType)
+		**out = **in
+	}
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplanePodTemplatePatch.
+func (in *DataplanePodTemplatePatch) DeepCopy() *DataplanePodTemplatePatch {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplanePodTemplatePatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataplaneSpec) DeepCopyInto(out *DataplaneSpec) {
 	*out = *in
@@ -206,6 +300,16 @@ func (in *DataplaneSpec) DeepCopyInto(out *DataplaneSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(DataplaneAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(DataplanePodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(corev1.ResourceRequirements)
@@ -240,6 +344,11 @@ func (in *DataplaneSpec) DeepCopyInto(out *DataplaneSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodTemplatePatch != nil {
+		in, out := &in.PodTemplatePatch, &out.PodTemplatePatch
+		*out = new(DataplanePodTemplatePatch)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.OffloadInterfaces != nil {
 		in, out := &in.OffloadInterfaces, &out.OffloadInterfaces
 		*out = make([]string, len(*in))
@@ -257,12 +366,40 @@ func (in *DataplaneSpec) DeepCopy() *DataplaneSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneStatus) DeepCopyInto(out *DataplaneStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]DataplaneGatewayStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneStatus.
+func (in *DataplaneStatus) DeepCopy() *DataplaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfig.
@@ -382,6 +519,33 @@ func (in *GatewayConfigSpec) DeepCopy() *GatewayConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayConfigStatus) DeepCopyInto(out *GatewayConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GatewayClasses != nil {
+		in, out := &in.GatewayClasses, &out.GatewayClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigStatus.
+func (in *GatewayConfigStatus) DeepCopy() *GatewayConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticService) DeepCopyInto(out *StaticService) {
 	*out = *in
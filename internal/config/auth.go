@@ -0,0 +1,194 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+const (
+	// podIPField is the field selector of the pods by their IP address.
+	podIPField = "status.podIP"
+
+	podLookupTimeout = 5 * time.Second
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// Authenticator authenticates the clients of the config discovery server.
+type Authenticator interface {
+	// Authenticate returns the Gateways whose config the client of the request may receive.
+	Authenticate(r *http.Request) ([]types.NamespacedName, error)
+}
+
+type cacheEntry struct {
+	gateways []types.NamespacedName
+	expires  time.Time
+}
+
+// podAuthenticator authenticates stunnerd pods by their source address. The pod is looked up by
+// its IP address and the Gateway is taken from the labels of the pod. Pods in the host network
+// share the address of the node, so a client in the host network of a node may receive the
+// config of any Gateway that has a host network dataplane pod on the node.
+type podAuthenticator struct {
+	reader client.Reader
+	ttl    time.Duration
+	cache  map[string]cacheEntry
+	now    func() time.Time
+	lock   sync.Mutex
+	log    logr.Logger
+}
+
+// NewPodAuthenticator creates an authenticator that identifies the clients by the pod that owns
+// their source address. The reader is used to list the pods, which should be an uncached reader
+// so that the operator does not have to watch all pods.
+func NewPodAuthenticator(reader client.Reader, logger logr.Logger) Authenticator {
+	return &podAuthenticator{
+		reader: reader,
+		ttl:    opdefault.DefaultCDSAuthCacheTTL,
+		cache:  map[string]cacheEntry{},
+		now:    time.Now,
+		log:    logger.WithName("cds-auth"),
+	}
+}
+
+func (a *podAuthenticator) Authenticate(r *http.Request) ([]types.NamespacedName, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid client address %q", errUnauthorized, r.RemoteAddr)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("%w: invalid client address %q", errUnauthorized, r.RemoteAddr)
+	}
+	addr := ip.String()
+
+	if gws, ok := a.lookup(addr); ok {
+		return gws, nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), podLookupTimeout)
+	defer cancel()
+
+	gws, err := a.gateways(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	a.lock.Lock()
+	a.cache[addr] = cacheEntry{gateways: gws, expires: a.now().Add(a.ttl)}
+	a.lock.Unlock()
+
+	return gws, nil
+}
+
+func (a *podAuthenticator) lookup(addr string) ([]types.NamespacedName, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+	for k, e := range a.cache {
+		if now.After(e.expires) {
+			delete(a.cache, k)
+		}
+	}
+
+	e, ok := a.cache[addr]
+	return e.gateways, ok
+}
+
+// gateways returns the Gateways of the dataplane pods with the given address.
+func (a *podAuthenticator) gateways(ctx context.Context, addr string) ([]types.NamespacedName, error) {
+	pods := &corev1.PodList{}
+	if err := a.reader.List(ctx, pods, client.MatchingFields{podIPField: addr}); err != nil {
+		return nil, fmt.Errorf("cannot list pods: %w", err)
+	}
+
+	gws := []types.NamespacedName{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		// the address of a terminated pod may have been reused
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		// the Gateway must live in the namespace of the pod
+		labels := pod.GetLabels()
+		gw := types.NamespacedName{
+			Namespace: labels[opdefault.RelatedGatewayNamespace],
+			Name:      labels[opdefault.RelatedGatewayKey],
+		}
+		if labels[opdefault.AppLabelKey] != opdefault.AppLabelValue || gw.Name == "" ||
+			gw.Namespace != pod.GetNamespace() {
+			continue
+		}
+
+		if !slices.Contains(gws, gw) {
+			gws = append(gws, gw)
+		}
+
+		a.log.V(2).Info("Authenticated config discovery client", "address", addr,
+			"pod", client.ObjectKeyFromObject(pod).String(), "gateway", gw.String())
+	}
+
+	if len(gws) == 0 {
+		return nil, fmt.Errorf("%w: no dataplane pod with address %s", errUnauthorized, addr)
+	}
+
+	return gws, nil
+}
+
+// authorized returns true if the client of the request may access the requested resource. A
+// client may receive the config of its own Gateways and the license status.
+func authorized(r *http.Request, gws []types.NamespacedName) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+
+	ps := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(ps) == 3 && ps[0] == "api" && ps[1] == "v1" && ps[2] == "license":
+		return true
+	case len(ps) == 5 && ps[0] == "api" && ps[1] == "v1" && ps[2] == "configs":
+		return slices.Contains(gws, types.NamespacedName{Namespace: ps[3], Name: ps[4]})
+	default:
+		return false
+	}
+}
+
+// authHandler authenticates and authorizes the requests before passing them to the config
+// discovery server.
+func (c *Server) authHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gws, err := c.auth.Authenticate(r)
+		if err != nil {
+			c.log.V(1).Info("Config discovery client authentication failed", "client",
+				r.RemoteAddr, "path", r.URL.Path, "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		if !authorized(r, gws) {
+			c.log.V(1).Info("Config discovery request denied", "client", r.RemoteAddr,
+				"gateways", fmt.Sprintf("%v", gws), "method", r.Method, "path", r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+func testDataplanePod(namespace, name, ip, gwNamespace, gwName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				opdefault.AppLabelKey:             opdefault.AppLabelValue,
+				opdefault.RelatedGatewayKey:       gwName,
+				opdefault.RelatedGatewayNamespace: gwNamespace,
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+}
+
+// testPodAuthenticator creates an authenticator with a fake API that serves the given pods and
+// counts the pod lists.
+func testPodAuthenticator(pods ...client.Object) (*podAuthenticator, *int) {
+	lists := 0
+	c := fake.NewClientBuilder().WithObjects(pods...).
+		WithIndex(&corev1.Pod{}, podIPField, func(o client.Object) []string {
+			return []string{o.(*corev1.Pod).Status.PodIP}
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, l client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				return c.List(ctx, l, opts...)
+			},
+		}).Build()
+
+	return NewPodAuthenticator(c, logr.Discard()).(*podAuthenticator), &lists
+}
+
+func testRequest(path, remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func TestPodAuthenticator(t *testing.T) {
+	hostNet1 := testDataplanePod("testnamespace", "hostnet-1", "10.0.1.1", "testnamespace", "gateway-1")
+	hostNet2 := testDataplanePod("testnamespace", "hostnet-2", "10.0.1.1", "testnamespace", "gateway-2")
+	failed := testDataplanePod("testnamespace", "failed", "10.0.0.5", "testnamespace", "gateway-2")
+	failed.Status.Phase = corev1.PodFailed
+	pods := []client.Object{
+		testDataplanePod("testnamespace", "stunnerd-1", "10.0.0.1", "testnamespace", "gateway-1"),
+		testDataplanePod("testnamespace", "stunnerd-v6", "2001:db8::1", "testnamespace", "gateway-1"),
+		testDataplanePod("testnamespace", "cross-ns", "10.0.0.2", "othernamespace", "gateway-1"),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace", Name: "other"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.3"},
+		},
+		hostNet1, hostNet2, failed,
+	}
+	a, lists := testPodAuthenticator(pods...)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	gw1 := types.NamespacedName{Namespace: "testnamespace", Name: "gateway-1"}
+	gw2 := types.NamespacedName{Namespace: "testnamespace", Name: "gateway-2"}
+
+	gws, err := a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", "10.0.0.1:1234"))
+	assert.NoError(t, err, "dataplane pod")
+	assert.Equal(t, []types.NamespacedName{gw1}, gws, "gateways")
+
+	gws, err = a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", "[2001:db8::1]:1234"))
+	assert.NoError(t, err, "IPv6 dataplane pod")
+	assert.Equal(t, []types.NamespacedName{gw1}, gws, "gateways")
+
+	gws, err = a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", "10.0.1.1:1234"))
+	assert.NoError(t, err, "host network dataplane pods")
+	assert.ElementsMatch(t, []types.NamespacedName{gw1, gw2}, gws, "gateways")
+
+	for _, addr := range []string{"", "dummy", "10.0.0.2:1234", "10.0.0.3:1234", "10.0.0.4:1234",
+		"10.0.0.5:1234"} {
+		_, err := a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", addr))
+		assert.Error(t, err, "address %q rejected", addr)
+	}
+
+	// authenticated addresses are cached
+	n := *lists
+	_, err = a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", "10.0.0.1:4321"))
+	assert.NoError(t, err, "cached address")
+	assert.Equal(t, n, *lists, "no pod list for a cached address")
+
+	now = now.Add(opdefault.DefaultCDSAuthCacheTTL + time.Second)
+	_, err = a.Authenticate(testRequest("/api/v1/configs/testnamespace/gateway-1", "10.0.0.1:4321"))
+	assert.NoError(t, err, "expired cache")
+	assert.Equal(t, n+1, *lists, "pod list for an expired cache entry")
+}
+
+func TestAuthHandler(t *testing.T) {
+	a, _ := testPodAuthenticator(
+		testDataplanePod("testnamespace", "stunnerd-1", "10.0.0.1", "testnamespace", "gateway-1"))
+
+	srv := &Server{auth: a, log: logr.Discard()}
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := srv.authHandler(backend)
+
+	for _, tc := range []struct {
+		name, method, path, addr string
+		code                     int
+	}{
+		{"own config", http.MethodGet, "/api/v1/configs/testnamespace/gateway-1", "10.0.0.1:1234", http.StatusOK},
+		{"own config watch", http.MethodGet, "/api/v1/configs/testnamespace/gateway-1?watch=true&node=testnode", "10.0.0.1:1234", http.StatusOK},
+		{"license", http.MethodGet, "/api/v1/license", "10.0.0.1:1234", http.StatusOK},
+		{"unknown address", http.MethodGet, "/api/v1/configs/testnamespace/gateway-1", "10.0.0.2:1234", http.StatusForbidden},
+		{"other gateway", http.MethodGet, "/api/v1/configs/testnamespace/gateway-2", "10.0.0.1:1234", http.StatusForbidden},
+		{"other namespace", http.MethodGet, "/api/v1/configs/othernamespace/gateway-1", "10.0.0.1:1234", http.StatusForbidden},
+		{"list namespace", http.MethodGet, "/api/v1/configs/testnamespace", "10.0.0.1:1234", http.StatusForbidden},
+		{"list all", http.MethodGet, "/api/v1/configs", "10.0.0.1:1234", http.StatusForbidden},
+		{"write", http.MethodPost, "/api/v1/configs/testnamespace/gateway-1", "10.0.0.1:1234", http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			r.RemoteAddr = tc.addr
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, tc.code, w.Code, "status code")
+		})
+	}
+}
+
+func TestAuthProxy(t *testing.T) {
+	a, _ := testPodAuthenticator(
+		testDataplanePod("testnamespace", "stunnerd-1", "127.0.0.1", "testnamespace", "gateway-1"))
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer backend.Close()
+
+	addr := getRandCDSAddr()
+	srv := &Server{auth: a, addr: addr, backendAddr: backend.Listener.Addr().String(),
+		sync: newSyncTracker(), log: logr.Discard()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, srv.startProxy(ctx), "proxy start")
+
+	get := func(path string) (int, string) {
+		res, err := http.Get("http://127.0.0.1" + addr + path)
+		require.NoError(t, err, "get")
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err, "read body")
+		return res.StatusCode, string(body)
+	}
+
+	code, body := get("/api/v1/configs/testnamespace/gateway-1")
+	assert.Equal(t, http.StatusOK, code, "own config")
+	assert.Equal(t, "/api/v1/configs/testnamespace/gateway-1", body, "forwarded")
+
+	code, _ = get("/api/v1/configs/testnamespace/gateway-2")
+	assert.Equal(t, http.StatusForbidden, code, "other config")
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+// SetLeaderElection makes the config discovery server serve the clients only once the
+// operator has been elected leader. Standby replicas do not listen on the config discovery
+// address. Must be called before Start.
+func (c *Server) SetLeaderElection(elected <-chan struct{}) {
+	c.elected = elected
+}
+
+// waitForElection blocks until the operator is elected leader, then loads the snapshot, if
+// any, and starts serving the clients. The snapshot is loaded only after the election so that
+// a new leader serves the last state published by the previous leader. Returns false if the
+// context is canceled before the election.
+func (c *Server) waitForElection(ctx context.Context) bool {
+	c.log.Info("Waiting for leader election before serving config discovery clients")
+
+	select {
+	case <-c.elected:
+	case <-ctx.Done():
+		return false
+	}
+
+	c.log.Info("Elected leader, starting config discovery server")
+
+	if c.snapshots != nil {
+		c.loadSnapshot(ctx)
+	}
+
+	if err := c.serve(ctx); err != nil {
+		c.log.Error(err, "Could not start config discovery server")
+	}
+
+	return true
+}
+
+// LeaderLabeler labels the operator pod while it holds the leader lease so that a Service can
+// route the config discovery clients to the active config discovery server.
+type LeaderLabeler struct {
+	client client.Client
+	pod    types.NamespacedName
+	log    logr.Logger
+}
+
+// NewLeaderLabeler creates a leader labeler for the given operator pod. Add it to the manager
+// to label the pod once elected.
+func NewLeaderLabeler(c client.Client, pod types.NamespacedName, logger logr.Logger) *LeaderLabeler {
+	return &LeaderLabeler{
+		client: c,
+		pod:    pod,
+		log:    logger.WithName("cds-leader"),
+	}
+}
+
+// NeedLeaderElection makes the manager start the labeler only on the leader.
+func (l *LeaderLabeler) NeedLeaderElection() bool { return true }
+
+// Start sets the leader label and removes it when the operator steps down.
+func (l *LeaderLabeler) Start(ctx context.Context) error {
+	// do not fail the manager: the config discovery server is still reachable directly
+	if err := l.setLabel(ctx, true); err != nil {
+		l.log.Error(err, "Could not set the config discovery leader label", "pod",
+			l.pod.String())
+	} else {
+		l.log.Info("Labeled operator pod as the config discovery leader", "pod",
+			l.pod.String())
+	}
+
+	<-ctx.Done()
+
+	// the manager context is already canceled
+	cctx, cancel := context.WithTimeout(context.Background(), opdefault.DefaultCDSLeaderLabelTimeout)
+	defer cancel()
+	if err := l.setLabel(cctx, false); err != nil {
+		l.log.Error(err, "Could not remove the config discovery leader label", "pod",
+			l.pod.String())
+	}
+
+	return nil
+}
+
+// Reset removes a stale leader label, left behind by a previous run of the operator in the
+// same pod. Call it before starting the manager.
+func (l *LeaderLabeler) Reset(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, opdefault.DefaultCDSLeaderLabelTimeout)
+	defer cancel()
+	return l.setLabel(ctx, false)
+}
+
+// setLabel sets or removes the leader label with a merge patch, which does not need the pod
+// to be in the cache.
+func (l *LeaderLabeler) setLabel(ctx context.Context, leader bool) error {
+	var value any
+	if leader {
+		value = opdefault.DefaultCDSLeaderLabelValue
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{opdefault.DefaultCDSLeaderLabelKey: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: l.pod.Namespace, Name: l.pod.Name}}
+	if err := l.client.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("cannot update the leader label on pod %s: %w", l.pod.String(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+func TestLeaderLabeler(t *testing.T) {
+	key := types.NamespacedName{Namespace: "stunner-system", Name: "operator-1"}
+	c := fake.NewClientBuilder().WithObjects(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: key.Namespace, Name: key.Name,
+		Labels: map[string]string{"app": "operator", opdefault.DefaultCDSLeaderLabelKey: "true"},
+	}}).Build()
+	l := NewLeaderLabeler(c, key, logr.Discard())
+	assert.True(t, l.NeedLeaderElection(), "runs on the leader only")
+
+	labels := func() map[string]string {
+		pod := &corev1.Pod{}
+		require.NoError(t, c.Get(context.Background(), key, pod), "get pod")
+		return pod.GetLabels()
+	}
+
+	// a stale label is removed at startup
+	require.NoError(t, l.Reset(context.Background()), "reset")
+	assert.Equal(t, map[string]string{"app": "operator"}, labels(), "stale label removed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Start(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return labels()[opdefault.DefaultCDSLeaderLabelKey] == opdefault.DefaultCDSLeaderLabelValue
+	}, time.Second, 10*time.Millisecond, "leader labeled")
+	assert.Equal(t, "operator", labels()["app"], "other labels kept")
+
+	// the label is removed when stepping down
+	cancel()
+	require.NoError(t, <-done, "labeler stopped")
+	assert.Equal(t, map[string]string{"app": "operator"}, labels(), "label removed")
+
+	// a missing pod is an error
+	l = NewLeaderLabeler(c, types.NamespacedName{Namespace: "stunner-system", Name: "dummy"},
+		logr.Discard())
+	assert.Error(t, l.Reset(context.Background()), "missing pod")
+}
+
+func TestServerLeaderElection(t *testing.T) {
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json"))
+	elected := make(chan struct{})
+	c, err := NewCDSServer("127.0.0.1:0", nil, logr.Discard())
+	require.NoError(t, err, "CDS server")
+	c.SetSnapshotStore(store)
+	c.SetLeaderElection(elected)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, c.Start(ctx), "start")
+
+	// the leader publishes a snapshot while we are on standby
+	require.NoError(t, store.Save(ctx, &Snapshot{Generation: 1, Configs: []*stnrv1.StunnerConfig{
+		snapshotConfig("testnamespace", "gateway-1", "leader"),
+	}}), "save")
+
+	// updates are not processed before the election
+	e := event.NewEventUpdate(1)
+	e.ConfigQueue = []*stnrv1.StunnerConfig{snapshotConfig("testnamespace", "gateway-1", "fresh")}
+	e.SetRequestAck(true)
+	c.GetConfigUpdateChannel() <- e
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, c.GetConfigs(), "standby")
+
+	// once elected, the snapshot of the previous leader is served until the update is acked
+	close(elected)
+	assert.Eventually(t, func() bool {
+		return len(c.GetConfigs()) == 1
+	}, time.Second, 10*time.Millisecond, "elected")
+	assert.Equal(t, "leader", servedRealms(c)["testnamespace/gateway-1"], "snapshot served")
+
+	c.GetAckChannel() <- event.NewEventAck(1)
+	assert.Eventually(t, func() bool {
+		return servedRealms(c)["testnamespace/gateway-1"] == "fresh"
+	}, time.Second, 10*time.Millisecond, "switched over")
+}
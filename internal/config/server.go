@@ -6,8 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -16,29 +22,99 @@ import (
 
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
 	"github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
+const proxyReadHeaderTimeout = 10 * time.Second
+
 type Server struct {
 	*cdsserver.Server
+	ctx      context.Context
 	configCh chan event.Event
+	// auth authenticates the clients, nil if authentication is disabled.
+	auth Authenticator
+	// addr is the address the proxy listens on, and backendAddr is the address of the config
+	// discovery server the proxy forwards the requests to.
+	addr, backendAddr string
+	// backend reserves the loopback port of the config discovery server until it is started.
+	backend net.Listener
+	// ackCh receives the acknowledgments of the updates from the operator.
+	ackCh chan event.Event
+	// snapshots persists the last acknowledged configs, nil if persistence is disabled.
+	snapshots     SnapshotStore
+	snapshotState snapshotState
+	// elected is closed when the operator is elected leader, nil if leader election is
+	// disabled.
+	elected <-chan struct{}
+	// sync tracks the config versions delivered to the clients.
+	sync       *syncTracker
+	operatorCh event.EventChannel
 	*ProgressTracker
 	log logr.Logger
 }
 
-func NewCDSServer(addr string, logger logr.Logger) *Server {
+// NewCDSServer creates a new config discovery server. The server listens on a loopback address
+// and a proxy serves the clients on addr, tracking the client connections and, if an
+// authenticator is given, authenticating the clients. The loopback port is reserved until the
+// server starts.
+func NewCDSServer(addr string, auth Authenticator, logger logr.Logger) (*Server, error) {
 	log := logger.WithName("cds-server")
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("cannot reserve a loopback address for the config discovery server: %w", err)
+	}
+	backendAddr := backend.Addr().String()
+
+	// the patcher is called whenever a config is sent to a client, with the client id
+	// encoded into the node name by the proxy
+	sync := newSyncTracker()
+	patch := getNodeAddressPatcher(log)
+	tracker := func(conf *stnrv1.StunnerConfig, node string) *stnrv1.StunnerConfig {
+		node, client := splitClientNode(node)
+		sync.delivered(conf, client)
+		return patch(conf, node)
+	}
+
 	return &Server{
-		Server:          cdsserver.New(addr, getNodeAddressPatcher(log), log),
+		Server:          cdsserver.New(backendAddr, tracker, log),
+		sync:            sync,
 		configCh:        make(chan event.Event, 10),
+		ackCh:           make(chan event.Event, 1),
+		auth:            auth,
+		addr:            addr,
+		backendAddr:     backendAddr,
+		backend:         backend,
 		ProgressTracker: NewProgressTracker(),
 		log:             log,
-	}
+	}, nil
 }
 
 func (c *Server) Start(ctx context.Context) error {
+	c.ctx = ctx
+
+	if c.elected == nil {
+		if c.snapshots != nil {
+			c.loadSnapshot(ctx)
+		}
+
+		if err := c.serve(ctx); err != nil {
+			return err
+		}
+	}
+
 	go func() {
 		defer close(c.configCh)
+		defer func() {
+			if c.operatorCh != nil {
+				c.operatorCh.Put()
+			}
+		}()
+
+		if c.elected != nil && !c.waitForElection(ctx) {
+			c.releaseBackend()
+			return
+		}
 		defer c.Close()
 
 		for {
@@ -57,13 +133,80 @@ func (c *Server) Start(ctx context.Context) error {
 				}
 				c.ProgressUpdate(-1)
 
+			case e := <-c.ackCh:
+				if e.GetType() != event.EventTypeAck {
+					c.log.Info("Config discovery server received unknown event",
+						"event", e.String())
+					continue
+				}
+
+				c.ProcessAck(e.(*event.EventAck))
+
+			case <-c.sync.notifyCh:
+				c.reportSyncStatus()
+
 			case <-ctx.Done():
 				return
 			}
 		}
 	}()
 
-	return c.Server.Start(ctx)
+	return nil
+}
+
+// serve starts serving the config discovery clients.
+func (c *Server) serve(ctx context.Context) error {
+	// the config discovery server binds the reserved port right after it is released
+	c.releaseBackend()
+	if err := c.Server.Start(ctx); err != nil {
+		return fmt.Errorf("cannot start config discovery server on %q: %w", c.backendAddr, err)
+	}
+
+	return c.startProxy(ctx)
+}
+
+// startProxy starts the proxy that tracks the client connections and, if enabled,
+// authenticates the clients before forwarding the requests to the config discovery server. The
+// reverse proxy also forwards the WebSocket upgrades of the watching clients.
+func (c *Server) startProxy(ctx context.Context) error {
+	l, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen on config discovery address %q: %w", c.addr, err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: c.backendAddr})
+	handler := c.sync.trackClients(proxy)
+	if c.auth != nil {
+		handler = c.authHandler(handler)
+	}
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: proxyReadHeaderTimeout,
+	}
+
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.log.Error(err, "Config discovery proxy failed")
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	c.log.Info("Config discovery proxy started", "address", c.addr, "backend-address",
+		c.backendAddr, "authentication", c.auth != nil)
+
+	return nil
+}
+
+// releaseBackend releases the reserved loopback port of the config discovery server.
+func (c *Server) releaseBackend() {
+	if c.backend != nil {
+		c.backend.Close()
+		c.backend = nil
+	}
 }
 
 // GetConfigUpdateChannel returns the channel on which the config discovery server listenens to
@@ -72,12 +215,35 @@ func (c *Server) GetConfigUpdateChannel() chan event.Event {
 	return c.configCh
 }
 
+// SetOperatorChannel sets the channel on which the config discovery server asks the operator to
+// refresh the status of the Gateways whose clients have picked up a new config.
+func (c *Server) SetOperatorChannel(ch event.EventChannel) {
+	c.operatorCh = ch
+	ch.Get()
+}
+
+// GetAckChannel returns the channel on which the config discovery server listens to the
+// acknowledgments of the updates.
+func (c *Server) GetAckChannel() chan event.Event {
+	return c.ackCh
+}
+
 // ProcessUpdate processes new config events and updates the server with the current
 // state-of-the-world.
-func (c *Server) ProcessUpdate(e *event.EventUpdate) error {
+func (c *Server) ProcessUpdate(e *event.EventUpdate) (err error) {
 	c.log.Info("Processing config update event", "generation", e.Generation, "update",
 		e.String())
 
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracing.Start(e.GetTraceContext(ctx), "UpdateConfig", trace.WithAttributes(
+		attribute.Int("generation", e.Generation),
+		attribute.Int("configs", len(e.ConfigQueue)),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	configs := []cdsserver.Config{}
 	for _, conf := range e.ConfigQueue {
 		id := conf.Admin.Name
@@ -92,13 +258,55 @@ func (c *Server) ProcessUpdate(e *event.EventUpdate) error {
 		}
 	}
 
+	c.UpdateLicenseStatus(e.LicenseStatus)
+
+	if c.snapshots == nil {
+		return c.updateConfig(configs)
+	}
+
+	return c.processSnapshotUpdate(ctx, e, configs)
+}
+
+// updateConfig updates the configs served to the clients.
+func (c *Server) updateConfig(configs []cdsserver.Config) error {
 	if err := c.UpdateConfig(configs); err != nil {
 		return err
 	}
+	c.sync.update(configs)
+	return nil
+}
 
-	c.UpdateLicenseStatus(e.LicenseStatus)
+// GetSyncStatus returns the sync status of the clients of a config.
+func (c *Server) GetSyncStatus(id string) (SyncStatus, bool) {
+	return c.sync.GetSyncStatus(id)
+}
 
-	return nil
+// reportSyncStatus triggers a render for the Gateways whose sync status has changed, so that
+// the Gateway status is updated.
+func (c *Server) reportSyncStatus() {
+	ids := c.sync.takeChanged()
+	if c.operatorCh == nil || DataplaneMode != DataplaneModeManaged || len(ids) == 0 {
+		return
+	}
+
+	changes := []event.ObjectKey{}
+	for _, id := range ids {
+		if namespace, name, ok := cdsserver.NamespacedName(id); ok {
+			changes = append(changes, event.ObjectKey{Kind: "Gateway",
+				NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	c.log.V(2).Info("Config discovery client sync status changed", "configs", ids)
+
+	// the operator may have stopped reading the channel on shutdown
+	select {
+	case c.operatorCh.Channel() <- event.NewEventReconcile(changes...):
+	case <-c.ctx.Done():
+	}
 }
 
 func getNodeAddressPatcher(log logr.Logger) cdsserver.ConfigNodePatcher {
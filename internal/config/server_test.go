@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -479,8 +480,8 @@ func TestConfigPatcher(t *testing.T) {
 
 	testCDSAddr := getRandCDSAddr()
 	log.Info("create server", "address", testCDSAddr)
-	srv := NewCDSServer(testCDSAddr, zlogger.WithName("cds-server"))
-	assert.NotNil(t, srv, "CDS server")
+	srv, err := NewCDSServer(testCDSAddr, nil, zlogger.WithName("cds-server"))
+	assert.NoError(t, err, "CDS server")
 
 	log.Info("starting CDS server")
 	ctx, cancel := context.WithCancel(context.Background())
@@ -611,6 +612,48 @@ func TestGetNodeAddressIPv6(t *testing.T) {
 	assert.Equal(t, "2001:db8::1", addr, "IPv6 external address")
 }
 
+// TestConfigDiscoveryPodAuth checks that the stunnerd config discovery client obtains the config
+// of its own Gateway through the authenticating proxy, and only that.
+func TestConfigDiscoveryPodAuth(t *testing.T) {
+	a, _ := testPodAuthenticator(
+		testDataplanePod("testnamespace", "stunnerd-1", "127.0.0.1", "testnamespace", "gateway-1"))
+
+	backendAddr, addr := getRandCDSAddr(), getRandCDSAddr()
+	noPatch := func(conf *stnrv1.StunnerConfig, _ string) *stnrv1.StunnerConfig { return conf }
+	srv := &Server{
+		Server:      cdsserver.New(backendAddr, noPatch, logr.Discard()),
+		auth:        a,
+		addr:        addr,
+		backendAddr: backendAddr,
+		sync:        newSyncTracker(),
+		log:         logr.Discard(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, srv.serve(ctx), "cds server start")
+	time.Sleep(50 * time.Millisecond)
+
+	conf1 := zeroConfig("testnamespace", "gateway-1", "testrealm")
+	conf2 := zeroConfig("testnamespace", "gateway-2", "testrealm")
+	assert.NoError(t, srv.UpdateConfig([]cdsserver.Config{
+		{Namespace: "testnamespace", Name: "gateway-1", Config: conf1},
+		{Namespace: "testnamespace", Name: "gateway-2", Config: conf2},
+	}), "config update")
+
+	c, err := cdsclient.New("http://127.0.0.1"+addr, "testnamespace/gateway-1", "",
+		logger.NewLoggerFactory(stunnerTestLoglevel))
+	assert.NoError(t, err, "cds client setup")
+	loaded, err := c.Load()
+	assert.NoError(t, err, "load")
+	assert.True(t, loaded.DeepEqual(conf1), "config")
+
+	c, err = cdsclient.New("http://127.0.0.1"+addr, "testnamespace/gateway-2", "",
+		logger.NewLoggerFactory(stunnerTestLoglevel))
+	assert.NoError(t, err, "cds client setup")
+	_, err = c.Load()
+	assert.Error(t, err, "config of another Gateway rejected")
+}
+
 // wait for some configurable time for a watch element
 func watchConfig(ch chan *stnrv1.StunnerConfig, d time.Duration) *stnrv1.StunnerConfig {
 	select {
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+	cdsserver "github.com/l7mp/stunner/pkg/config/server"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+const (
+	snapshotFilePrefix   = "file:"
+	snapshotSecretPrefix = "secret:"
+)
+
+// Snapshot is the set of stunnerd configs served by the config discovery server at a given
+// generation.
+type Snapshot struct {
+	Generation int                     `json:"generation"`
+	Configs    []*stnrv1.StunnerConfig `json:"configs"`
+}
+
+// SnapshotStore persists the last acknowledged config discovery snapshot across operator
+// restarts. The snapshot contains the full stunnerd configs, including the TURN credentials
+// and the TLS keys.
+type SnapshotStore interface {
+	// Load returns the last saved snapshot, or nil if there is none.
+	Load(ctx context.Context) (*Snapshot, error)
+	// Save persists a snapshot, overwriting the previous one.
+	Save(ctx context.Context, s *Snapshot) error
+	String() string
+}
+
+// NewSnapshotStore creates a snapshot store from a spec: either "file:<path>" or
+// "secret:<namespace>/<name>". The client is used to write the Secret and the reader to read it,
+// which should be an uncached reader since the snapshot is loaded before the manager caches are
+// started.
+func NewSnapshotStore(spec string, c client.Client, reader client.Reader) (SnapshotStore, error) {
+	switch {
+	case strings.HasPrefix(spec, snapshotFilePrefix):
+		path := strings.TrimPrefix(spec, snapshotFilePrefix)
+		if path == "" {
+			return nil, errors.New("empty snapshot file path")
+		}
+		return NewFileSnapshotStore(path), nil
+
+	case strings.HasPrefix(spec, snapshotSecretPrefix):
+		namespace, name, ok := strings.Cut(strings.TrimPrefix(spec, snapshotSecretPrefix), "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid snapshot Secret %q, expecting <namespace>/<name>",
+				spec)
+		}
+		return NewSecretSnapshotStore(c, reader, types.NamespacedName{Namespace: namespace,
+			Name: name}), nil
+
+	default:
+		return nil, fmt.Errorf("invalid snapshot store %q, expecting file:<path> or "+
+			"secret:<namespace>/<name>", spec)
+	}
+}
+
+// fileSnapshotStore persists the snapshot into a local file. The file is readable only by the
+// operator, but it must be on a volume that is not accessible to others.
+type fileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore creates a snapshot store that persists the snapshot into a local file.
+func NewFileSnapshotStore(path string) SnapshotStore {
+	return &fileSnapshotStore{path: path}
+}
+
+func (s *fileSnapshotStore) Load(_ context.Context) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return decodeSnapshot(data)
+}
+
+// Save writes the snapshot into a temporary file and renames it, so that a crash never leaves
+// a truncated snapshot behind. The temporary file is created with mode 0600.
+func (s *fileSnapshotStore) Save(_ context.Context, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
+
+func (s *fileSnapshotStore) String() string { return snapshotFilePrefix + s.path }
+
+// secretSnapshotStore persists the snapshot into a Secret.
+type secretSnapshotStore struct {
+	client client.Client
+	reader client.Reader
+	key    types.NamespacedName
+}
+
+// NewSecretSnapshotStore creates a snapshot store that persists the snapshot into a Secret.
+func NewSecretSnapshotStore(c client.Client, reader client.Reader, key types.NamespacedName) SnapshotStore {
+	return &secretSnapshotStore{client: c, reader: reader, key: key}
+}
+
+func (s *secretSnapshotStore) Load(ctx context.Context) (*Snapshot, error) {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, s.key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, ok := secret.Data[opdefault.DefaultCDSSnapshotKey]
+	if !ok {
+		return nil, nil
+	}
+
+	return decodeSnapshot(data)
+}
+
+func (s *secretSnapshotStore) Save(ctx context.Context, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, s.key, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.key.Namespace,
+				Name:      s.key.Name,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{opdefault.DefaultCDSSnapshotKey: data},
+		}
+		return s.client.Create(ctx, secret)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[opdefault.DefaultCDSSnapshotKey] = data
+
+	return s.client.Update(ctx, secret)
+}
+
+func (s *secretSnapshotStore) String() string {
+	return snapshotSecretPrefix + s.key.String()
+}
+
+func decodeSnapshot(data []byte) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid config discovery snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+// snapshotState tracks the snapshot served by the config discovery server.
+type snapshotState struct {
+	// serving is true while the configs loaded from the snapshot store are served.
+	serving bool
+	// pending is the last update not yet acknowledged by the updater.
+	pending *pendingUpdate
+	// lastAcked is the last generation acknowledged by the updater.
+	lastAcked int
+	// lastSaved is the last snapshot saved into the store.
+	lastSaved []byte
+}
+
+type pendingUpdate struct {
+	generation int
+	configs    []cdsserver.Config
+}
+
+// SetSnapshotStore enables persisting the last acknowledged configs. Must be called before
+// Start.
+func (c *Server) SetSnapshotStore(s SnapshotStore) {
+	c.snapshots = s
+}
+
+// loadSnapshot serves the configs from the snapshot store until the first fresh update is
+// acknowledged.
+func (c *Server) loadSnapshot(ctx context.Context) {
+	log := c.log.WithValues("store", c.snapshots.String())
+
+	snapshot, err := c.snapshots.Load(ctx)
+	if err != nil {
+		log.Error(err, "Could not load config discovery snapshot")
+		return
+	}
+	if snapshot == nil {
+		log.Info("No config discovery snapshot found")
+		return
+	}
+
+	configs := []cdsserver.Config{}
+	for _, conf := range snapshot.Configs {
+		if conf == nil {
+			continue
+		}
+		if namespace, name, ok := cdsserver.NamespacedName(conf.Admin.Name); ok {
+			configs = append(configs, cdsserver.Config{
+				Name:      name,
+				Namespace: namespace,
+				Config:    conf,
+			})
+		}
+	}
+
+	if err := c.updateConfig(configs); err != nil {
+		log.Error(err, "Could not serve config discovery snapshot")
+		return
+	}
+
+	c.snapshotState.serving = true
+	log.Info("Serving config discovery snapshot", "generation", snapshot.Generation,
+		"configs", len(configs))
+}
+
+// processSnapshotUpdate records an update until it is acknowledged. While the snapshot is
+// served the update is not applied, so that clients do not receive a partial view during the
+// first render after a restart.
+func (c *Server) processSnapshotUpdate(ctx context.Context, e *event.EventUpdate, configs []cdsserver.Config) error {
+	s := &c.snapshotState
+	if !s.serving {
+		if err := c.updateConfig(configs); err != nil {
+			return err
+		}
+	}
+
+	s.pending = &pendingUpdate{generation: e.Generation, configs: configs}
+
+	// updates that will never be acknowledged are committed right away
+	if !e.GetRequestAck() || e.Generation <= s.lastAcked {
+		return c.commitSnapshot(ctx)
+	}
+
+	return nil
+}
+
+// ProcessAck commits the pending update if it has been acknowledged.
+func (c *Server) ProcessAck(e *event.EventAck) {
+	s := &c.snapshotState
+	if e.Generation > s.lastAcked {
+		s.lastAcked = e.Generation
+	}
+
+	if c.snapshots == nil || s.pending == nil || s.pending.generation > s.lastAcked {
+		return
+	}
+
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := c.commitSnapshot(ctx); err != nil {
+		c.log.Error(err, "Could not commit config discovery snapshot", "generation",
+			e.Generation)
+	}
+}
+
+// commitSnapshot switches over from the snapshot to the pending update, if needed, and
+// persists the pending update.
+func (c *Server) commitSnapshot(ctx context.Context) error {
+	s := &c.snapshotState
+	p := s.pending
+	s.pending = nil
+
+	if s.serving {
+		if err := c.updateConfig(p.configs); err != nil {
+			return err
+		}
+		s.serving = false
+		c.log.Info("Switched over from the config discovery snapshot", "generation",
+			p.generation)
+	}
+
+	snapshot := &Snapshot{Generation: p.generation, Configs: []*stnrv1.StunnerConfig{}}
+	for _, conf := range p.configs {
+		snapshot.Configs = append(snapshot.Configs, conf.Config)
+	}
+
+	// skip the write if only the generation has changed
+	data, err := json.Marshal(snapshot.Configs)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, s.lastSaved) {
+		return nil
+	}
+
+	if err := c.snapshots.Save(ctx, snapshot); err != nil {
+		return fmt.Errorf("could not save config discovery snapshot to %s: %w",
+			c.snapshots.String(), err)
+	}
+	s.lastSaved = data
+
+	c.log.V(1).Info("Saved config discovery snapshot", "generation", p.generation,
+		"configs", len(snapshot.Configs))
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+	cdsserver "github.com/l7mp/stunner/pkg/config/server"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+func snapshotConfig(namespace, name, realm string) *stnrv1.StunnerConfig {
+	return &stnrv1.StunnerConfig{
+		ApiVersion: stnrv1.ApiVersion,
+		Admin:      stnrv1.AdminConfig{Name: namespace + "/" + name},
+		Auth:       stnrv1.AuthConfig{Realm: realm},
+	}
+}
+
+func servedRealms(c *Server) map[string]string {
+	ret := map[string]string{}
+	for _, conf := range c.GetConfigs() {
+		ret[conf.Namespace+"/"+conf.Name] = conf.Config.Auth.Realm
+	}
+	return ret
+}
+
+func TestSnapshotStore(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	fileStore, err := NewSnapshotStore("file:"+filepath.Join(t.TempDir(), "snapshot.json"), c, c)
+	require.NoError(t, err, "file store")
+	secretStore, err := NewSnapshotStore("secret:testnamespace/cds-snapshot", c, c)
+	require.NoError(t, err, "secret store")
+
+	for _, s := range []SnapshotStore{fileStore, secretStore} {
+		t.Run(s.String(), func(t *testing.T) {
+			ctx := context.Background()
+			snapshot, err := s.Load(ctx)
+			assert.NoError(t, err, "load empty")
+			assert.Nil(t, snapshot, "no snapshot")
+
+			for gen, realm := range []string{"realm-1", "realm-2"} {
+				err := s.Save(ctx, &Snapshot{Generation: gen, Configs: []*stnrv1.StunnerConfig{
+					snapshotConfig("testnamespace", "gateway-1", realm)}})
+				require.NoError(t, err, "save")
+
+				snapshot, err = s.Load(ctx)
+				require.NoError(t, err, "load")
+				require.NotNil(t, snapshot, "snapshot")
+				assert.Equal(t, gen, snapshot.Generation, "generation")
+				require.Len(t, snapshot.Configs, 1, "configs")
+				assert.Equal(t, realm, snapshot.Configs[0].Auth.Realm, "config")
+			}
+		})
+	}
+
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+		Namespace: "testnamespace", Name: "cds-snapshot"}, secret), "snapshot secret")
+	assert.Contains(t, secret.Data, opdefault.DefaultCDSSnapshotKey, "snapshot key")
+	assert.Equal(t, corev1.SecretTypeOpaque, secret.Type, "secret type")
+	assert.Empty(t, secret.GetLabels(), "not owned by the operator")
+
+	// the snapshot is never written to a ConfigMap
+	cms := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(context.Background(), cms), "list configmaps")
+	assert.Empty(t, cms.Items, "no configmap")
+
+	// the snapshot file is readable only by the operator
+	info, err := os.Stat(strings.TrimPrefix(fileStore.String(), "file:"))
+	require.NoError(t, err, "stat snapshot file")
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "snapshot file mode")
+
+	for _, spec := range []string{"", "dummy", "file:", "secret:testnamespace",
+		"secret:/cds-snapshot", "configmap:testnamespace/cds-snapshot"} {
+		_, err := NewSnapshotStore(spec, c, c)
+		assert.Error(t, err, "invalid spec %q", spec)
+	}
+}
+
+func TestServerSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json"))
+	require.NoError(t, store.Save(ctx, &Snapshot{Generation: 3, Configs: []*stnrv1.StunnerConfig{
+		snapshotConfig("testnamespace", "gateway-1", "snapshot"),
+		snapshotConfig("testnamespace", "gateway-2", "snapshot"),
+	}}), "save")
+
+	c := &Server{
+		Server: cdsserver.New("", nil, logr.Discard()),
+		sync:   newSyncTracker(),
+		log:    logr.Discard(),
+	}
+	c.SetSnapshotStore(store)
+
+	// the snapshot is served at startup
+	c.loadSnapshot(ctx)
+	assert.Equal(t, map[string]string{"testnamespace/gateway-1": "snapshot",
+		"testnamespace/gateway-2": "snapshot"}, servedRealms(c), "snapshot served")
+
+	// an unacknowledged render does not replace the snapshot
+	e := event.NewEventUpdate(1)
+	e.ConfigQueue = []*stnrv1.StunnerConfig{snapshotConfig("testnamespace", "gateway-1", "fresh")}
+	e.SetRequestAck(true)
+	require.NoError(t, c.ProcessUpdate(e), "update")
+	assert.Equal(t, "snapshot", servedRealms(c)["testnamespace/gateway-1"], "snapshot still served")
+
+	// stale acks are ignored
+	c.ProcessAck(event.NewEventAck(0))
+	assert.Equal(t, "snapshot", servedRealms(c)["testnamespace/gateway-1"], "snapshot still served")
+
+	// switch over once the render is acked
+	c.ProcessAck(event.NewEventAck(1))
+	assert.Equal(t, map[string]string{"testnamespace/gateway-1": "fresh"}, servedRealms(c),
+		"switched over")
+
+	snapshot, err := store.Load(ctx)
+	require.NoError(t, err, "load")
+	assert.Equal(t, 1, snapshot.Generation, "snapshot generation")
+	require.Len(t, snapshot.Configs, 1, "snapshot configs")
+	assert.Equal(t, "fresh", snapshot.Configs[0].Auth.Realm, "snapshot config")
+
+	// later updates are served immediately, but persisted only when acked
+	e = event.NewEventUpdate(2)
+	e.ConfigQueue = []*stnrv1.StunnerConfig{snapshotConfig("testnamespace", "gateway-1", "next")}
+	e.SetRequestAck(true)
+	require.NoError(t, c.ProcessUpdate(e), "update")
+	assert.Equal(t, "next", servedRealms(c)["testnamespace/gateway-1"], "update served")
+	snapshot, err = store.Load(ctx)
+	require.NoError(t, err, "load")
+	assert.Equal(t, "fresh", snapshot.Configs[0].Auth.Realm, "unacked update not persisted")
+
+	c.ProcessAck(event.NewEventAck(2))
+	snapshot, err = store.Load(ctx)
+	require.NoError(t, err, "load")
+	assert.Equal(t, 2, snapshot.Generation, "snapshot generation")
+	assert.Equal(t, "next", snapshot.Configs[0].Auth.Realm, "acked update persisted")
+
+	// updates that are never acked are persisted right away
+	e = event.NewEventUpdate(3)
+	e.ConfigQueue = []*stnrv1.StunnerConfig{snapshotConfig("testnamespace", "gateway-1", "legacy")}
+	require.NoError(t, c.ProcessUpdate(e), "update")
+	snapshot, err = store.Load(ctx)
+	require.NoError(t, err, "load")
+	assert.Equal(t, "legacy", snapshot.Configs[0].Auth.Realm, "unacked update persisted")
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+	cdsserver "github.com/l7mp/stunner/pkg/config/server"
+)
+
+// SyncStatus reports how far the config discovery clients of a config are behind the latest
+// version of the config. Versions are numbered by the config discovery server and increase with
+// each change of the config.
+type SyncStatus struct {
+	// Version is the latest version of the config.
+	Version int
+	// Clients is the number of clients that have received the config.
+	Clients int
+	// Synced is the number of clients on the latest version.
+	Synced int
+	// OldestVersion is the oldest version still in use by a client.
+	OldestVersion int
+}
+
+// SyncStatusReporter reports the sync status of the config discovery clients.
+type SyncStatusReporter interface {
+	// GetSyncStatus returns the sync status for a config id, or false if the config is not
+	// served.
+	GetSyncStatus(id string) (SyncStatus, bool)
+}
+
+// clientNodeSeparator separates the node name from the client id in the node query parameter
+// the config discovery proxy passes to the config discovery server. Node names cannot contain
+// it.
+const clientNodeSeparator = "|"
+
+// syncClient is the config a connected config discovery client has last received, and its
+// version. The id is empty until the client receives a config of known version.
+type syncClient struct {
+	id      string
+	version int
+}
+
+type configVersion struct {
+	version int
+	config  *stnrv1.StunnerConfig
+}
+
+// syncTracker tracks the config versions delivered to the config discovery clients.
+type syncTracker struct {
+	// seq is the last version number handed out.
+	seq      int
+	versions map[string]configVersion
+	// clients is keyed by the client id, which identifies the connection of the client.
+	clients map[string]syncClient
+	// changed collects the config ids whose sync status has changed since the last call to
+	// takeChanged, and notifyCh is signaled when the set becomes non-empty.
+	changed  map[string]bool
+	notifyCh chan struct{}
+	lock     sync.RWMutex
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{
+		versions: map[string]configVersion{},
+		clients:  map[string]syncClient{},
+		changed:  map[string]bool{},
+		notifyCh: make(chan struct{}, 1),
+	}
+}
+
+// update registers the served configs. The version of a config is bumped only when its
+// content changes.
+func (t *syncTracker) update(configs []cdsserver.Config) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	served := map[string]bool{}
+	for _, c := range configs {
+		if c.Config == nil {
+			continue
+		}
+		id := c.Config.Admin.Name
+		served[id] = true
+
+		if v, ok := t.versions[id]; ok && v.config.DeepEqual(c.Config) {
+			continue
+		}
+		before, _ := t.status(id)
+		t.seq++
+		t.versions[id] = configVersion{version: t.seq, config: c.Config}
+		t.markChanged(id, before)
+	}
+
+	for id := range t.versions {
+		if served[id] {
+			continue
+		}
+		delete(t.versions, id)
+		for client, c := range t.clients {
+			if c.id == id {
+				t.clients[client] = syncClient{}
+			}
+		}
+	}
+}
+
+// connected registers a new client connection.
+func (t *syncTracker) connected(client string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.clients[client] = syncClient{}
+}
+
+// disconnected forgets a client whose connection has closed.
+func (t *syncTracker) disconnected(client string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	c, ok := t.clients[client]
+	if !ok {
+		return
+	}
+
+	before, _ := t.status(c.id)
+	delete(t.clients, client)
+	if c.id != "" {
+		t.markChanged(c.id, before)
+	}
+}
+
+// delivered registers that a client has received a config. Called from the config patcher,
+// before the config is patched with the node address. Clients that are not connected are
+// ignored.
+func (t *syncTracker) delivered(conf *stnrv1.StunnerConfig, client string) {
+	if conf == nil {
+		return
+	}
+	id := conf.Admin.Name
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	last, ok := t.clients[client]
+	if !ok {
+		return
+	}
+
+	// a config that is no longer the latest one has an unknown version
+	v, ok := t.versions[id]
+	if !ok || !v.config.DeepEqual(conf) {
+		return
+	}
+
+	c := syncClient{id: id, version: v.version}
+	if last == c {
+		return
+	}
+
+	before, _ := t.status(id)
+	beforeLast, _ := t.status(last.id)
+	t.clients[client] = c
+	t.markChanged(id, before)
+	if last.id != "" && last.id != id {
+		t.markChanged(last.id, beforeLast)
+	}
+}
+
+func (t *syncTracker) GetSyncStatus(id string) (SyncStatus, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.status(id)
+}
+
+// status must be called with the lock held.
+func (t *syncTracker) status(id string) (SyncStatus, bool) {
+	v, ok := t.versions[id]
+	if !ok {
+		return SyncStatus{}, false
+	}
+
+	s := SyncStatus{Version: v.version, OldestVersion: v.version}
+	for _, c := range t.clients {
+		if c.id != id {
+			continue
+		}
+		s.Clients++
+		if c.version == v.version {
+			s.Synced++
+		}
+		if c.version < s.OldestVersion {
+			s.OldestVersion = c.version
+		}
+	}
+
+	return s, true
+}
+
+// markChanged must be called with the lock held.
+func (t *syncTracker) markChanged(id string, before SyncStatus) {
+	if after, _ := t.status(id); after == before {
+		return
+	}
+	t.changed[id] = true
+	t.notify()
+}
+
+func (t *syncTracker) notify() {
+	select {
+	case t.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// takeChanged returns and resets the config ids whose sync status has changed.
+func (t *syncTracker) takeChanged() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ids := make([]string, 0, len(t.changed))
+	for id := range t.changed {
+		ids = append(ids, id)
+	}
+	t.changed = map[string]bool{}
+
+	return ids
+}
+
+// clientNode encodes the client id into the node name.
+func clientNode(node, client string) string {
+	return node + clientNodeSeparator + client
+}
+
+// splitClientNode returns the node name and the client id encoded by clientNode. The client id
+// is empty if the node name carries none.
+func splitClientNode(s string) (string, string) {
+	node, client, _ := strings.Cut(s, clientNodeSeparator)
+	return node, client
+}
+
+// trackClients identifies each client connection by the remote address of the connection and
+// passes the client id to the config discovery server in the node query parameter, so that the
+// config patcher can attribute the delivered configs to the connection. The client is forgotten
+// when the request, which for a watching client lasts as long as its WebSocket connection,
+// completes.
+func (t *syncTracker) trackClients(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := r.RemoteAddr
+		q := r.URL.Query()
+		q.Set("node", clientNode(q.Get("node"), client))
+		r.URL.RawQuery = q.Encode()
+
+		t.connected(client)
+		defer t.disconnected(client)
+		next.ServeHTTP(w, r)
+	})
+}
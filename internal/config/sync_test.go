@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+	cdsserver "github.com/l7mp/stunner/pkg/config/server"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+)
+
+func syncConfigs(confs ...*stnrv1.StunnerConfig) []cdsserver.Config {
+	ret := []cdsserver.Config{}
+	for _, conf := range confs {
+		namespace, name, _ := cdsserver.NamespacedName(conf.Admin.Name)
+		ret = append(ret, cdsserver.Config{Namespace: namespace, Name: name, Config: conf})
+	}
+	return ret
+}
+
+func TestSyncTracker(t *testing.T) {
+	tr := newSyncTracker()
+	id := "testnamespace/gateway-1"
+
+	_, ok := tr.GetSyncStatus(id)
+	assert.False(t, ok, "unknown config")
+
+	c1 := snapshotConfig("testnamespace", "gateway-1", "realm-1")
+	tr.update(syncConfigs(c1))
+	s, ok := tr.GetSyncStatus(id)
+	require.True(t, ok, "config served")
+	assert.Equal(t, SyncStatus{Version: 1, OldestVersion: 1}, s, "no clients")
+	assert.ElementsMatch(t, []string{id}, tr.takeChanged(), "changed")
+
+	// clients that are not connected are ignored
+	tr.delivered(c1, "10.0.0.1:1000")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 0, s.Clients, "unknown client")
+
+	tr.connected("10.0.0.1:1000")
+	tr.connected("10.0.0.2:1000")
+	tr.delivered(c1, "10.0.0.1:1000")
+	tr.delivered(c1, "10.0.0.2:1000")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, SyncStatus{Version: 1, Clients: 2, Synced: 2, OldestVersion: 1}, s, "synced")
+	assert.ElementsMatch(t, []string{id}, tr.takeChanged(), "changed")
+
+	// an unchanged config keeps the version
+	tr.update(syncConfigs(snapshotConfig("testnamespace", "gateway-1", "realm-1")))
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 1, s.Version, "version")
+	assert.Empty(t, tr.takeChanged(), "not changed")
+
+	// a new version
+	c2 := snapshotConfig("testnamespace", "gateway-1", "realm-2")
+	tr.update(syncConfigs(c2))
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, SyncStatus{Version: 2, Clients: 2, Synced: 0, OldestVersion: 1}, s, "pending")
+
+	// the old config is still delivered to a late client: version unknown
+	tr.connected("10.0.0.3:1000")
+	tr.delivered(c1, "10.0.0.3:1000")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 2, s.Clients, "unknown version ignored")
+
+	tr.delivered(c2, "10.0.0.1:1000")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, SyncStatus{Version: 2, Clients: 2, Synced: 1, OldestVersion: 1}, s, "partially synced")
+	assert.ElementsMatch(t, []string{id}, tr.takeChanged(), "changed")
+
+	// re-delivery does not change the status
+	tr.delivered(c2, "10.0.0.1:1000")
+	assert.Empty(t, tr.takeChanged(), "not changed")
+
+	// a closed connection forgets the client
+	tr.disconnected("10.0.0.2:1000")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, SyncStatus{Version: 2, Clients: 1, Synced: 1, OldestVersion: 2}, s, "synced")
+	assert.ElementsMatch(t, []string{id}, tr.takeChanged(), "changed")
+
+	// a client reconnecting from the same node is a new client
+	tr.connected("10.0.0.1:1001")
+	tr.delivered(c2, "10.0.0.1:1001")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 2, s.Clients, "reconnected")
+	tr.disconnected("10.0.0.1:1001")
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 1, s.Clients, "disconnected")
+
+	// deleting the config forgets the clients
+	tr.update(syncConfigs())
+	_, ok = tr.GetSyncStatus(id)
+	assert.False(t, ok, "config deleted")
+	tr.update(syncConfigs(c2))
+	s, _ = tr.GetSyncStatus(id)
+	assert.Equal(t, 0, s.Clients, "clients forgotten")
+}
+
+func TestServerReportSyncStatus(t *testing.T) {
+	mode := DataplaneMode
+	DataplaneMode = DataplaneModeManaged
+	defer func() { DataplaneMode = mode }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := NewCDSServer("127.0.0.1:0", nil, logr.Discard())
+	require.NoError(t, err, "CDS server")
+	ch := make(chan event.Event, 10)
+	c.SetOperatorChannel(event.NewEventChannel(ch))
+	require.NoError(t, c.Start(ctx), "start")
+
+	e := event.NewEventUpdate(1)
+	conf := snapshotConfig("testnamespace", "gateway-1", "realm-1")
+	e.ConfigQueue = []*stnrv1.StunnerConfig{conf}
+	c.GetConfigUpdateChannel() <- e
+
+	// a new config changes the sync status
+	re := (<-ch).(*event.EventReconcile)
+	assert.Equal(t, []event.ObjectKey{{Kind: "Gateway", NamespacedName: types.NamespacedName{
+		Namespace: "testnamespace", Name: "gateway-1"}}}, re.Changes, "changes")
+
+	// so does a client picking up the config
+	c.sync.connected("10.0.0.1:1000")
+	c.sync.delivered(conf, "10.0.0.1:1000")
+	re = (<-ch).(*event.EventReconcile)
+	assert.Len(t, re.Changes, 1, "changes")
+
+	s, ok := c.GetSyncStatus("testnamespace/gateway-1")
+	require.True(t, ok, "config served")
+	assert.Equal(t, 1, s.Synced, "synced")
+}
+
+func TestSyncTrackClients(t *testing.T) {
+	tr := newSyncTracker()
+	id := "testnamespace/gateway-1"
+	conf := snapshotConfig("testnamespace", "gateway-1", "realm-1")
+	tr.update(syncConfigs(conf))
+
+	// the backend plays the config discovery server that calls the patcher
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node, client := splitClientNode(r.URL.Query().Get("node"))
+		assert.Equal(t, "node-1", node, "node")
+		assert.Equal(t, r.RemoteAddr, client, "client id")
+		tr.delivered(conf, client)
+
+		s, _ := tr.GetSyncStatus(id)
+		assert.Equal(t, 1, s.Synced, "client connected")
+	})
+
+	srv := httptest.NewServer(tr.trackClients(backend))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/api/v1/configs/testnamespace/gateway-1?node=node-1")
+	require.NoError(t, err, "get")
+	res.Body.Close()
+
+	s, _ := tr.GetSyncStatus(id)
+	assert.Equal(t, 0, s.Clients, "client disconnected")
+
+	node, client := splitClientNode("node-1")
+	assert.Equal(t, "node-1", node, "node")
+	assert.Empty(t, client, "no client id")
+}
@@ -1,6 +1,9 @@
 package config
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // DataplaneModeType species the type of the STUN/TURN authentication mechanism used by STUNner
 type DataplaneModeType int
@@ -38,3 +41,77 @@ func (a DataplaneModeType) String() string {
 		return "<unknown>"
 	}
 }
+
+// CDSAuthModeType specifies the authentication mode of the config discovery server.
+type CDSAuthModeType int
+
+const (
+	CDSAuthModeNone CDSAuthModeType = iota // default
+	CDSAuthModePod                         // source address of the dataplane pods
+)
+
+const (
+	cdsAuthModeNoneStr = "none"
+	cdsAuthModePodStr  = "pod"
+)
+
+// NewCDSAuthMode parses the config discovery authentication mode specification.
+func NewCDSAuthMode(raw string) (CDSAuthModeType, error) {
+	switch strings.ToLower(raw) {
+	case cdsAuthModeNoneStr:
+		return CDSAuthModeNone, nil
+	case cdsAuthModePodStr:
+		return CDSAuthModePod, nil
+	default:
+		return CDSAuthModeNone, fmt.Errorf("invalid config discovery authentication mode %q", raw)
+	}
+}
+
+// String returns a string representation for the config discovery authentication mode.
+func (a CDSAuthModeType) String() string {
+	switch a {
+	case CDSAuthModeNone:
+		return cdsAuthModeNoneStr
+	case CDSAuthModePod:
+		return cdsAuthModePodStr
+	default:
+		return "<unknown>"
+	}
+}
+
+// UpdateModeType specifies how the updater writes the resources to Kubernetes.
+type UpdateModeType int
+
+const (
+	UpdateModePatch UpdateModeType = iota // default
+	UpdateModeApply                       // server-side apply
+)
+
+const (
+	updateModePatchStr = "patch"
+	updateModeApplyStr = "apply"
+)
+
+// NewUpdateMode parses the update mode specification.
+func NewUpdateMode(raw string) (UpdateModeType, error) {
+	switch strings.ToLower(raw) {
+	case updateModePatchStr:
+		return UpdateModePatch, nil
+	case updateModeApplyStr:
+		return UpdateModeApply, nil
+	default:
+		return UpdateModePatch, fmt.Errorf("invalid update mode %q", raw)
+	}
+}
+
+// String returns a string representation for the update mode.
+func (a UpdateModeType) String() string {
+	switch a {
+	case UpdateModePatch:
+		return updateModePatchStr
+	case UpdateModeApply:
+		return updateModeApplyStr
+	default:
+		return "<unknown>"
+	}
+}
@@ -44,6 +44,17 @@ var (
 	// ConfigDiscoveryAddress is the default URI at which config discovery requests are served.
 	ConfigDiscoveryAddress = stnrv1.DefaultConfigDiscoveryAddress
 
+	// CDSAuthMode is the authentication mode of the config discovery server. When set to
+	// "pod", clients are identified by the dataplane pod that owns their source address and
+	// receive only the config of the Gateway the pod belongs to.
+	CDSAuthMode = CDSAuthModeNone
+
+	// UpdateMode specifies how the updater writes the resources to Kubernetes. In "patch" mode
+	// the updater reads the current object, applies the operator-owned fields and patches the
+	// difference. In "apply" mode the operator-owned fields are sent with server-side apply
+	// under a dedicated field manager, leaving the fields managed by other controllers alone.
+	UpdateMode = UpdateModePatch
+
 	// EndpointSliceAvailable is a global flag indicating whether EndpointSlices are available
 	// in the current cluster. This is detected in the UDPRoute controller trying to create a
 	// Watch for EndpointSlices. If successful, only EndpointSlices will be considered and
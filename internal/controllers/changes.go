@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+// changeTracker collects the keys of the objects that changed between two reconciliations, so
+// that the renderer can re-render only the affected Gateways.
+type changeTracker struct {
+	changes map[event.ObjectKey]bool
+	lock    sync.Mutex
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{changes: map[event.ObjectKey]bool{}}
+}
+
+// record adds an object to the change set.
+func (t *changeTracker) record(kind string, o client.Object) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.changes[event.ObjectKey{Kind: kind, NamespacedName: store.GetNamespacedName(o)}] = true
+}
+
+// drain returns the changes collected so far and resets the change set. Drain must be called
+// before the controller lists the objects, so that a change recorded after draining is
+// reconciled in the next round.
+func (t *changeTracker) drain() []event.ObjectKey {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ret := make([]event.ObjectKey, 0, len(t.changes))
+	for k := range t.changes {
+		ret = append(ret, k)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+
+	t.changes = map[event.ObjectKey]bool{}
+
+	return ret
+}
+
+// trackChanges returns a predicate that records each object in the change set and lets all
+// events pass. It must be the last predicate on a watch, so that only the objects that pass the
+// other predicates are recorded.
+func trackChanges[T client.Object](t *changeTracker, kind string) predicate.TypedPredicate[T] {
+	return predicate.NewTypedPredicateFuncs[T](func(o T) bool {
+		t.record(kind, o)
+		return true
+	})
+}
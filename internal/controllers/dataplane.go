@@ -39,6 +39,7 @@ import (
 type dataplaneReconciler struct {
 	client.Client
 	eventCh     event.EventChannel
+	changes     *changeTracker
 	terminating bool
 	log         logr.Logger
 }
@@ -47,6 +48,7 @@ func NewDataplaneController(mgr manager.Manager, ch event.EventChannel, log logr
 	r := &dataplaneReconciler{
 		Client:  mgr.GetClient(),
 		eventCh: ch,
+		changes: newChangeTracker(),
 		log:     log.WithName("dataplane-controller"),
 	}
 
@@ -64,7 +66,8 @@ func NewDataplaneController(mgr manager.Manager, ch event.EventChannel, log logr
 		source.Kind(mgr.GetCache(), &stnrgwv1.Dataplane{},
 			&handler.TypedEnqueueRequestForObject[*stnrgwv1.Dataplane]{},
 			// trigger when the Dataplane spec changes
-			predicate.TypedGenerationChangedPredicate[*stnrgwv1.Dataplane]{}),
+			predicate.TypedGenerationChangedPredicate[*stnrgwv1.Dataplane]{},
+			trackChanges[*stnrgwv1.Dataplane](r.changes, "Dataplane")),
 	); err != nil {
 		return nil, err
 	}
@@ -74,6 +77,9 @@ func NewDataplaneController(mgr manager.Manager, ch event.EventChannel, log logr
 }
 
 func (r *dataplaneReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "dataplane", req)
+	defer span.End()
+
 	log := r.log.WithValues("dataplane", req.String())
 
 	if r.terminating {
@@ -82,6 +88,7 @@ func (r *dataplaneReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 	}
 
 	log.Info("Reconciling")
+	changes := r.changes.drain()
 	dataplaneList := []client.Object{}
 
 	// find all Dataplanes
@@ -100,7 +107,7 @@ func (r *dataplaneReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 	store.Dataplanes.Reset(dataplaneList)
 	r.log.V(2).Info("Reset Dataplane store", "configs", store.Dataplanes.String())
 
-	r.eventCh.Channel() <- event.NewEventReconcile()
+	r.eventCh.Channel() <- newEventReconcile(ctx, changes...)
 
 	return reconcile.Result{}, nil
 }
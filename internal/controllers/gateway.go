@@ -12,7 +12,9 @@ import (
 
 	"github.com/go-logr/logr"
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
@@ -25,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
@@ -42,16 +45,19 @@ const (
 type gatewayReconciler struct {
 	client.Client
 	eventCh     event.EventChannel
+	changes     *changeTracker
 	terminating bool
 	log         logr.Logger
 }
 
-// NewGatewayController registers a reconciler for Gateway and the associated Secret objects.
+// NewGatewayController registers a reconciler for Gateway and the associated Secret and
+// ReferenceGrant objects.
 func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.Logger) (Controller, error) {
 	ctx := context.Background()
 	r := &gatewayReconciler{
 		Client:  mgr.GetClient(),
 		eventCh: ch,
+		changes: newChangeTracker(),
 		log:     log.WithName("gateway-controller"),
 	}
 
@@ -72,6 +78,7 @@ func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.L
 			predicate.And( // trigger when the spec changes on a GatewayClass we manage
 				predicate.NewTypedPredicateFuncs[*gwapiv1.GatewayClass](r.hasMatchingController),
 				predicate.TypedGenerationChangedPredicate[*gwapiv1.GatewayClass]{}),
+			trackChanges[*gwapiv1.GatewayClass](r.changes, "GatewayClass"),
 		),
 	); err != nil {
 		return nil, err
@@ -88,6 +95,7 @@ func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.L
 					predicate.TypedAnnotationChangedPredicate[*gwapiv1.Gateway]{},
 				),
 				predicate.NewTypedPredicateFuncs[*gwapiv1.Gateway](r.validateGatewayForReconcile)),
+			trackChanges[*gwapiv1.Gateway](r.changes, "Gateway"),
 		),
 	); err != nil {
 		return nil, err
@@ -110,18 +118,32 @@ func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.L
 	if err := c.Watch(
 		source.Kind(mgr.GetCache(), &corev1.Secret{},
 			&handler.TypedEnqueueRequestForObject[*corev1.Secret]{},
-			predicate.NewTypedPredicateFuncs[*corev1.Secret](r.validateSecretForReconcile)),
+			predicate.NewTypedPredicateFuncs[*corev1.Secret](r.validateSecretForReconcile),
+			trackChanges[*corev1.Secret](r.changes, "Secret")),
 	); err != nil {
 		return nil, err
 	}
 	r.log.Info("watching Secret objects")
 
+	// watch ReferenceGrant objects: any of these may allow or deny a cross-namespace reference
+	// from one of our Gateways, UDPRoutes or GatewayConfigs
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &gwapiv1b1.ReferenceGrant{},
+			&handler.TypedEnqueueRequestForObject[*gwapiv1b1.ReferenceGrant]{},
+			predicate.TypedGenerationChangedPredicate[*gwapiv1b1.ReferenceGrant]{},
+			trackChanges[*gwapiv1b1.ReferenceGrant](r.changes, "ReferenceGrant")),
+	); err != nil {
+		return nil, err
+	}
+	r.log.Info("Watching ReferenceGrant objects")
+
 	if config.DataplaneMode == config.DataplaneModeManaged {
 		// watch Deployment objects referenced by one of our Gateways
 		if err := c.Watch(
 			source.Kind(mgr.GetCache(), &appv1.Deployment{},
 				&handler.TypedEnqueueRequestForObject[*appv1.Deployment]{},
-				predicate.NewTypedPredicateFuncs[*appv1.Deployment](r.validateDeploymentForReconcile)),
+				predicate.NewTypedPredicateFuncs[*appv1.Deployment](r.validateDeploymentForReconcile),
+				trackChanges[*appv1.Deployment](r.changes, "Deployment")),
 		); err != nil {
 			return nil, err
 		}
@@ -131,11 +153,34 @@ func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.L
 		if err := c.Watch(
 			source.Kind(mgr.GetCache(), &appv1.DaemonSet{},
 				&handler.TypedEnqueueRequestForObject[*appv1.DaemonSet]{},
-				predicate.NewTypedPredicateFuncs[*appv1.DaemonSet](r.validateDaemonSetForReconcile)),
+				predicate.NewTypedPredicateFuncs[*appv1.DaemonSet](r.validateDaemonSetForReconcile),
+				trackChanges[*appv1.DaemonSet](r.changes, "DaemonSet")),
 		); err != nil {
 			return nil, err
 		}
 		r.log.Info("Watching dataplane DaemonSet objects")
+
+		// watch PodDisruptionBudget objects referenced by one of our Gateways
+		if err := c.Watch(
+			source.Kind(mgr.GetCache(), &policyv1.PodDisruptionBudget{},
+				&handler.TypedEnqueueRequestForObject[*policyv1.PodDisruptionBudget]{},
+				predicate.NewTypedPredicateFuncs[*policyv1.PodDisruptionBudget](r.validatePodDisruptionBudgetForReconcile),
+				trackChanges[*policyv1.PodDisruptionBudget](r.changes, "PodDisruptionBudget")),
+		); err != nil {
+			return nil, err
+		}
+		r.log.Info("Watching dataplane PodDisruptionBudget objects")
+
+		// watch HorizontalPodAutoscaler objects referenced by one of our Gateways
+		if err := c.Watch(
+			source.Kind(mgr.GetCache(), &autoscalingv2.HorizontalPodAutoscaler{},
+				&handler.TypedEnqueueRequestForObject[*autoscalingv2.HorizontalPodAutoscaler]{},
+				predicate.NewTypedPredicateFuncs[*autoscalingv2.HorizontalPodAutoscaler](r.validateHorizontalPodAutoscalerForReconcile),
+				trackChanges[*autoscalingv2.HorizontalPodAutoscaler](r.changes, "HorizontalPodAutoscaler")),
+		); err != nil {
+			return nil, err
+		}
+		r.log.Info("Watching dataplane HorizontalPodAutoscaler objects")
 	}
 
 	// NOTE: LoadBalancer Service resources are watched by the UDPRoute controller (together
@@ -144,9 +189,12 @@ func NewGatewayController(mgr manager.Manager, ch event.EventChannel, log logr.L
 	return r, nil
 }
 
-// Reconcile handles updates to a Gateway managed by this controller or a Secret referenced by one
-// of the Gateways managed by this controller.
+// Reconcile handles updates to a Gateway managed by this controller, a Secret referenced by one
+// of the Gateways managed by this controller, or a ReferenceGrant.
 func (r *gatewayReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "gateway", req)
+	defer span.End()
+
 	log := r.log.WithValues("resource", req.String())
 
 	if r.terminating {
@@ -155,11 +203,15 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req reconcile.Request
 	}
 
 	log.Info("Reconciling")
+	changes := r.changes.drain()
 	gatewayClassList := []client.Object{}
 	gatewayList := []client.Object{}
 	secretList := []client.Object{}
 	deploymentList := []client.Object{}
 	daemonSetList := []client.Object{}
+	pdbList := []client.Object{}
+	hpaList := []client.Object{}
+	referenceGrantList := []client.Object{}
 
 	// find Gateways managed by this controller
 	gwClasses := &gwapiv1.GatewayClassList{}
@@ -240,8 +292,7 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req reconcile.Request
 						continue
 					}
 
-					// TODO: check for ReferenceGrants
-
+					// ReferenceGrants are checked by the renderer
 					r.log.V(2).Info("found Secret", "name", store.GetObjectKey(&gc))
 					secretList = append(secretList, &secret)
 				}
@@ -257,12 +308,34 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req reconcile.Request
 
 				ds := &appv1.DaemonSet{}
 				if err := r.Get(context.Background(), resourceName, ds); err == nil {
-					daemonSetList = append(daemonSetList, dp)
+					daemonSetList = append(daemonSetList, ds)
+				}
+
+				pdb := &policyv1.PodDisruptionBudget{}
+				if err := r.Get(context.Background(), resourceName, pdb); err == nil {
+					pdbList = append(pdbList, pdb)
+				}
+
+				hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+				if err := r.Get(context.Background(), resourceName, hpa); err == nil {
+					hpaList = append(hpaList, hpa)
 				}
 			}
 		}
 	}
 
+	// find all ReferenceGrants
+	grants := &gwapiv1b1.ReferenceGrantList{}
+	if err := r.List(ctx, grants); err != nil {
+		r.log.Info("No ReferenceGrants found")
+	} else {
+		for _, rg := range grants.Items {
+			rg := rg
+			r.log.V(2).Info("Found ReferenceGrant", "name", store.GetObjectKey(&rg))
+			referenceGrantList = append(referenceGrantList, &rg)
+		}
+	}
+
 	store.GatewayClasses.Reset(gatewayClassList)
 	r.log.V(2).Info("reset GatewayClass store", "gateway-classes",
 		store.GatewayClasses.String())
@@ -279,7 +352,18 @@ func (r *gatewayReconciler) Reconcile(ctx context.Context, req reconcile.Request
 	store.DaemonSets.Reset(daemonSetList)
 	r.log.V(2).Info("reset DaemonSet store", "daemonSets", store.DaemonSets.String())
 
-	r.eventCh.Channel() <- event.NewEventReconcile()
+	store.PodDisruptionBudgets.Reset(pdbList)
+	r.log.V(2).Info("reset PodDisruptionBudget store", "pod-disruption-budgets",
+		store.PodDisruptionBudgets.String())
+
+	store.HorizontalPodAutoscalers.Reset(hpaList)
+	r.log.V(2).Info("reset HorizontalPodAutoscaler store", "horizontal-pod-autoscalers",
+		store.HorizontalPodAutoscalers.String())
+
+	store.ReferenceGrants.Reset(referenceGrantList)
+	r.log.V(2).Info("reset ReferenceGrant store", "reference-grants", store.ReferenceGrants.String())
+
+	r.eventCh.Channel() <- newEventReconcile(ctx, changes...)
 
 	return reconcile.Result{}, nil
 }
@@ -341,8 +425,17 @@ func (r *gatewayReconciler) validateDaemonSetForReconcile(daemonSet *appv1.Daemo
 	return r.validateDataplaneResourceForReconcile(daemonSet)
 }
 
+func (r *gatewayReconciler) validatePodDisruptionBudgetForReconcile(pdb *policyv1.PodDisruptionBudget) bool {
+	return r.validateDataplaneResourceForReconcile(pdb)
+}
+
+func (r *gatewayReconciler) validateHorizontalPodAutoscalerForReconcile(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return r.validateDataplaneResourceForReconcile(hpa)
+}
+
 // validateDataplaneResourceForReconcile checks whether there is a Gateway with the same name as
-// the dataplane resource (Deployment or DaemonSet) and the resource is owned by us.
+// the dataplane resource (Deployment, DaemonSet, PodDisruptionBudget or HorizontalPodAutoscaler)
+// and the resource is owned by us.
 func (r *gatewayReconciler) validateDataplaneResourceForReconcile(obj client.Object) bool {
 	// we don't watch dataplane resources in legacy mode
 	if config.DataplaneMode != config.DataplaneModeManaged {
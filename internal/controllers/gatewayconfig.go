@@ -96,6 +96,9 @@ func NewGatewayConfigController(mgr manager.Manager, ch event.EventChannel, log
 }
 
 func (r *gatewayConfigReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "gatewayconfig", req)
+	defer span.End()
+
 	log := r.log.WithValues("resource", req.String())
 
 	if r.terminating {
@@ -164,7 +167,7 @@ func (r *gatewayConfigReconciler) Reconcile(ctx context.Context, req reconcile.R
 	r.log.V(2).Info("Reset AuthSecret store", "secrets", store.AuthSecrets.String())
 
 	if !r.terminating {
-		r.eventCh.Channel() <- event.NewEventReconcile()
+		r.eventCh.Channel() <- newEventReconcile(ctx)
 	}
 
 	return reconcile.Result{}, nil
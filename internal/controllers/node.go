@@ -64,6 +64,9 @@ func NewNodeController(mgr manager.Manager, ch event.EventChannel, log logr.Logg
 }
 
 func (r *nodeReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "node", req)
+	defer span.End()
+
 	log := r.log.WithValues("node", req.String())
 
 	if r.terminating {
@@ -84,7 +87,7 @@ func (r *nodeReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 		log.Info("node removed: triggering reconcile")
 		store.Nodes.Remove(req.NamespacedName)
 
-		eventCh <- event.NewEventReconcile()
+		eventCh <- newEventReconcile(ctx)
 		return reconcile.Result{}, nil
 	}
 
@@ -93,7 +96,7 @@ func (r *nodeReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 		log.Info("node added: triggering reconcile")
 		store.Nodes.Upsert(node)
 
-		eventCh <- event.NewEventReconcile()
+		eventCh <- newEventReconcile(ctx)
 		return reconcile.Result{}, nil
 
 	}
@@ -107,7 +110,7 @@ func (r *nodeReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 	log.Info("node addresses changed: triggering reconcile")
 	store.Nodes.Upsert(node)
 
-	eventCh <- event.NewEventReconcile()
+	eventCh <- newEventReconcile(ctx)
 	return reconcile.Result{}, nil
 }
 
@@ -8,6 +8,12 @@ package controllers
 // +kubebuilder:rbac:groups=core,resources=nodes/status;services/status;endpoints/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=list
+
+// config discovery leader label and snapshot, in the operator namespace
+// +kubebuilder:rbac:groups=core,namespace=system,resources=pods,verbs=get;patch
+// +kubebuilder:rbac:groups=core,namespace=system,resources=secrets,verbs=create
+// +kubebuilder:rbac:groups=core,namespace=system,resources=secrets,resourceNames=stunner-cds-snapshot,verbs=get;update
 
 // apps
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
@@ -15,14 +21,24 @@ package controllers
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=daemonsets/status;daemonsets/finalizers,verbs=get;list;watch
 
+// autoscaling
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+
 // discovery.k8s.io
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices/status,verbs=get;list;watch
 
+// events.k8s.io
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // gateway.networking.k8s.io
-// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses;gateways;udproutes,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses/status;gateways/status;udproutes/status,verbs=update;patch
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses;gateways;udproutes;tcproutes;tlsroutes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gatewayclasses/status;gateways/status;udproutes/status;tcproutes/status;tlsroutes/status,verbs=update;patch
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=referencegrants,verbs=get;list;watch
+
+// policy
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // stunner.l7mp.io
 // +kubebuilder:rbac:groups="stunner.l7mp.io",resources=gatewayconfigs;staticservices;dataplanes;udproutes,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups="stunner.l7mp.io",resources=staticservices/finalizers;udproutes/finalizers;udproutes/status,verbs=update;patch
+// +kubebuilder:rbac:groups="stunner.l7mp.io",resources=dataplanes/status;gatewayconfigs/status;staticservices/finalizers;udproutes/finalizers;udproutes/status,verbs=update;patch
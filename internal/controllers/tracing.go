@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
+)
+
+// startReconcileSpan starts the root span of the trace of a reconciliation.
+func startReconcileSpan(ctx context.Context, controller string, req reconcile.Request) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("controller", controller),
+		attribute.String("resource", req.String()),
+	))
+}
+
+// newEventReconcile creates a reconcile event that carries the trace context of ctx.
+func newEventReconcile(ctx context.Context, changes ...event.ObjectKey) *event.EventReconcile {
+	e := event.NewEventReconcile(changes...)
+	e.SetTraceContext(ctx)
+	return e
+}
@@ -11,6 +11,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -40,22 +41,35 @@ const (
 	serviceUDPRouteIndexV1A2       = "serviceUDPRouteIndexV1A2"
 	staticServiceUDPRouteIndex     = "staticServiceUDPRouteIndex"
 	staticServiceUDPRouteIndexV1A2 = "staticServiceUDPRouteIndexV1A2"
+	serviceTCPRouteIndex           = "serviceTCPRouteIndex"
+	staticServiceTCPRouteIndex     = "staticServiceTCPRouteIndex"
+	serviceTLSRouteIndex           = "serviceTLSRouteIndex"
+	staticServiceTLSRouteIndex     = "staticServiceTLSRouteIndex"
 )
 
 type udpRouteReconciler struct {
 	client.Client
 	eventCh       event.EventChannel
+	changes       *changeTracker
 	terminating   bool
 	skipGwapiv1a2 bool
+	skipRoutes    map[string]bool
 	log           logr.Logger
 }
 
+// routeObjects collects the objects referenced by the routes during a reconciliation.
+type routeObjects struct {
+	namespaces, svcs, ssvcs, endpoints []client.Object
+}
+
 func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.Logger) (Controller, error) {
 	ctx := context.Background()
 	r := &udpRouteReconciler{
-		Client:  mgr.GetClient(),
-		eventCh: ch,
-		log:     log.WithName("udproute-controller"),
+		Client:     mgr.GetClient(),
+		eventCh:    ch,
+		skipRoutes: map[string]bool{},
+		changes:    newChangeTracker(),
+		log:        log.WithName("udproute-controller"),
 	}
 
 	c, err := controller.New("udproute", mgr, controller.Options{Reconciler: r})
@@ -72,7 +86,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 	if err := c.Watch(
 		source.Kind(mgr.GetCache(), &stnrgwv1.UDPRoute{},
 			&handler.TypedEnqueueRequestForObject[*stnrgwv1.UDPRoute]{},
-			predicate.TypedGenerationChangedPredicate[*stnrgwv1.UDPRoute]{}),
+			predicate.TypedGenerationChangedPredicate[*stnrgwv1.UDPRoute]{},
+			trackChanges[*stnrgwv1.UDPRoute](r.changes, "UDPRoute")),
 	); err != nil {
 		return nil, err
 	}
@@ -91,7 +106,7 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 	}
 
 	// watch UDPRouteV1A2 objects only when the CRD is loaded
-	udpRouteV1A2Loaded, err := r.isUDPRouteV1A2Loaded(mgr)
+	udpRouteV1A2Loaded, err := isRouteCRDLoaded(mgr, &gwapiv1a2.UDPRoute{}, "udproutes")
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +116,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 		if err := c.Watch(
 			source.Kind(mgr.GetCache(), &gwapiv1a2.UDPRoute{},
 				&handler.TypedEnqueueRequestForObject[*gwapiv1a2.UDPRoute]{},
-				predicate.TypedGenerationChangedPredicate[*gwapiv1a2.UDPRoute]{}),
+				predicate.TypedGenerationChangedPredicate[*gwapiv1a2.UDPRoute]{},
+				trackChanges[*gwapiv1a2.UDPRoute](r.changes, "UDPRoute")),
 		); err != nil {
 			return nil, err
 		}
@@ -123,6 +139,16 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 		r.log.V(1).Info("Gateway API v1alpha2 UDPRoute CRD not available, skipping")
 	}
 
+	// TCPRoutes and TLSRoutes are watched by this controller as well: the route controller
+	// is responsible for loading the backends of all routes
+	if err := watchV1A2Routes(ctx, r, mgr, c, tcpRouteKind); err != nil {
+		return nil, err
+	}
+
+	if err := watchV1A2Routes(ctx, r, mgr, c, tlsRouteKind); err != nil {
+		return nil, err
+	}
+
 	// a label-selector predicate to select the loadbalancer services we are interested in
 	// loadBalancerPredicate, err := predicate.LabelSelectorPredicate(
 	loadBalancerPredicate, err := ServiceLabelSelectorPredicate(
@@ -146,7 +172,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 			// related-service for a gateway) or a backend-service changes
 			predicate.Or(
 				predicate.NewTypedPredicateFuncs[*v1.Service](r.validateBackendServiceForReconcile),
-				loadBalancerPredicate)),
+				loadBalancerPredicate),
+			trackChanges[*v1.Service](r.changes, "Service")),
 	); err != nil {
 		return nil, err
 	}
@@ -160,7 +187,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 			if err := c.Watch(
 				source.Kind(mgr.GetCache(), &discoveryv1.EndpointSlice{},
 					&handler.TypedEnqueueRequestForObject[*discoveryv1.EndpointSlice]{},
-					predicate.NewTypedPredicateFuncs[*discoveryv1.EndpointSlice](r.validateEndpointSliceForReconcile)),
+					predicate.NewTypedPredicateFuncs[*discoveryv1.EndpointSlice](r.validateEndpointSliceForReconcile),
+					trackChanges[*discoveryv1.EndpointSlice](r.changes, "EndpointSlice")),
 			); err == nil {
 				r.log.Info("Watching EndpointSlice objects")
 				config.EndpointSliceAvailable = true
@@ -178,7 +206,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 				//nolint:staticcheck
 				source.Kind(mgr.GetCache(), &v1.Endpoints{},
 					&handler.TypedEnqueueRequestForObject[*v1.Endpoints]{},
-					predicate.NewTypedPredicateFuncs[*v1.Endpoints](r.validateBackendEndpointsForReconcile)),
+					predicate.NewTypedPredicateFuncs[*v1.Endpoints](r.validateBackendEndpointsForReconcile),
+					trackChanges[*v1.Endpoints](r.changes, "Endpoints")),
 			); err != nil {
 				return nil, err
 			}
@@ -192,7 +221,8 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 	if err := c.Watch(
 		source.Kind(mgr.GetCache(), &stnrgwv1.StaticService{},
 			&handler.TypedEnqueueRequestForObject[*stnrgwv1.StaticService]{},
-			predicate.NewTypedPredicateFuncs[*stnrgwv1.StaticService](r.validateStaticServiceForReconcile)),
+			predicate.NewTypedPredicateFuncs[*stnrgwv1.StaticService](r.validateStaticServiceForReconcile),
+			trackChanges[*stnrgwv1.StaticService](r.changes, "StaticService")),
 	); err != nil {
 		return nil, err
 	}
@@ -201,8 +231,11 @@ func NewUDPRouteController(mgr manager.Manager, ch event.EventChannel, log logr.
 	return r, nil
 }
 
-// Reconcile handles an update to a UDPRoute or a Service/Endpoints referenced by an UDPRoute.
+// Reconcile handles an update to a route or a Service/Endpoints referenced by a route.
 func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := startReconcileSpan(ctx, "udproute", req)
+	defer span.End()
+
 	log := r.log.WithValues("resource", req.String())
 
 	if r.terminating {
@@ -211,12 +244,10 @@ func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reques
 	}
 
 	log.Info("Reconciling")
+	changes := r.changes.drain()
 	routeList := []client.Object{}
 	routeListV1A2 := []client.Object{}
-	namespaceList := []client.Object{}
-	svcList := []client.Object{}
-	ssvcList := []client.Object{}
-	endpointList := []client.Object{}
+	objs := routeObjects{}
 
 	// find all related-services that we use as LoadBalancers for Gateways (i.e., have label
 	// "app:stunner")
@@ -225,7 +256,7 @@ func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reques
 	if err == nil {
 		for _, svc := range svcs.Items {
 			svc := svc
-			svcList = append(svcList, &svc)
+			objs.svcs = append(objs.svcs, &svc)
 		}
 	}
 
@@ -239,54 +270,7 @@ func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reques
 			r.log.V(1).Info("Processing UDPRoute", "name", store.GetObjectKey(&udproute))
 
 			routeList = append(routeList, &udproute)
-
-			for _, rule := range udproute.Spec.Rules {
-				for _, ref := range rule.BackendRefs {
-					ref := ref
-
-					// is this a static service?
-					if store.IsReferenceStaticService(&ref) {
-						if svc := r.getStaticServiceForBackend(ctx, &udproute, &ref); svc != nil {
-							ssvcList = append(ssvcList, svc)
-						}
-						continue
-					}
-
-					if store.IsReferenceService(&ref) {
-						if svc := r.getServiceForBackend(ctx, &udproute, &ref); svc != nil {
-							r.log.V(2).Info("Found service for UDPRoute backend ref",
-								"udproute", store.GetObjectKey(&udproute),
-								"ref", store.DumpBackendRef(&ref),
-								"svc", store.GetObjectKey(svc))
-							svcList = append(svcList, svc)
-						}
-
-						if config.EnableEndpointDiscovery {
-							if config.EndpointSliceAvailable {
-								es := r.getEndpointSlicesForBackend(ctx, &udproute, &ref)
-								endpointList = append(endpointList, es...)
-							} else {
-								if e := r.getEndpointsForBackend(ctx, &udproute, &ref); e != nil {
-									endpointList = append(endpointList, e)
-								}
-							}
-						}
-
-						continue
-					}
-				}
-			}
-
-			nsName := udproute.GetNamespace()
-			r.log.V(2).Info("Looking for the namespace of UDPRoute", "name", nsName)
-			namespace := v1.Namespace{}
-			if err := r.Get(ctx, types.NamespacedName{Name: nsName}, &namespace); err != nil {
-				r.log.Error(err, "Error getting namespace for UDPRoute", "udproute",
-					store.GetObjectKey(&udproute), "namespace-name", nsName)
-				continue
-			}
-
-			namespaceList = append(namespaceList, &namespace)
+			r.collectRouteObjects(ctx, &udproute, &objs)
 		}
 	}
 
@@ -303,53 +287,19 @@ func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reques
 			r.log.V(1).Info("Processing UDPRouteV1A2", "name", store.GetObjectKey(udproute))
 
 			routeListV1A2 = append(routeListV1A2, udproute)
+			r.collectRouteObjects(ctx, udproute, &objs)
+		}
+	}
 
-			for _, rule := range udproute.Spec.Rules {
-				for _, ref := range rule.BackendRefs {
-					ref := ref
-
-					// is this a static service?
-					if store.IsReferenceStaticService(&ref) {
-						if svc := r.getStaticServiceForBackend(ctx, udproute, &ref); svc != nil {
-							ssvcList = append(ssvcList, svc)
-						}
-						continue
-					}
-
-					if store.IsReferenceService(&ref) {
-						if svc := r.getServiceForBackend(ctx, udproute, &ref); svc != nil {
-							svcList = append(svcList, svc)
-						}
-
-						if config.EnableEndpointDiscovery {
-							if config.EndpointSliceAvailable {
-								es := r.getEndpointSlicesForBackend(ctx, udproute, &ref)
-								if len(es) > 0 {
-									endpointList = append(endpointList, es...)
-								}
-							} else {
-								if e := r.getEndpointsForBackend(ctx, udproute, &ref); e != nil {
-									endpointList = append(endpointList, e)
-								}
-							}
-						}
-
-						continue
-					}
-				}
-			}
-
-			nsName := udproute.GetNamespace()
-			r.log.V(2).Info("Looking for the namespace of UDPRoute", "name", nsName)
-			namespace := v1.Namespace{}
-			if err := r.Get(ctx, types.NamespacedName{Name: nsName}, &namespace); err != nil {
-				r.log.Error(err, "Error getting namespace for UDPRoute", "udproute",
-					store.GetObjectKey(udproute), "namespace-name", nsName)
-				continue
-			}
+	// find all TCPRoutes and TLSRoutes and convert to our own UDPRoute format
+	tcpRouteList, err := listV1A2Routes(ctx, r, tcpRouteKind, &objs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
-			namespaceList = append(namespaceList, &namespace)
-		}
+	tlsRouteList, err := listV1A2Routes(ctx, r, tlsRouteKind, &objs)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	store.UDPRoutes.Reset(routeList)
@@ -358,80 +308,140 @@ func (r *udpRouteReconciler) Reconcile(ctx context.Context, req reconcile.Reques
 	store.UDPRoutesV1A2.Reset(routeListV1A2)
 	r.log.V(2).Info("Reset UDPRoute V1A2 store", "udproutes", store.UDPRoutesV1A2.String())
 
-	store.Namespaces.Reset(namespaceList)
+	store.TCPRoutes.Reset(tcpRouteList)
+	r.log.V(2).Info("Reset TCPRoute store", "tcproutes", store.TCPRoutes.String())
+
+	store.TLSRoutes.Reset(tlsRouteList)
+	r.log.V(2).Info("Reset TLSRoute store", "tlsroutes", store.TLSRoutes.String())
+
+	store.Namespaces.Reset(objs.namespaces)
 	r.log.V(2).Info("Reset Namespace store", "namespaces", store.Namespaces.String())
 
-	store.Services.Reset(svcList)
+	store.Services.Reset(objs.svcs)
 	r.log.V(2).Info("Reset Service store", "services", store.Services.String())
 
 	if config.EndpointSliceAvailable {
-		store.EndpointSlices.Reset(endpointList)
+		store.EndpointSlices.Reset(objs.endpoints)
 		r.log.V(2).Info("Reset EndpointSlice store", "endpointslices", store.EndpointSlices.String())
 	} else {
-		store.Endpoints.Reset(endpointList)
+		store.Endpoints.Reset(objs.endpoints)
 		r.log.V(2).Info("Reset Endpoints store", "endpoints", store.Endpoints.String())
 	}
 
-	store.StaticServices.Reset(ssvcList)
+	store.StaticServices.Reset(objs.ssvcs)
 	r.log.V(2).Info("Reset StaticService store", "static-services", store.StaticServices.String())
 
-	r.eventCh.Channel() <- event.NewEventReconcile()
+	r.eventCh.Channel() <- newEventReconcile(ctx, changes...)
 
 	return reconcile.Result{}, nil
 }
 
+// collectRouteObjects finds the backend Services, StaticServices and Endpoints/EndpointSlices,
+// and the Namespace of a route.
+func (r *udpRouteReconciler) collectRouteObjects(ctx context.Context, ro *stnrgwv1.UDPRoute, objs *routeObjects) {
+	for _, rule := range ro.Spec.Rules {
+		for _, ref := range rule.BackendRefs {
+			ref := ref
+
+			// is this a static service?
+			if store.IsReferenceStaticService(&ref) {
+				if svc := r.getStaticServiceForBackend(ctx, ro, &ref); svc != nil {
+					objs.ssvcs = append(objs.ssvcs, svc)
+				}
+				continue
+			}
+
+			if store.IsReferenceService(&ref) {
+				if svc := r.getServiceForBackend(ctx, ro, &ref); svc != nil {
+					r.log.V(2).Info("Found service for route backend ref",
+						"route", store.GetObjectKey(ro),
+						"ref", store.DumpBackendRef(&ref),
+						"svc", store.GetObjectKey(svc))
+					objs.svcs = append(objs.svcs, svc)
+				}
+
+				if config.EnableEndpointDiscovery {
+					if config.EndpointSliceAvailable {
+						es := r.getEndpointSlicesForBackend(ctx, ro, &ref)
+						objs.endpoints = append(objs.endpoints, es...)
+					} else {
+						if e := r.getEndpointsForBackend(ctx, ro, &ref); e != nil {
+							objs.endpoints = append(objs.endpoints, e)
+						}
+					}
+				}
+
+				continue
+			}
+		}
+	}
+
+	nsName := ro.GetNamespace()
+	r.log.V(2).Info("Looking for the namespace of route", "name", nsName)
+	namespace := v1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nsName}, &namespace); err != nil {
+		r.log.Error(err, "Error getting namespace for route", "route",
+			store.GetObjectKey(ro), "namespace-name", nsName)
+		return
+	}
+
+	objs.namespaces = append(objs.namespaces, &namespace)
+}
+
 func (r *udpRouteReconciler) validateBackendServiceForReconcile(svc *v1.Service) bool {
-	return r.validateBackendForReconcile(store.GetObjectKey(svc), serviceUDPRouteIndex, serviceUDPRouteIndexV1A2)
+	return r.validateBackendForReconcile(store.GetObjectKey(svc), false)
 }
 
 func (r *udpRouteReconciler) validateStaticServiceForReconcile(staticSvc *stnrgwv1.StaticService) bool {
-	return r.validateBackendForReconcile(store.GetObjectKey(staticSvc),
-		staticServiceUDPRouteIndex, staticServiceUDPRouteIndexV1A2)
+	return r.validateBackendForReconcile(store.GetObjectKey(staticSvc), true)
 }
 
 //nolint:staticcheck
 func (r *udpRouteReconciler) validateBackendEndpointsForReconcile(e *v1.Endpoints) bool {
-	return r.validateBackendForReconcile(store.GetObjectKey(e), serviceUDPRouteIndex, serviceUDPRouteIndexV1A2)
+	return r.validateBackendForReconcile(store.GetObjectKey(e), false)
 }
 
-// validateBackendForReconcile checks whether the Service or StaticService belongs to a valid
-// UDPRoute. Uses the indexers in the argument.
-func (r *udpRouteReconciler) validateBackendForReconcile(key, index, indexV1A2 string) bool {
-	routeNum := 0
+// validateBackendForReconcile checks whether the Service or StaticService (if static is set)
+// belongs to a valid route.
+func (r *udpRouteReconciler) validateBackendForReconcile(key string, static bool) bool {
+	index, indexV1A2 := serviceUDPRouteIndex, serviceUDPRouteIndexV1A2
+	if static {
+		index, indexV1A2 = staticServiceUDPRouteIndex, staticServiceUDPRouteIndexV1A2
+	}
 
 	// find the routes referring to this service
-	routeList := &stnrgwv1.UDPRouteList{}
-	if err := r.List(context.Background(), routeList, &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(index, key),
-	}); err != nil {
-		r.log.Error(err, "Unable to find associated UDPRoute", "service", key)
-	} else {
-		routeNum += len(routeList.Items)
-	}
+	routeNum := r.countRoutesForBackend(&stnrgwv1.UDPRouteList{}, index, key)
 
 	if !r.skipGwapiv1a2 {
-		// find V1A2 routes referring to this service
-		routeListV1A2 := &gwapiv1a2.UDPRouteList{}
-		if err := r.List(context.Background(), routeListV1A2, &client.ListOptions{
-			FieldSelector: fields.OneTermEqualSelector(indexV1A2, key),
-		}); err != nil {
-			r.log.Error(err, "Unable to find associated UDPRouteV1A2", "service", key)
-		} else {
-			routeNum += len(routeListV1A2.Items)
-		}
+		routeNum += r.countRoutesForBackend(&gwapiv1a2.UDPRouteList{}, indexV1A2, key)
 	}
 
+	routeNum += countV1A2RoutesForBackend(r, tcpRouteKind, key, static)
+	routeNum += countV1A2RoutesForBackend(r, tlsRouteKind, key, static)
+
 	resStr := "not found"
 	if routeNum > 0 {
 		resStr = fmt.Sprintf("found %d routes", routeNum)
 	}
 
-	r.log.Info("Validating backend", "key", key, "index", fmt.Sprintf("%s/%s", index, indexV1A2),
-		"udproute", resStr)
+	r.log.Info("Validating backend", "key", key, "index", index, "route", resStr)
 
 	return routeNum != 0
 }
 
+// countRoutesForBackend returns the number of routes in the list that refer to a backend, as per
+// the given indexer.
+func (r *udpRouteReconciler) countRoutesForBackend(list client.ObjectList, index, key string) int {
+	if err := r.List(context.Background(), list, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(index, key),
+	}); err != nil {
+		r.log.Error(err, "Unable to find associated routes", "service", key, "index", index)
+		return 0
+	}
+
+	return meta.LenList(list)
+}
+
 // validateEndpointSliceForReconcile checks whether an EndpointSlice belongs to a Service that
 // belongs to a valid UDPRoute.
 func (r *udpRouteReconciler) validateEndpointSliceForReconcile(esl *discoveryv1.EndpointSlice) bool {
@@ -588,7 +598,8 @@ func (r *udpRouteReconciler) getStaticServiceForBackend(ctx context.Context, udp
 	return &svc
 }
 
-func (r *udpRouteReconciler) isUDPRouteV1A2Loaded(mgr manager.Manager) (bool, error) {
+// isRouteCRDLoaded checks whether the API server serves the resource of the given route type.
+func isRouteCRDLoaded(mgr manager.Manager, o client.Object, resourceName string) (bool, error) {
 	// Build a discovery client
 	d, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
 	if err != nil {
@@ -596,9 +607,9 @@ func (r *udpRouteReconciler) isUDPRouteV1A2Loaded(mgr manager.Manager) (bool, er
 	}
 
 	// Get the Groupversion
-	gvk, err := apiutil.GVKForObject(&gwapiv1a2.UDPRoute{}, mgr.GetScheme())
+	gvk, err := apiutil.GVKForObject(o, mgr.GetScheme())
 	if err != nil {
-		return false, fmt.Errorf("failed to get GVK for UDPRouteV1A2: %w", err)
+		return false, fmt.Errorf("failed to get GVK for %s: %w", resourceName, err)
 	}
 	gvStr := gvk.GroupVersion().String()
 
@@ -610,7 +621,6 @@ func (r *udpRouteReconciler) isUDPRouteV1A2Loaded(mgr manager.Manager) (bool, er
 		return false, fmt.Errorf("failed to get server resources for %s: %w", gvStr, err)
 	}
 
-	resourceName := "udproutes"
 	for _, r := range resList.APIResources {
 		if r.Name == resourceName {
 			return true, nil
@@ -620,14 +630,25 @@ func (r *udpRouteReconciler) isUDPRouteV1A2Loaded(mgr manager.Manager) (bool, er
 	return false, nil
 }
 
-func serviceUDPRouteIndexFunc(o client.Object) []string {
-	var udproute *stnrgwv1.UDPRoute
+// toCanonicalRoute converts a route of any supported kind to our own UDPRoute format.
+func toCanonicalRoute(o client.Object) *stnrgwv1.UDPRoute {
 	switch ro := o.(type) {
-	case *gwapiv1a2.UDPRoute:
-		udproute = stnrgwv1.ConvertV1A2UDPRouteToV1(ro)
 	case *stnrgwv1.UDPRoute:
-		udproute = ro
+		return ro
+	case *gwapiv1a2.UDPRoute:
+		return stnrgwv1.ConvertV1A2UDPRouteToV1(ro)
+	case *gwapiv1a2.TCPRoute:
+		return stnrgwv1.ConvertV1A2RouteToV1(ro)
+	case *gwapiv1a2.TLSRoute:
+		return stnrgwv1.ConvertV1A2RouteToV1(ro)
 	default:
+		return nil
+	}
+}
+
+func serviceUDPRouteIndexFunc(o client.Object) []string {
+	udproute := toCanonicalRoute(o)
+	if udproute == nil {
 		return []string{}
 	}
 
@@ -660,13 +681,8 @@ func serviceUDPRouteIndexFunc(o client.Object) []string {
 }
 
 func staticServiceUDPRouteIndexFunc(o client.Object) []string {
-	var udproute *stnrgwv1.UDPRoute
-	switch ro := o.(type) {
-	case *gwapiv1a2.UDPRoute:
-		udproute = stnrgwv1.ConvertV1A2UDPRouteToV1(ro)
-	case *stnrgwv1.UDPRoute:
-		udproute = ro
-	default:
+	udproute := toCanonicalRoute(o)
+	if udproute == nil {
 		return []string{}
 	}
 
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// v1a2RouteKind describes a Gateway API route kind that is watched by the UDPRoute controller
+// and converted to our own UDPRoute format.
+type v1a2RouteKind[R stnrgwv1.V1A2Route] struct {
+	// kind is the name of the route kind and resource is the name of the route resource.
+	kind, resource string
+	// serviceIndex and staticServiceIndex index the routes as per the referenced Services and
+	// StaticServices.
+	serviceIndex, staticServiceIndex string
+	newRoute                         func() R
+	newList                          func() client.ObjectList
+}
+
+var (
+	tcpRouteKind = v1a2RouteKind[*gwapiv1a2.TCPRoute]{
+		kind:               "TCPRoute",
+		resource:           "tcproutes",
+		serviceIndex:       serviceTCPRouteIndex,
+		staticServiceIndex: staticServiceTCPRouteIndex,
+		newRoute:           func() *gwapiv1a2.TCPRoute { return &gwapiv1a2.TCPRoute{} },
+		newList:            func() client.ObjectList { return &gwapiv1a2.TCPRouteList{} },
+	}
+
+	tlsRouteKind = v1a2RouteKind[*gwapiv1a2.TLSRoute]{
+		kind:               "TLSRoute",
+		resource:           "tlsroutes",
+		serviceIndex:       serviceTLSRouteIndex,
+		staticServiceIndex: staticServiceTLSRouteIndex,
+		newRoute:           func() *gwapiv1a2.TLSRoute { return &gwapiv1a2.TLSRoute{} },
+		newList:            func() client.ObjectList { return &gwapiv1a2.TLSRouteList{} },
+	}
+)
+
+// watchV1A2Routes sets up a watch for the routes of the given kind and indexes them as per the
+// referenced Services and StaticServices. The watch is skipped if the CRD of the route kind is
+// not loaded.
+func watchV1A2Routes[R stnrgwv1.V1A2Route](ctx context.Context, r *udpRouteReconciler, mgr manager.Manager, c controller.Controller, k v1a2RouteKind[R]) error {
+	loaded, err := isRouteCRDLoaded(mgr, k.newRoute(), k.resource)
+	if err != nil {
+		return err
+	}
+
+	if !loaded {
+		r.skipRoutes[k.kind] = true
+		r.log.V(1).Info("Gateway API route CRD not available, skipping", "kind", k.kind)
+		return nil
+	}
+
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), k.newRoute(),
+			&handler.TypedEnqueueRequestForObject[R]{},
+			predicate.TypedGenerationChangedPredicate[R]{},
+			trackChanges[R](r.changes, k.kind)),
+	); err != nil {
+		return err
+	}
+
+	// index the routes as per the referenced Services
+	if err := mgr.GetFieldIndexer().IndexField(ctx, k.newRoute(),
+		k.serviceIndex, serviceUDPRouteIndexFunc); err != nil {
+		return err
+	}
+
+	// index the routes as per the referenced StaticServices
+	if err := mgr.GetFieldIndexer().IndexField(ctx, k.newRoute(),
+		k.staticServiceIndex, staticServiceUDPRouteIndexFunc); err != nil {
+		return err
+	}
+
+	r.log.Info("Watching Gateway API route objects", "kind", k.kind)
+
+	return nil
+}
+
+// listV1A2Routes lists all routes of the given kind, converts them to our own UDPRoute format
+// and collects the objects they refer to.
+func listV1A2Routes[R stnrgwv1.V1A2Route](ctx context.Context, r *udpRouteReconciler, k v1a2RouteKind[R], objs *routeObjects) ([]client.Object, error) {
+	ret := []client.Object{}
+	if r.skipRoutes[k.kind] {
+		return ret, nil
+	}
+
+	list := k.newList()
+	if err := r.List(ctx, list); err != nil {
+		r.log.V(2).Info("No Gateway API route resources found", "kind", k.kind)
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range items {
+		route, ok := o.(R)
+		if !ok {
+			continue
+		}
+
+		ro := stnrgwv1.ConvertV1A2RouteToV1(route)
+		r.log.V(1).Info("Processing Gateway API route", "kind", k.kind, "name",
+			store.GetObjectKey(ro))
+
+		ret = append(ret, ro)
+		r.collectRouteObjects(ctx, ro, objs)
+	}
+
+	return ret, nil
+}
+
+// countV1A2RoutesForBackend returns the number of routes of the given kind that refer to a
+// Service or StaticService (if static is set).
+func countV1A2RoutesForBackend[R stnrgwv1.V1A2Route](r *udpRouteReconciler, k v1a2RouteKind[R], key string, static bool) int {
+	if r.skipRoutes[k.kind] {
+		return 0
+	}
+
+	index := k.serviceIndex
+	if static {
+		index = k.staticServiceIndex
+	}
+
+	return r.countRoutesForBackend(k.newList(), index, key)
+}
@@ -0,0 +1,160 @@
+// Package debug implements an HTTP endpoint that dumps the internal state of the operator: the
+// local object stores, the last rendered dataplane configs and the last update queue.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+// StatusReporter reports the state of the operator event loop.
+type StatusReporter interface {
+	// GetGeneration returns the current render generation.
+	GetGeneration() int
+	// GetLastAckedGeneration returns the last update generation acknowledged by the updater.
+	GetLastAckedGeneration() int
+	// GetLastUpdate returns the last update event, or nil if no update has been rendered yet.
+	GetLastUpdate() *event.EventUpdate
+}
+
+// Dump is the internal state of the operator served by the debug endpoint.
+type Dump struct {
+	Generation          int                              `json:"generation"`
+	LastAckedGeneration int                              `json:"lastAckedGeneration"`
+	Stores              map[string][]client.Object       `json:"stores"`
+	Configs             map[string]*stnrv1.StunnerConfig `json:"configs"`
+	UpdateQueue         *UpdateQueue                     `json:"updateQueue,omitempty"`
+}
+
+// UpdateQueue is the dump of an update event.
+type UpdateQueue struct {
+	Generation int                        `json:"generation"`
+	Upsert     map[string][]client.Object `json:"upsert"`
+	Delete     map[string][]client.Object `json:"delete"`
+}
+
+type handler struct {
+	reporter StatusReporter
+	log      logr.Logger
+}
+
+// NewHandler creates a new HTTP handler for the debug endpoint. Secrets are never dumped and the
+// credentials in the dataplane configs are redacted.
+func NewHandler(reporter StatusReporter, logger logr.Logger) http.Handler {
+	return &handler{reporter: reporter, log: logger.WithName("debug")}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	out, err := json.MarshalIndent(NewDump(h.reporter), "", "  ")
+	if err != nil {
+		h.log.Error(err, "cannot marshal debug dump")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(out); err != nil {
+		h.log.V(1).Info("cannot write debug dump", "error", err.Error())
+	}
+}
+
+// NewDump collects the internal state of the operator.
+func NewDump(reporter StatusReporter) *Dump {
+	d := &Dump{
+		Generation:          reporter.GetGeneration(),
+		LastAckedGeneration: reporter.GetLastAckedGeneration(),
+		Stores: map[string][]client.Object{
+			"GatewayClasses": dumpStore(store.GatewayClasses),
+			"GatewayConfigs": dumpStore(store.GatewayConfigs),
+			"Dataplanes":     dumpStore(store.Dataplanes),
+			"Gateways":       dumpStore(store.Gateways),
+			"UDPRoutes":      dumpStore(store.UDPRoutes),
+			"UDPRoutesV1A2":  dumpStore(store.UDPRoutesV1A2),
+			"TCPRoutes":      dumpStore(store.TCPRoutes),
+			"TLSRoutes":      dumpStore(store.TLSRoutes),
+			"Services":       dumpStore(store.Services),
+			"EndpointSlices": dumpStore(store.EndpointSlices),
+			"StaticServices": dumpStore(store.StaticServices),
+			"Nodes":          dumpStore(store.Nodes),
+		},
+		Configs: map[string]*stnrv1.StunnerConfig{},
+	}
+
+	u := reporter.GetLastUpdate()
+	if u == nil {
+		return d
+	}
+
+	d.UpdateQueue = &UpdateQueue{
+		Generation: u.Generation,
+		Upsert:     dumpQueue(u.UpsertQueue),
+		Delete:     dumpQueue(u.DeleteQueue),
+	}
+
+	// managed mode: the config queue holds the configs for all Gateways
+	for _, conf := range u.ConfigQueue {
+		d.Configs[conf.Admin.Name] = store.RedactConfig(conf)
+	}
+
+	// legacy mode: the configs are rendered into ConfigMaps named after the Gateway
+	for _, o := range u.UpsertQueue.ConfigMaps.Objects() {
+		cm, ok := o.(*corev1.ConfigMap)
+		if !ok {
+			continue
+		}
+		conf, err := store.UnpackConfigMap(cm)
+		if err != nil {
+			continue
+		}
+		d.Configs[store.GetObjectKey(cm)] = store.RedactConfig(&conf)
+	}
+
+	return d
+}
+
+func dumpQueue(q event.UpdateConf) map[string][]client.Object {
+	return map[string][]client.Object{
+		"GatewayClasses":           dumpStore(q.GatewayClasses),
+		"GatewayConfigs":           dumpStore(q.GatewayConfigs),
+		"Dataplanes":               dumpStore(q.Dataplanes),
+		"Gateways":                 dumpStore(q.Gateways),
+		"UDPRoutes":                dumpStore(q.UDPRoutes),
+		"UDPRoutesV1A2":            dumpStore(q.UDPRoutesV1A2),
+		"TCPRoutes":                dumpStore(q.TCPRoutes),
+		"TLSRoutes":                dumpStore(q.TLSRoutes),
+		"Services":                 dumpStore(q.Services),
+		"ConfigMaps":               dumpStore(q.ConfigMaps),
+		"Deployments":              dumpStore(q.Deployments),
+		"DaemonSets":               dumpStore(q.DaemonSets),
+		"PodDisruptionBudgets":     dumpStore(q.PodDisruptionBudgets),
+		"HorizontalPodAutoscalers": dumpStore(q.HorizontalPodAutoscalers),
+	}
+}
+
+func dumpStore(s store.Store) []client.Object {
+	ret := []client.Object{}
+	for _, o := range s.Objects() {
+		if ro := store.RedactObject(o); ro != nil {
+			ret = append(ret, ro)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return store.GetObjectKey(ret[i]) < store.GetObjectKey(ret[j])
+	})
+	return ret
+}
@@ -0,0 +1,131 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+type testReporter struct {
+	gen, ackedGen int
+	update        *event.EventUpdate
+}
+
+func (r *testReporter) GetGeneration() int                { return r.gen }
+func (r *testReporter) GetLastAckedGeneration() int       { return r.ackedGen }
+func (r *testReporter) GetLastUpdate() *event.EventUpdate { return r.update }
+
+// rawDump is the unmarshaled form of the dump: objects cannot be unmarshaled into interfaces
+type rawDump struct {
+	Generation          int                                `json:"generation"`
+	LastAckedGeneration int                                `json:"lastAckedGeneration"`
+	Stores              map[string][]map[string]any        `json:"stores"`
+	Configs             map[string]stnrv1.StunnerConfig    `json:"configs"`
+	UpdateQueue         *struct{ Upsert map[string][]any } `json:"updateQueue"`
+}
+
+func get(t *testing.T, h http.Handler) *rawDump {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/stunner", nil))
+	require.Equal(t, http.StatusOK, w.Code, "status")
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"), "content type")
+
+	d := &rawDump{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), d), "unmarshal")
+	return d
+}
+
+func TestDebugHandler(t *testing.T) {
+	store.Gateways.Reset([]client.Object{&gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace", Name: "gateway-1",
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "dummy",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "dummy"}},
+		},
+	}})
+	defer store.Gateways.Flush()
+	store.Nodes.Reset([]client.Object{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}})
+	defer store.Nodes.Flush()
+
+	r := &testReporter{gen: 3, ackedGen: 2}
+	h := NewHandler(r, logr.Discard())
+
+	// no update yet
+	d := get(t, h)
+	assert.Equal(t, 3, d.Generation, "generation")
+	assert.Equal(t, 2, d.LastAckedGeneration, "last acked generation")
+	require.Len(t, d.Stores["Gateways"], 1, "gateways")
+	require.Len(t, d.Stores["Nodes"], 1, "nodes")
+	assert.Empty(t, d.Stores["UDPRoutes"], "udproutes")
+	assert.Empty(t, d.Configs, "configs")
+	assert.Nil(t, d.UpdateQueue, "update queue")
+
+	// the object is stripped
+	meta := d.Stores["Gateways"][0]["metadata"].(map[string]any)
+	assert.Equal(t, "gateway-1", meta["name"], "name")
+	assert.NotContains(t, meta, "annotations", "last-applied annotation stripped")
+	assert.NotContains(t, meta, "managedFields", "managed fields stripped")
+
+	// the credentials in the configs are redacted
+	r.update = event.NewEventUpdate(3)
+	r.update.UpsertQueue.Gateways.Upsert(store.Gateways.GetAll()[0])
+	r.update.ConfigQueue = []*stnrv1.StunnerConfig{{
+		ApiVersion: stnrv1.ApiVersion,
+		Admin:      stnrv1.AdminConfig{Name: "testnamespace/gateway-1"},
+		Auth: stnrv1.AuthConfig{Type: "static", Realm: "stunner.l7mp.io",
+			Credentials: map[string]string{"username": "user", "password": "pass"}},
+		Listeners: []stnrv1.ListenerConfig{{Name: "tls", Cert: "cert", Key: "key"}},
+	}}
+
+	d = get(t, h)
+	require.NotNil(t, d.UpdateQueue, "update queue")
+	assert.Len(t, d.UpdateQueue.Upsert["Gateways"], 1, "upserted gateways")
+	require.Contains(t, d.Configs, "testnamespace/gateway-1", "config")
+	conf := d.Configs["testnamespace/gateway-1"]
+	assert.Equal(t, "stunner.l7mp.io", conf.Auth.Realm, "realm")
+	assert.Equal(t, "-SECRET-", conf.Auth.Credentials["username"], "username redacted")
+	assert.Equal(t, "-SECRET-", conf.Auth.Credentials["password"], "password redacted")
+	assert.Equal(t, "-SECRET-", conf.Listeners[0].Key, "key redacted")
+	assert.Equal(t, "user", r.update.ConfigQueue[0].Auth.Credentials["username"],
+		"original config intact")
+
+	// so are the credentials in the GatewayConfigs
+	user, pass, secret := "user", "pass", "secret"
+	gwConf := &stnrgwv1.GatewayConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace", Name: "gatewayconfig-1"},
+		Spec: stnrgwv1.GatewayConfigSpec{Username: &user, Password: &pass,
+			SharedSecret: &secret},
+	}
+	r.update.UpsertQueue.GatewayConfigs.Upsert(gwConf)
+
+	d = get(t, h)
+	require.Len(t, d.UpdateQueue.Upsert["GatewayConfigs"], 1, "upserted gatewayconfigs")
+	spec := d.UpdateQueue.Upsert["GatewayConfigs"][0].(map[string]any)["spec"].(map[string]any)
+	assert.Equal(t, "-SECRET-", spec["userName"], "username redacted")
+	assert.Equal(t, "-SECRET-", spec["password"], "password redacted")
+	assert.Equal(t, "-SECRET-", spec["sharedSecret"], "shared secret redacted")
+	assert.Equal(t, "user", *gwConf.Spec.Username, "original gatewayconfig intact")
+
+	// only GET is allowed
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/stunner", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "post")
+}
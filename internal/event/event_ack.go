@@ -6,6 +6,7 @@ import "fmt"
 type EventAck struct {
 	Type       EventType
 	Generation int
+	TraceContext
 	// Reason string
 	// Params map[string]string
 }
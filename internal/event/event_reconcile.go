@@ -1,15 +1,35 @@
 package event
 
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectKey identifies an object by kind, namespace and name.
+type ObjectKey struct {
+	Kind string
+	types.NamespacedName
+}
+
+func (k ObjectKey) String() string {
+	return fmt.Sprintf("%s:%s", k.Kind, k.NamespacedName.String())
+}
+
 // reconcile event
 type EventReconcile struct {
 	Type EventType
+	// Changes lists the objects that changed since the last reconcile event. An empty list
+	// means that the changes are unknown and everything must be re-rendered.
+	Changes []ObjectKey
+	TraceContext
 	// Reason string
 	// Params map[string]string
 }
 
 // NewEvent returns an empty event
-func NewEventReconcile() *EventReconcile {
-	return &EventReconcile{Type: EventTypeReconcile}
+func NewEventReconcile(changes ...ObjectKey) *EventReconcile {
+	return &EventReconcile{Type: EventTypeReconcile, Changes: changes}
 }
 
 func (e *EventReconcile) GetType() EventType {
@@ -17,5 +37,8 @@ func (e *EventReconcile) GetType() EventType {
 }
 
 func (e *EventReconcile) String() string {
-	return e.Type.String()
+	if len(e.Changes) == 0 {
+		return e.Type.String()
+	}
+	return fmt.Sprintf("%s: changes: %d", e.Type.String(), len(e.Changes))
 }
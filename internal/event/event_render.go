@@ -6,6 +6,10 @@ import "fmt"
 type EventRender struct {
 	Type       EventType
 	Generation int
+	// Changes lists the objects that changed since the last render. An empty list requests a
+	// full render.
+	Changes []ObjectKey
+	TraceContext
 	// Reason string
 	// Params map[string]string
 }
@@ -20,5 +24,9 @@ func (e *EventRender) GetType() EventType {
 }
 
 func (e *EventRender) String() string {
-	return fmt.Sprintf("%s: generation: %d", e.Type.String(), e.Generation)
+	if len(e.Changes) == 0 {
+		return fmt.Sprintf("%s: generation: %d", e.Type.String(), e.Generation)
+	}
+	return fmt.Sprintf("%s: generation: %d, changes: %d", e.Type.String(), e.Generation,
+		len(e.Changes))
 }
@@ -12,14 +12,20 @@ import (
 // render event
 type ConfigConf = []*stnrv1.StunnerConfig
 type UpdateConf struct {
-	GatewayClasses store.Store
-	Gateways       store.Store
-	UDPRoutes      store.Store
-	UDPRoutesV1A2  store.Store
-	Services       store.Store
-	ConfigMaps     store.Store
-	Deployments    store.Store
-	DaemonSets     store.Store
+	GatewayClasses           store.Store
+	GatewayConfigs           store.Store
+	Dataplanes               store.Store
+	Gateways                 store.Store
+	UDPRoutes                store.Store
+	UDPRoutesV1A2            store.Store
+	TCPRoutes                store.Store
+	TLSRoutes                store.Store
+	Services                 store.Store
+	ConfigMaps               store.Store
+	Deployments              store.Store
+	DaemonSets               store.Store
+	PodDisruptionBudgets     store.Store
+	HorizontalPodAutoscalers store.Store
 }
 
 type EventUpdate struct {
@@ -30,6 +36,7 @@ type EventUpdate struct {
 	LicenseStatus stnrv1.LicenseStatus
 	Generation    int
 	RequestAck    bool
+	TraceContext
 }
 
 // NewEvent returns an empty event
@@ -37,24 +44,36 @@ func NewEventUpdate(generation int) *EventUpdate {
 	return &EventUpdate{
 		Type: EventTypeUpdate,
 		UpsertQueue: UpdateConf{
-			GatewayClasses: store.NewStore(),
-			Gateways:       store.NewStore(),
-			UDPRoutes:      store.NewStore(),
-			UDPRoutesV1A2:  store.NewStore(),
-			Services:       store.NewStore(),
-			ConfigMaps:     store.NewStore(),
-			Deployments:    store.NewStore(),
-			DaemonSets:     store.NewStore(),
+			GatewayClasses:           store.NewStore(),
+			GatewayConfigs:           store.NewStore(),
+			Dataplanes:               store.NewStore(),
+			Gateways:                 store.NewStore(),
+			UDPRoutes:                store.NewStore(),
+			UDPRoutesV1A2:            store.NewStore(),
+			TCPRoutes:                store.NewStore(),
+			TLSRoutes:                store.NewStore(),
+			Services:                 store.NewStore(),
+			ConfigMaps:               store.NewStore(),
+			Deployments:              store.NewStore(),
+			DaemonSets:               store.NewStore(),
+			PodDisruptionBudgets:     store.NewStore(),
+			HorizontalPodAutoscalers: store.NewStore(),
 		},
 		DeleteQueue: UpdateConf{
-			GatewayClasses: store.NewStore(),
-			Gateways:       store.NewStore(),
-			UDPRoutes:      store.NewStore(),
-			UDPRoutesV1A2:  store.NewStore(),
-			Services:       store.NewStore(),
-			ConfigMaps:     store.NewStore(),
-			Deployments:    store.NewStore(),
-			DaemonSets:     store.NewStore(),
+			GatewayClasses:           store.NewStore(),
+			GatewayConfigs:           store.NewStore(),
+			Dataplanes:               store.NewStore(),
+			Gateways:                 store.NewStore(),
+			UDPRoutes:                store.NewStore(),
+			UDPRoutesV1A2:            store.NewStore(),
+			TCPRoutes:                store.NewStore(),
+			TLSRoutes:                store.NewStore(),
+			Services:                 store.NewStore(),
+			ConfigMaps:               store.NewStore(),
+			Deployments:              store.NewStore(),
+			DaemonSets:               store.NewStore(),
+			PodDisruptionBudgets:     store.NewStore(),
+			HorizontalPodAutoscalers: store.NewStore(),
 		},
 		ConfigQueue:   []*stnrv1.StunnerConfig{},
 		LicenseStatus: stnrv1.NewEmptyLicenseStatus(),
@@ -68,19 +87,24 @@ func (e *EventUpdate) GetType() EventType {
 }
 
 func (e *EventUpdate) String() string {
-	return fmt.Sprintf("%s (gen: %d, ack: %t, license: %s): upsert-queue: gway-cls: %d, gway: %d, "+
-		"route: %d, routeV1A2: %d, svc: %d, confmap: %d, dp: %d, ds: %d / "+
-		"delete-queue: gway-cls: %d, gway: %d, route: %d, routeV1A2: %d, "+
-		"svc: %d, confmap: %d, dp: %d, ds: %d / config-queue: %d",
+	return fmt.Sprintf("%s (gen: %d, ack: %t, license: %s): upsert-queue: gway-cls: %d, gway-conf: %d, "+
+		"dataplane: %d, gway: %d, route: %d, routeV1A2: %d, tcproute: %d, tlsroute: %d, svc: %d, confmap: %d, dp: %d, ds: %d, pdb: %d, hpa: %d / "+
+		"delete-queue: gway-cls: %d, gway: %d, route: %d, routeV1A2: %d, tcproute: %d, tlsroute: %d, "+
+		"svc: %d, confmap: %d, dp: %d, ds: %d, pdb: %d, hpa: %d / config-queue: %d",
 		e.Type.String(), e.Generation, e.RequestAck, e.LicenseStatus.String(),
-		e.UpsertQueue.GatewayClasses.Len(), e.UpsertQueue.Gateways.Len(),
+		e.UpsertQueue.GatewayClasses.Len(), e.UpsertQueue.GatewayConfigs.Len(),
+		e.UpsertQueue.Dataplanes.Len(), e.UpsertQueue.Gateways.Len(),
 		e.UpsertQueue.UDPRoutes.Len(), e.UpsertQueue.UDPRoutesV1A2.Len(),
+		e.UpsertQueue.TCPRoutes.Len(), e.UpsertQueue.TLSRoutes.Len(),
 		e.UpsertQueue.Services.Len(), e.UpsertQueue.ConfigMaps.Len(),
 		e.UpsertQueue.Deployments.Len(), e.UpsertQueue.DaemonSets.Len(),
+		e.UpsertQueue.PodDisruptionBudgets.Len(), e.UpsertQueue.HorizontalPodAutoscalers.Len(),
 		e.DeleteQueue.GatewayClasses.Len(), e.DeleteQueue.Gateways.Len(),
 		e.DeleteQueue.UDPRoutes.Len(), e.DeleteQueue.UDPRoutesV1A2.Len(),
+		e.DeleteQueue.TCPRoutes.Len(), e.DeleteQueue.TLSRoutes.Len(),
 		e.DeleteQueue.Services.Len(), e.DeleteQueue.ConfigMaps.Len(),
 		e.DeleteQueue.Deployments.Len(), e.DeleteQueue.DaemonSets.Len(),
+		e.DeleteQueue.PodDisruptionBudgets.Len(), e.DeleteQueue.HorizontalPodAutoscalers.Len(),
 		len(e.ConfigQueue))
 }
 
@@ -91,25 +115,38 @@ func (e *EventUpdate) DeepCopy() *EventUpdate {
 
 	q := e.UpsertQueue
 	u.UpsertQueue.GatewayClasses = deepCopyStore(q.GatewayClasses)
+	u.UpsertQueue.GatewayConfigs = deepCopyStore(q.GatewayConfigs)
+	u.UpsertQueue.Dataplanes = deepCopyStore(q.Dataplanes)
 	u.UpsertQueue.Gateways = deepCopyStore(q.Gateways)
 	u.UpsertQueue.UDPRoutes = deepCopyStore(q.UDPRoutes)
 	u.UpsertQueue.UDPRoutesV1A2 = deepCopyStore(q.UDPRoutesV1A2)
+	u.UpsertQueue.TCPRoutes = deepCopyStore(q.TCPRoutes)
+	u.UpsertQueue.TLSRoutes = deepCopyStore(q.TLSRoutes)
 	u.UpsertQueue.Services = deepCopyStore(q.Services)
 	u.UpsertQueue.ConfigMaps = deepCopyStore(q.ConfigMaps)
 	u.UpsertQueue.Deployments = deepCopyStore(q.Deployments)
 	u.UpsertQueue.DaemonSets = deepCopyStore(q.DaemonSets)
+	u.UpsertQueue.PodDisruptionBudgets = deepCopyStore(q.PodDisruptionBudgets)
+	u.UpsertQueue.HorizontalPodAutoscalers = deepCopyStore(q.HorizontalPodAutoscalers)
 
 	q = e.DeleteQueue
 	u.DeleteQueue.GatewayClasses = deepCopyStore(q.GatewayClasses)
+	u.DeleteQueue.GatewayConfigs = deepCopyStore(q.GatewayConfigs)
+	u.DeleteQueue.Dataplanes = deepCopyStore(q.Dataplanes)
 	u.DeleteQueue.Gateways = deepCopyStore(q.Gateways)
 	u.DeleteQueue.UDPRoutes = deepCopyStore(q.UDPRoutes)
 	u.DeleteQueue.UDPRoutesV1A2 = deepCopyStore(q.UDPRoutesV1A2)
+	u.DeleteQueue.TCPRoutes = deepCopyStore(q.TCPRoutes)
+	u.DeleteQueue.TLSRoutes = deepCopyStore(q.TLSRoutes)
 	u.DeleteQueue.Services = deepCopyStore(q.Services)
 	u.DeleteQueue.ConfigMaps = deepCopyStore(q.ConfigMaps)
 	u.DeleteQueue.Deployments = deepCopyStore(q.Deployments)
 	u.DeleteQueue.DaemonSets = deepCopyStore(q.DaemonSets)
+	u.DeleteQueue.PodDisruptionBudgets = deepCopyStore(q.PodDisruptionBudgets)
+	u.DeleteQueue.HorizontalPodAutoscalers = deepCopyStore(q.HorizontalPodAutoscalers)
 
 	u.LicenseStatus = e.LicenseStatus
+	u.TraceContext = e.TraceContext
 
 	u.ConfigQueue = make([]*stnrv1.StunnerConfig, len(e.ConfigQueue))
 	copy(u.ConfigQueue, e.ConfigQueue)
@@ -117,6 +154,18 @@ func (e *EventUpdate) DeepCopy() *EventUpdate {
 	return u
 }
 
+// Coalesce merges a stale update into the update event. Objects in the update take precedence
+// over the ones in the stale update: a stale object is added only if the object is neither upserted
+// nor deleted by the update. The config queue holds the state-of-the-world and it is not merged.
+func (e *EventUpdate) Coalesce(stale *EventUpdate) {
+	upserts, deletes := e.UpsertQueue.stores(), e.DeleteQueue.stores()
+	staleUpserts, staleDeletes := stale.UpsertQueue.stores(), stale.DeleteQueue.stores()
+	for i := range upserts {
+		coalesceStore(upserts[i], deletes[i], staleUpserts[i])
+		coalesceStore(deletes[i], upserts[i], staleDeletes[i])
+	}
+}
+
 // GetRequestAck returns true of the event contains an acknowledgement request.
 func (e *EventUpdate) GetRequestAck() bool {
 	return e.RequestAck
@@ -127,6 +176,30 @@ func (e *EventUpdate) SetRequestAck(b bool) {
 	e.RequestAck = b
 }
 
+// stores returns the stores of an update queue in a fixed order.
+func (q *UpdateConf) stores() []store.Store {
+	// MUST BE KEPT IN SYNC WITH UpdateConf
+	return []store.Store{q.GatewayClasses, q.GatewayConfigs, q.Dataplanes, q.Gateways, q.UDPRoutes,
+		q.UDPRoutesV1A2, q.TCPRoutes, q.TLSRoutes, q.Services, q.ConfigMaps, q.Deployments, q.DaemonSets, q.PodDisruptionBudgets,
+		q.HorizontalPodAutoscalers}
+}
+
+// coalesceStore adds the objects from a stale store to dst that are present neither in dst nor
+// in other.
+func coalesceStore(dst, other, stale store.Store) {
+	for _, o := range stale.Objects() {
+		key := store.GetNamespacedName(o)
+		if dst.Get(key) != nil || other.Get(key) != nil {
+			continue
+		}
+		copy, ok := o.DeepCopyObject().(client.Object)
+		if !ok {
+			panic(fmt.Sprintf("cannot deepcopy object %T", o))
+		}
+		dst.Upsert(copy)
+	}
+}
+
 func deepCopyStore(s store.Store) store.Store {
 	ret := store.NewStore()
 	for _, o := range s.Objects() {
@@ -0,0 +1,32 @@
+package event
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceContext carries the trace context of an event across the event channels, so that the
+// spans created by the consumer of the event are attached to the trace of the producer.
+type TraceContext struct {
+	spanContext trace.SpanContext
+}
+
+// SetTraceContext stores the span context of ctx in the event.
+func (t *TraceContext) SetTraceContext(ctx context.Context) {
+	t.spanContext = trace.SpanContextFromContext(ctx)
+}
+
+// GetTraceContext returns a copy of ctx with the span context of the event set as the parent
+// span.
+func (t *TraceContext) GetTraceContext(ctx context.Context) context.Context {
+	if !t.spanContext.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, t.spanContext)
+}
+
+// SpanContext returns the span context of the event.
+func (t *TraceContext) SpanContext() trace.SpanContext {
+	return t.spanContext
+}
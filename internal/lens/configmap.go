@@ -51,6 +51,12 @@ func (l *ConfigMapLens) ApplyToStatus(_ client.Object) error {
 	return nil
 }
 
+// ApplyObject returns the object to send with server-side apply. The ConfigMap holds the rendered
+// stunnerd config in legacy mode, which no one else is expected to write, so it is sent as is.
+func (l *ConfigMapLens) ApplyObject() client.Object {
+	return l.ConfigMap.DeepCopy()
+}
+
 func (l *ConfigMapLens) DeepCopy() *ConfigMapLens {
 	return &ConfigMapLens{ConfigMap: *l.ConfigMap.DeepCopy()}
 }
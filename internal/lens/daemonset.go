@@ -45,6 +45,12 @@ func (l *DaemonSetLens) ApplyToStatus(_ client.Object) error {
 	return nil
 }
 
+// ApplyObject returns the object to send with server-side apply. A DaemonSet has no replica count
+// for an autoscaler to manage, so the rendered object is sent as is.
+func (l *DaemonSetLens) ApplyObject() client.Object {
+	return l.DaemonSet.DeepCopy()
+}
+
 func (l *DaemonSetLens) DeepCopy() *DaemonSetLens {
 	return &DaemonSetLens{DaemonSet: *l.DaemonSet.DeepCopy()}
 }
@@ -66,6 +72,7 @@ func (l *DaemonSetLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
 //   See deployment.go for the full per-field pod-template policy list.
 
 func applyDaemonSet(current, desired *appv1.DaemonSet) error {
+	prunePodTemplate(current, desired, &current.Spec.Template)
 	if err := setMetadata(current, desired); err != nil {
 		return err
 	}
@@ -81,6 +88,7 @@ func projectDaemonSet(d, owned *appv1.DaemonSet) *appv1.DaemonSet {
 	k8sscheme.Scheme.Default(src)
 
 	ret := &appv1.DaemonSet{ObjectMeta: projectMetadata(src, owned)}
+	ret.Annotations = projectLastAppliedPodTemplate(ret.Annotations, src)
 	ret.Spec.Selector = copyLabelSelector(src.Spec.Selector)
 	ret.Spec.Template.ObjectMeta = projectTemplateMeta(&src.Spec.Template)
 	ret.Spec.Template.Spec = projectPodSpec(&src.Spec.Template.Spec, &owned.Spec.Template.Spec)
@@ -0,0 +1,59 @@
+package lens
+
+import (
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+type DataplaneLens struct {
+	stnrgwv1.Dataplane `json:",inline"`
+}
+
+func NewDataplaneLens(dp *stnrgwv1.Dataplane) *DataplaneLens {
+	return &DataplaneLens{Dataplane: *dp.DeepCopy()}
+}
+
+func (l *DataplaneLens) EqualResource(_ client.Object) bool {
+	return true
+}
+
+func (l *DataplaneLens) ApplyToResource(_ client.Object) error {
+	return nil
+}
+
+func (l *DataplaneLens) EqualStatus(current client.Object) bool {
+	dp, ok := current.(*stnrgwv1.Dataplane)
+	if !ok {
+		return false
+	}
+
+	return DataplaneStatusEqual(dp.Status, &l.Status)
+}
+
+func (l *DataplaneLens) ApplyToStatus(target client.Object) error {
+	dp, ok := target.(*stnrgwv1.Dataplane)
+	if !ok {
+		return fmt.Errorf("dataplane lens: invalid target type %T", target)
+	}
+
+	l.Status.DeepCopyInto(&dp.Status)
+	return nil
+}
+
+func (l *DataplaneLens) DeepCopy() *DataplaneLens {
+	return &DataplaneLens{Dataplane: *l.Dataplane.DeepCopy()}
+}
+
+func (l *DataplaneLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
+
+func DataplaneStatusEqual(current stnrgwv1.DataplaneStatus, desired *stnrgwv1.DataplaneStatus) bool {
+	normalized := desired.DeepCopy()
+	normalizeConditionTimestamps(normalized.Conditions, current.Conditions)
+
+	return apiequality.Semantic.DeepEqual(current, *normalized)
+}
@@ -51,6 +51,17 @@ func (l *DeploymentLens) ApplyToStatus(_ client.Object) error {
 	return nil
 }
 
+// ApplyObject returns the object to send with server-side apply. The replica count is sent only
+// if the Dataplane sets it to a value other than the default 1, mirroring applyDeployment, so that
+// the operator does not claim the replicas managed by an autoscaler.
+func (l *DeploymentLens) ApplyObject() client.Object {
+	d := l.Deployment.DeepCopy()
+	if d.Spec.Replicas != nil && *d.Spec.Replicas == 1 {
+		d.Spec.Replicas = nil
+	}
+	return d
+}
+
 func (l *DeploymentLens) DeepCopy() *DeploymentLens {
 	return &DeploymentLens{Deployment: *l.Deployment.DeepCopy()}
 }
@@ -111,7 +122,37 @@ func (l *DeploymentLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
 // * * Deployment.Spec.Template.Spec.TopologySpreadConstraints
 // * - renderer: set from Dataplane.Spec.TopologySpreadConstraints when non-nil.
 // * - updater: deep-copies when non-nil (including explicit empty to clear).
+// *
+// * * Deployment.Spec.Template.Spec.Volumes / Container.VolumeMounts
+// * - renderer: set only by the Dataplane.Spec.PodTemplatePatch.
+// * - updater: deep-copies the volumes when non-nil; volume mounts are copied per container.
+// *
+// * * Deployment.Spec.Template.Spec.NodeSelector / ServiceAccountName / AutomountServiceAccountToken /
+// *   PriorityClassName / RuntimeClassName / SchedulerName / DNSPolicy / DNSConfig / HostAliases /
+// *   ShareProcessNamespace
+// * - renderer: set only by the Dataplane.Spec.PodTemplatePatch.
+// * - updater: copies when set in desired; otherwise preserves current, unless reset by
+// *   prunePodTemplate.
+// *
+// * * Deployment.Spec.Template.Spec.InitContainers
+// * - renderer: set only by the Dataplane.Spec.PodTemplatePatch.
+// * - updater: when non-nil in desired, rebuilds the list from desired using the same per-container
+// *   policy as for Containers; otherwise preserves current, except for the init containers
+// *   removed by prunePodTemplate.
+// *
+// * * Container.EnvFrom / WorkingDir / StartupProbe / Lifecycle / RestartPolicy
+// * - renderer: set only by the Dataplane.Spec.PodTemplatePatch.
+// * - updater: copies when set in the desired container; otherwise preserves current, unless reset
+// *   by prunePodTemplate.
+// *
+// * * Deployment.ObjectMeta.Annotations[stunner.l7mp.io/last-applied-pod-template-patch]
+// * - renderer: records the pod template fields set by the Dataplane.Spec.PodTemplatePatch; the
+// *   patch is rejected if it sets any other field than the ones listed above (see podtemplate.go).
+// * - updater: prunePodTemplate resets the fields recorded in current but not in desired, so that a
+// *   field removed from the patch does not keep its last value, and removes the annotation when
+// *   desired records no fields.
 func applyDeployment(current, desired *appv1.Deployment) error {
+	prunePodTemplate(current, desired, &current.Spec.Template)
 	if err := setMetadata(current, desired); err != nil {
 		return err
 	}
@@ -134,12 +175,9 @@ func applyPodTemplateSpec(current, desired *corev1.PodTemplateSpec) {
 	dpspec := &desired.Spec
 	currentspec := &current.Spec
 
-	currentContainers := currentspec.Containers
-	currentspec.Containers = make([]corev1.Container, len(dpspec.Containers))
-	for i := range dpspec.Containers {
-		desiredContainer := &dpspec.Containers[i]
-		currentContainer := findContainerByName(currentContainers, desiredContainer.Name)
-		currentspec.Containers[i] = applyContainerSpec(currentContainer, desiredContainer)
+	currentspec.Containers = applyContainers(currentspec.Containers, dpspec.Containers)
+	if dpspec.InitContainers != nil {
+		currentspec.InitContainers = applyContainers(currentspec.InitContainers, dpspec.InitContainers)
 	}
 
 	currentspec.HostNetwork = dpspec.HostNetwork
@@ -149,6 +187,28 @@ func applyPodTemplateSpec(current, desired *corev1.PodTemplateSpec) {
 	applyOwnedSlice(&currentspec.Tolerations, dpspec.Tolerations)
 	applyOwnedSlice(&currentspec.ImagePullSecrets, dpspec.ImagePullSecrets)
 	applyOwnedSlice(&currentspec.TopologySpreadConstraints, dpspec.TopologySpreadConstraints)
+	applyOwnedSlice(&currentspec.Volumes, dpspec.Volumes)
+	applyOwnedMap(&currentspec.NodeSelector, dpspec.NodeSelector)
+	applyOwnedString(&currentspec.ServiceAccountName, dpspec.ServiceAccountName)
+	applyOwnedScalar(&currentspec.AutomountServiceAccountToken, dpspec.AutomountServiceAccountToken)
+	applyOwnedString(&currentspec.PriorityClassName, dpspec.PriorityClassName)
+	applyOwnedScalar(&currentspec.RuntimeClassName, dpspec.RuntimeClassName)
+	applyOwnedString(&currentspec.SchedulerName, dpspec.SchedulerName)
+	applyOwnedString(&currentspec.DNSPolicy, dpspec.DNSPolicy)
+	applyOwnedPtr(&currentspec.DNSConfig, dpspec.DNSConfig)
+	applyOwnedSlice(&currentspec.HostAliases, dpspec.HostAliases)
+	applyOwnedScalar(&currentspec.ShareProcessNamespace, dpspec.ShareProcessNamespace)
+}
+
+func applyContainers(current, desired []corev1.Container) []corev1.Container {
+	ret := make([]corev1.Container, len(desired))
+	for i := range desired {
+		desiredContainer := &desired[i]
+		currentContainer := findContainerByName(current, desiredContainer.Name)
+		ret[i] = applyContainerSpec(currentContainer, desiredContainer)
+	}
+
+	return ret
 }
 
 func projectDeployment(d, owned *appv1.Deployment) *appv1.Deployment {
@@ -156,6 +216,7 @@ func projectDeployment(d, owned *appv1.Deployment) *appv1.Deployment {
 	k8sscheme.Scheme.Default(src)
 
 	ret := &appv1.Deployment{ObjectMeta: projectMetadata(src, owned)}
+	ret.Annotations = projectLastAppliedPodTemplate(ret.Annotations, src)
 	ret.Spec.Selector = copyLabelSelector(src.Spec.Selector)
 	ret.Spec.Replicas = normalizeReplicas(src.Spec.Replicas, owned.Spec.Replicas)
 	ret.Spec.Template.ObjectMeta = projectTemplateMeta(&src.Spec.Template)
@@ -192,7 +253,18 @@ func projectPodSpec(s, owned *corev1.PodSpec) corev1.PodSpec {
 		Tolerations:                   projectOwnedSlice(s.Tolerations, owned.Tolerations),
 		ImagePullSecrets:              projectOwnedSlice(s.ImagePullSecrets, owned.ImagePullSecrets),
 		TopologySpreadConstraints:     projectOwnedSlice(s.TopologySpreadConstraints, owned.TopologySpreadConstraints),
-		Containers:                    make([]corev1.Container, 0, len(s.Containers)),
+		Volumes:                       projectOwnedSlice(s.Volumes, owned.Volumes),
+		NodeSelector:                  projectOwnedWholeMap(s.NodeSelector, owned.NodeSelector),
+		ServiceAccountName:            projectOwnedString(s.ServiceAccountName, owned.ServiceAccountName),
+		AutomountServiceAccountToken:  projectOwnedScalar(s.AutomountServiceAccountToken, owned.AutomountServiceAccountToken),
+		PriorityClassName:             projectOwnedString(s.PriorityClassName, owned.PriorityClassName),
+		RuntimeClassName:              projectOwnedScalar(s.RuntimeClassName, owned.RuntimeClassName),
+		SchedulerName:                 projectOwnedString(s.SchedulerName, owned.SchedulerName),
+		DNSPolicy:                     projectOwnedString(s.DNSPolicy, owned.DNSPolicy),
+		DNSConfig:                     projectOwnedPtr(s.DNSConfig, owned.DNSConfig),
+		HostAliases:                   projectOwnedSlice(s.HostAliases, owned.HostAliases),
+		ShareProcessNamespace:         projectOwnedScalar(s.ShareProcessNamespace, owned.ShareProcessNamespace),
+		Containers:                    projectContainers(s.Containers, owned.Containers),
 	}
 
 	// SecurityContext has an "empty struct → nil" normalization that does
@@ -201,11 +273,20 @@ func projectPodSpec(s, owned *corev1.PodSpec) corev1.PodSpec {
 		ret.SecurityContext = normalizePodSecurityContext(s.SecurityContext)
 	}
 
-	for i := range s.Containers {
-		c := s.Containers[i]
-		ownedContainer := findContainerByName(owned.Containers, c.Name)
+	if owned.InitContainers != nil {
+		ret.InitContainers = projectContainers(s.InitContainers, owned.InitContainers)
+	}
+
+	return ret
+}
+
+func projectContainers(cs, owned []corev1.Container) []corev1.Container {
+	ret := make([]corev1.Container, 0, len(cs))
+	for i := range cs {
+		c := cs[i]
+		ownedContainer := findContainerByName(owned, c.Name)
 		pc := projectContainerSpec(c, ownedContainer)
-		ret.Containers = append(ret.Containers, pc)
+		ret = append(ret, pc)
 	}
 
 	return ret
@@ -295,6 +376,7 @@ func projectContainerSpec(c corev1.Container, owned *corev1.Container) corev1.Co
 		Args:            append([]string(nil), c.Args...),
 		Ports:           projectContainerPorts(c.Ports),
 		Env:             projectEnvVars(c.Env),
+		VolumeMounts:    cloneSlice(c.VolumeMounts),
 		Resources:       *c.Resources.DeepCopy(),
 		LivenessProbe:   normalizeProbe(c.LivenessProbe),
 		ReadinessProbe:  normalizeProbe(c.ReadinessProbe),
@@ -309,6 +391,18 @@ func projectContainerSpec(c corev1.Container, owned *corev1.Container) corev1.Co
 		ret.SecurityContext = c.SecurityContext.DeepCopy()
 	}
 
+	// Fields that can only be set via a pod template patch are projected only
+	// when owned.
+	if owned != nil {
+		ret.EnvFrom = projectOwnedSlice(c.EnvFrom, owned.EnvFrom)
+		ret.WorkingDir = projectOwnedString(c.WorkingDir, owned.WorkingDir)
+		ret.Lifecycle = projectOwnedPtr(c.Lifecycle, owned.Lifecycle)
+		ret.RestartPolicy = projectOwnedScalar(c.RestartPolicy, owned.RestartPolicy)
+		if owned.StartupProbe != nil {
+			ret.StartupProbe = normalizeProbe(c.StartupProbe)
+		}
+	}
+
 	return ret
 }
 
@@ -320,6 +414,7 @@ func applyContainerSpec(current, desired *corev1.Container) corev1.Container {
 		Args:            append([]string(nil), desired.Args...),
 		Ports:           cloneSlice(desired.Ports),
 		Env:             cloneSlice(desired.Env),
+		VolumeMounts:    cloneSlice(desired.VolumeMounts),
 		Resources:       *desired.Resources.DeepCopy(),
 		LivenessProbe:   desired.LivenessProbe.DeepCopy(),
 		ReadinessProbe:  desired.ReadinessProbe.DeepCopy(),
@@ -334,6 +429,19 @@ func applyContainerSpec(current, desired *corev1.Container) corev1.Container {
 		ret.SecurityContext = current.SecurityContext.DeepCopy()
 	}
 
+	if current != nil {
+		ret.EnvFrom = cloneSlice(current.EnvFrom)
+		ret.WorkingDir = current.WorkingDir
+		ret.StartupProbe = current.StartupProbe.DeepCopy()
+		ret.Lifecycle = current.Lifecycle.DeepCopy()
+		applyOwnedScalar(&ret.RestartPolicy, current.RestartPolicy)
+	}
+	applyOwnedSlice(&ret.EnvFrom, desired.EnvFrom)
+	applyOwnedString(&ret.WorkingDir, desired.WorkingDir)
+	applyOwnedPtr(&ret.StartupProbe, desired.StartupProbe)
+	applyOwnedPtr(&ret.Lifecycle, desired.Lifecycle)
+	applyOwnedScalar(&ret.RestartPolicy, desired.RestartPolicy)
+
 	return ret
 }
 
@@ -10,6 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
 func TestDeploymentEqualIgnoresDefaultedFields(t *testing.T) {
@@ -257,6 +260,56 @@ func unownedPodSpecCases() []podSpecFieldCase {
 					"topology spread constraints should be preserved when not owned")
 			},
 		},
+		{
+			name: "Volumes",
+			mutate: func(s *corev1.PodSpec) {
+				s.Volumes = []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				}}}
+			},
+			check: func(t *testing.T, s *corev1.PodSpec) {
+				assert.Len(t, s.Volumes, 1, "volumes should be preserved when not owned")
+			},
+		},
+		{
+			name:   "NodeSelector",
+			mutate: func(s *corev1.PodSpec) { s.NodeSelector = map[string]string{"role": "turn"} },
+			check: func(t *testing.T, s *corev1.PodSpec) {
+				assert.Equal(t, map[string]string{"role": "turn"}, s.NodeSelector,
+					"node selector should be preserved when not owned")
+			},
+		},
+		{
+			name:   "PriorityClassName",
+			mutate: func(s *corev1.PodSpec) { s.PriorityClassName = "high" },
+			check: func(t *testing.T, s *corev1.PodSpec) {
+				assert.Equal(t, "high", s.PriorityClassName,
+					"priority class should be preserved when not owned")
+			},
+		},
+		{
+			name: "InitContainers",
+			mutate: func(s *corev1.PodSpec) {
+				s.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+			},
+			check: func(t *testing.T, s *corev1.PodSpec) {
+				assert.Len(t, s.InitContainers, 1, "init containers should be preserved when not owned")
+			},
+		},
+		{
+			name: "Container.EnvFrom",
+			mutate: func(s *corev1.PodSpec) {
+				s.Containers[0].EnvFrom = []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "env"},
+					},
+				}}
+			},
+			check: func(t *testing.T, s *corev1.PodSpec) {
+				assert.Len(t, s.Containers[0].EnvFrom, 1,
+					"container env sources should be preserved when not owned")
+			},
+		},
 	}
 }
 
@@ -268,6 +321,173 @@ func clearOwnedPodSpecOptionals(s *corev1.PodSpec) {
 	s.Containers[0].SecurityContext = nil
 	s.ImagePullSecrets = nil
 	s.TopologySpreadConstraints = nil
+	s.Volumes = nil
+}
+
+func TestDeploymentProjectedVolume(t *testing.T) {
+	expiration, mode := int64(3600), corev1.ProjectedVolumeSourceDefaultMode
+	desired := testDeployment()
+	desired.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "token",
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{
+				ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+					Audience:          "test-audience",
+					ExpirationSeconds: &expiration,
+					Path:              "token",
+				},
+			}},
+			DefaultMode: &mode,
+		}},
+	}}
+	desired.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+		Name: "token", MountPath: "/var/run/secrets/test", ReadOnly: true,
+	}}
+
+	current := testDeployment()
+	v := NewDeploymentLens(desired)
+	assert.False(t, v.EqualResource(current), "missing volume should be detected")
+
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	assert.Len(t, current.Spec.Template.Spec.Volumes, 1, "volume applied")
+	assert.Len(t, current.Spec.Template.Spec.Containers[0].VolumeMounts, 1, "volume mount applied")
+
+	k8sscheme.Scheme.Default(current)
+	assert.True(t, v.EqualResource(current),
+		"expected projected volume to match after scheme defaulting")
+
+	// a removed volume mount is detected
+	current.Spec.Template.Spec.Containers[0].VolumeMounts = nil
+	assert.False(t, v.EqualResource(current), "missing volume mount should be detected")
+}
+
+func TestDeploymentPatchedPodTemplateFields(t *testing.T) {
+	// fields that can only be set via a Dataplane pod template patch
+	desired := testDeployment()
+	spec := &desired.Spec.Template.Spec
+	spec.NodeSelector = map[string]string{"role": "turn"}
+	spec.PriorityClassName = "high"
+	spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+	spec.Containers[0].WorkingDir = "/tmp"
+	spec.Containers[0].StartupProbe = &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{Path: "/live"},
+	}}
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:  "sidecar",
+		Image: "sidecar:v1",
+		EnvFrom: []corev1.EnvFromSource{{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+			},
+		}},
+	})
+
+	current := testDeployment()
+	v := NewDeploymentLens(desired)
+	assert.False(t, v.EqualResource(current), "missing patched fields should be detected")
+
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	assert.Equal(t, map[string]string{"role": "turn"}, current.Spec.Template.Spec.NodeSelector,
+		"node selector applied")
+	assert.Equal(t, "high", current.Spec.Template.Spec.PriorityClassName, "priority class applied")
+	assert.Len(t, current.Spec.Template.Spec.InitContainers, 1, "init container applied")
+	require.Len(t, current.Spec.Template.Spec.Containers, 2, "sidecar applied")
+	assert.Len(t, current.Spec.Template.Spec.Containers[1].EnvFrom, 1, "sidecar env source applied")
+
+	k8sscheme.Scheme.Default(current)
+	assert.True(t, v.EqualResource(current),
+		"expected patched fields to match after scheme defaulting")
+
+	// a modified patched field is detected
+	current.Spec.Template.Spec.NodeSelector["role"] = "media"
+	assert.False(t, v.EqualResource(current), "modified node selector should be detected")
+	current.Spec.Template.Spec.NodeSelector["role"] = "turn"
+
+	current.Spec.Template.Spec.Containers[0].WorkingDir = "/"
+	assert.False(t, v.EqualResource(current), "modified working dir should be detected")
+}
+
+func TestDeploymentPrunesRemovedPodTemplatePatchFields(t *testing.T) {
+	// the patch sets a node selector, a service account, an init container and the working dir
+	// of the stunnerd container
+	patched := testDeployment()
+	spec := &patched.Spec.Template.Spec
+	spec.NodeSelector = map[string]string{"role": "turn"}
+	spec.ServiceAccountName = "stunnerd"
+	spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+	spec.Containers[0].WorkingDir = "/tmp"
+	store.SetLastAppliedPodTemplateFields(patched, []string{
+		"spec.nodeSelector",
+		"spec.serviceAccountName",
+		"spec.initContainers[init]",
+		"spec.initContainers[init].image",
+		"spec.containers[stunnerd].workingDir",
+	})
+
+	current := testDeployment()
+	require.NoError(t, NewDeploymentLens(patched).ApplyToResource(current), "apply failed")
+	k8sscheme.Scheme.Default(current)
+	assert.True(t, NewDeploymentLens(patched).EqualResource(current), "patched")
+
+	// the node selector is removed from the patch
+	desired := patched.DeepCopy()
+	desired.Spec.Template.Spec.NodeSelector = nil
+	store.SetLastAppliedPodTemplateFields(desired, []string{
+		"spec.serviceAccountName",
+		"spec.initContainers[init]",
+		"spec.initContainers[init].image",
+		"spec.containers[stunnerd].workingDir",
+	})
+	v := NewDeploymentLens(desired)
+	assert.False(t, v.EqualResource(current), "removed field should be detected")
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	assert.Nil(t, current.Spec.Template.Spec.NodeSelector, "node selector pruned")
+	assert.Equal(t, "stunnerd", current.Spec.Template.Spec.ServiceAccountName, "service account kept")
+	k8sscheme.Scheme.Default(current)
+	assert.True(t, v.EqualResource(current), "pruned")
+
+	// the patch is removed
+	v = NewDeploymentLens(testDeployment())
+	assert.False(t, v.EqualResource(current), "removed patch should be detected")
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	assert.Empty(t, current.Spec.Template.Spec.ServiceAccountName, "service account pruned")
+	assert.Nil(t, current.Spec.Template.Spec.InitContainers, "init container pruned")
+	assert.Empty(t, current.Spec.Template.Spec.Containers[0].WorkingDir, "working dir pruned")
+	assert.NotContains(t, current.GetAnnotations(), opdefault.LastAppliedPodTemplatePatchAnnotationKey,
+		"record removed")
+	k8sscheme.Scheme.Default(current)
+	assert.True(t, v.EqualResource(current), "pruned")
+
+	// fields set by someone else are left alone
+	current.Spec.Template.Spec.NodeSelector = map[string]string{"role": "media"}
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	assert.Equal(t, map[string]string{"role": "media"}, current.Spec.Template.Spec.NodeSelector,
+		"unpatched node selector preserved")
+}
+
+func TestValidatePodTemplateFields(t *testing.T) {
+	assert.NoError(t, ValidatePodTemplateFields([]string{
+		"metadata.labels",
+		"spec.nodeSelector",
+		"spec.initContainers[init]",
+		"spec.containers[sidecar].envFrom",
+	}), "reconciled fields")
+
+	for _, f := range []string{
+		"metadata.finalizers",
+		"spec.hostPID",
+		"spec.preemptionPolicy",
+		"spec.enableServiceLinks",
+		"spec.readinessGates",
+		"spec.containers[stunnerd].tty",
+		"spec.containers[stunnerd].resizePolicy",
+		"spec.volumes[vol]",
+		"spec.containers[]",
+		"status.phase",
+	} {
+		assert.Error(t, ValidatePodTemplateFields([]string{f}), f)
+	}
 }
 
 func TestDeploymentApplyCopiesOwnedEmptyOptionalSlices(t *testing.T) {
@@ -0,0 +1,59 @@
+package lens
+
+import (
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+type GatewayConfigLens struct {
+	stnrgwv1.GatewayConfig `json:",inline"`
+}
+
+func NewGatewayConfigLens(gwConf *stnrgwv1.GatewayConfig) *GatewayConfigLens {
+	return &GatewayConfigLens{GatewayConfig: *gwConf.DeepCopy()}
+}
+
+func (l *GatewayConfigLens) EqualResource(_ client.Object) bool {
+	return true
+}
+
+func (l *GatewayConfigLens) ApplyToResource(_ client.Object) error {
+	return nil
+}
+
+func (l *GatewayConfigLens) EqualStatus(current client.Object) bool {
+	gwConf, ok := current.(*stnrgwv1.GatewayConfig)
+	if !ok {
+		return false
+	}
+
+	return GatewayConfigStatusEqual(gwConf.Status, &l.Status)
+}
+
+func (l *GatewayConfigLens) ApplyToStatus(target client.Object) error {
+	gwConf, ok := target.(*stnrgwv1.GatewayConfig)
+	if !ok {
+		return fmt.Errorf("gatewayconfig lens: invalid target type %T", target)
+	}
+
+	l.Status.DeepCopyInto(&gwConf.Status)
+	return nil
+}
+
+func (l *GatewayConfigLens) DeepCopy() *GatewayConfigLens {
+	return &GatewayConfigLens{GatewayConfig: *l.GatewayConfig.DeepCopy()}
+}
+
+func (l *GatewayConfigLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
+
+func GatewayConfigStatusEqual(current stnrgwv1.GatewayConfigStatus, desired *stnrgwv1.GatewayConfigStatus) bool {
+	normalized := desired.DeepCopy()
+	normalizeConditionTimestamps(normalized.Conditions, current.Conditions)
+
+	return apiequality.Semantic.DeepEqual(current, *normalized)
+}
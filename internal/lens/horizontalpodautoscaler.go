@@ -0,0 +1,104 @@
+package lens
+
+import (
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+type HorizontalPodAutoscalerLens struct {
+	autoscalingv2.HorizontalPodAutoscaler `json:",inline"`
+}
+
+func NewHorizontalPodAutoscalerLens(hpa *autoscalingv2.HorizontalPodAutoscaler) *HorizontalPodAutoscalerLens {
+	return &HorizontalPodAutoscalerLens{HorizontalPodAutoscaler: *hpa.DeepCopy()}
+}
+
+func (l *HorizontalPodAutoscalerLens) EqualResource(current client.Object) bool {
+	hpa, ok := current.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return false
+	}
+
+	return apiequality.Semantic.DeepEqual(projectHorizontalPodAutoscaler(hpa, &l.HorizontalPodAutoscaler),
+		projectHorizontalPodAutoscaler(&l.HorizontalPodAutoscaler, &l.HorizontalPodAutoscaler))
+}
+
+func (l *HorizontalPodAutoscalerLens) ApplyToResource(target client.Object) error {
+	hpa, ok := target.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return fmt.Errorf("horizontalpodautoscaler lens: invalid target type %T", target)
+	}
+
+	return applyHorizontalPodAutoscaler(hpa, &l.HorizontalPodAutoscaler)
+}
+
+func (l *HorizontalPodAutoscalerLens) EqualStatus(_ client.Object) bool {
+	return true
+}
+
+func (l *HorizontalPodAutoscalerLens) ApplyToStatus(_ client.Object) error {
+	return nil
+}
+
+// ApplyObject returns the object to send with server-side apply. The autoscaler writes only the
+// status of the HorizontalPodAutoscaler, so the rendered spec is sent as is.
+func (l *HorizontalPodAutoscalerLens) ApplyObject() client.Object {
+	return l.HorizontalPodAutoscaler.DeepCopy()
+}
+
+func (l *HorizontalPodAutoscalerLens) DeepCopy() *HorizontalPodAutoscalerLens {
+	return &HorizontalPodAutoscalerLens{HorizontalPodAutoscaler: *l.HorizontalPodAutoscaler.DeepCopy()}
+}
+
+func (l *HorizontalPodAutoscalerLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
+
+// * HorizontalPodAutoscaler.ObjectMeta.Labels / HorizontalPodAutoscaler.ObjectMeta.Annotations / HorizontalPodAutoscaler.ObjectMeta.OwnerReferences
+// - renderer: sets the operator-owned dataplane labels/annotations and a singleton owner
+//   reference to the Gateway.
+// - updater: merges top-level metadata and updates owner reference via setMetadata/addOwnerRef.
+//
+// * HorizontalPodAutoscaler.Spec.ScaleTargetRef
+// - renderer: refers to the dataplane Deployment of the Gateway.
+// - updater: overwrites from desired.
+//
+// * HorizontalPodAutoscaler.Spec.MinReplicas / HorizontalPodAutoscaler.Spec.MaxReplicas
+// - renderer: copies Dataplane.Spec.Autoscaling, MinReplicas is always set explicitly to avoid a
+//   diff against the API server default.
+// - updater: overwrites from desired.
+//
+// * HorizontalPodAutoscaler.Spec.Metrics
+// - renderer: always sets at least one metric (the CPU utilization by default) to avoid a diff
+//   against the API server default.
+// - updater: overwrites the full list from desired.
+//
+// * HorizontalPodAutoscaler.Spec.Behavior
+// - renderer: never set.
+// - updater: preserved, so the scaling behavior can be tuned (and is defaulted by the API
+//   server) without the operator resetting it.
+
+func applyHorizontalPodAutoscaler(current, desired *autoscalingv2.HorizontalPodAutoscaler) error {
+	if err := setMetadata(current, desired); err != nil {
+		return err
+	}
+
+	current.Spec.ScaleTargetRef = desired.Spec.ScaleTargetRef
+	applyOwnedScalar(&current.Spec.MinReplicas, desired.Spec.MinReplicas)
+	current.Spec.MaxReplicas = desired.Spec.MaxReplicas
+	current.Spec.Metrics = cloneSlice[autoscalingv2.MetricSpec](desired.Spec.Metrics)
+
+	return nil
+}
+
+func projectHorizontalPodAutoscaler(hpa, owned *autoscalingv2.HorizontalPodAutoscaler) *autoscalingv2.HorizontalPodAutoscaler {
+	ret := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: projectMetadata(hpa, owned)}
+	ret.Spec.ScaleTargetRef = hpa.Spec.ScaleTargetRef
+	ret.Spec.MinReplicas = projectOwnedScalar(hpa.Spec.MinReplicas, owned.Spec.MinReplicas)
+	ret.Spec.MaxReplicas = hpa.Spec.MaxReplicas
+	ret.Spec.Metrics = cloneSlice[autoscalingv2.MetricSpec](hpa.Spec.Metrics)
+	return ret
+}
@@ -0,0 +1,110 @@
+package lens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHorizontalPodAutoscalerEqualDetectsRealDiff(t *testing.T) {
+	current := testHorizontalPodAutoscaler()
+	candidate := testHorizontalPodAutoscaler()
+	candidate.Spec.MaxReplicas = 10
+
+	v := NewHorizontalPodAutoscalerLens(candidate)
+	assert.False(t, v.EqualResource(current), "expected maxReplicas change to be detected")
+
+	candidate = testHorizontalPodAutoscaler()
+	target := int32(50)
+	candidate.Spec.Metrics[0].Resource.Target.AverageUtilization = &target
+
+	v = NewHorizontalPodAutoscalerLens(candidate)
+	assert.False(t, v.EqualResource(current), "expected metric target change to be detected")
+}
+
+func TestHorizontalPodAutoscalerEqualIgnoresUnowned(t *testing.T) {
+	current := testHorizontalPodAutoscaler()
+	current.Labels["external-label"] = "keep"
+	window := int32(60)
+	current.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+	}
+	current.Status.CurrentReplicas = 3
+
+	v := NewHorizontalPodAutoscalerLens(testHorizontalPodAutoscaler())
+	assert.True(t, v.EqualResource(current), "unowned fields should be ignored in equality")
+}
+
+func TestHorizontalPodAutoscalerApply(t *testing.T) {
+	current := testHorizontalPodAutoscaler()
+	current.Labels["external-label"] = "keep"
+	window := int32(60)
+	current.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+	}
+
+	desired := testHorizontalPodAutoscaler()
+	desired.Labels["owned-label"] = "set"
+	desired.Spec.MaxReplicas = 10
+	desired.Spec.Metrics = []autoscalingv2.MetricSpec{{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "stunner_allocations_active"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType},
+		},
+	}}
+
+	v := NewHorizontalPodAutoscalerLens(desired)
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+
+	assert.Equal(t, "keep", current.Labels["external-label"], "external labels retained")
+	assert.Equal(t, "set", current.Labels["owned-label"], "owned labels added")
+	assert.Equal(t, int32(10), current.Spec.MaxReplicas, "max replicas")
+	require.Len(t, current.Spec.Metrics, 1, "metrics replaced")
+	assert.Equal(t, autoscalingv2.PodsMetricSourceType, current.Spec.Metrics[0].Type, "metric type")
+	require.NotNil(t, current.Spec.Behavior, "behavior retained")
+	assert.Equal(t, &window, current.Spec.Behavior.ScaleDown.StabilizationWindowSeconds,
+		"behavior")
+	assert.True(t, v.EqualResource(current), "equal after apply")
+}
+
+func testHorizontalPodAutoscaler() *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas, target := int32(1), int32(80)
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "stunner"},
+			Annotations: map[string]string{
+				"team": "edge",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Gateway",
+				Name:       "gw",
+			}},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "gw",
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: 5,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: "cpu",
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: &target,
+					},
+				},
+			}},
+		},
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -20,6 +22,13 @@ type Lens interface {
 	ApplyToStatus(target client.Object) error
 }
 
+// ApplyLens is implemented by the lenses of the resources the updater can write with server-side
+// apply. ApplyObject returns the fields of the desired object that are owned by the operator.
+type ApplyLens interface {
+	Lens
+	ApplyObject() client.Object
+}
+
 func New(o client.Object) (Lens, error) {
 	switch current := o.(type) {
 	case *corev1.ConfigMap:
@@ -30,14 +39,26 @@ func New(o client.Object) (Lens, error) {
 		return NewDeploymentLens(current), nil
 	case *appv1.DaemonSet:
 		return NewDaemonSetLens(current), nil
+	case *policyv1.PodDisruptionBudget:
+		return NewPodDisruptionBudgetLens(current), nil
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		return NewHorizontalPodAutoscalerLens(current), nil
 	case *gwapiv1.GatewayClass:
 		return NewGatewayClassLens(current), nil
 	case *gwapiv1.Gateway:
 		return NewGatewayLens(current), nil
+	case *stnrgwv1.GatewayConfig:
+		return NewGatewayConfigLens(current), nil
+	case *stnrgwv1.Dataplane:
+		return NewDataplaneLens(current), nil
 	case *stnrgwv1.UDPRoute:
 		return NewUDPRouteLens(current), nil
 	case *gwapiv1a2.UDPRoute:
 		return NewUDPRouteV1A2Lens(current), nil
+	case *gwapiv1a2.TCPRoute:
+		return NewV1A2RouteLens(current), nil
+	case *gwapiv1a2.TLSRoute:
+		return NewV1A2RouteLens(current), nil
 	default:
 		return nil, fmt.Errorf("unsupported object type %T", o)
 	}
@@ -1,5 +1,7 @@
 package lens
 
+import "maps"
+
 // This file implements the per-field ownership model used by the resource
 // lenses. A field is "owned" by the operator when the renderer-produced spec
 // (the lens's desired state) has it set; otherwise the field is considered
@@ -78,6 +80,47 @@ func applyOwnedScalar[T any](cur **T, desired *T) {
 	*cur = &v
 }
 
+// projectOwnedString returns src when owned is non-empty; otherwise the empty
+// string. Use for string-typed fields that the API server may default.
+func projectOwnedString[T ~string](src, owned T) T {
+	if owned == "" {
+		return ""
+	}
+	return src
+}
+
+// applyOwnedString sets *cur to desired when desired is non-empty; otherwise
+// leaves *cur alone.
+func applyOwnedString[T ~string](cur *T, desired T) {
+	if desired == "" {
+		return
+	}
+	*cur = desired
+}
+
+// projectOwnedWholeMap returns a copy of src when owned is non-nil; otherwise
+// nil. Unlike projectOwnedMap, which projects only the owned keys, this owns
+// the map as a whole.
+func projectOwnedWholeMap[K comparable, V any](src, owned map[K]V) map[K]V {
+	if owned == nil || len(src) == 0 {
+		return nil
+	}
+	return maps.Clone(src)
+}
+
+// applyOwnedMap sets *cur to a copy of desired when desired is non-nil;
+// otherwise leaves *cur alone. A non-nil empty desired clears *cur.
+func applyOwnedMap[K comparable, V any](cur *map[K]V, desired map[K]V) {
+	if desired == nil {
+		return
+	}
+	if len(desired) == 0 {
+		*cur = nil
+		return
+	}
+	*cur = maps.Clone(desired)
+}
+
 // cloneSlice returns a fresh slice with each element deep-copied. Returns
 // nil for an empty input.
 func cloneSlice[T any, PT interface {
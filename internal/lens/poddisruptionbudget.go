@@ -0,0 +1,106 @@
+package lens
+
+import (
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+type PodDisruptionBudgetLens struct {
+	policyv1.PodDisruptionBudget `json:",inline"`
+}
+
+func NewPodDisruptionBudgetLens(pdb *policyv1.PodDisruptionBudget) *PodDisruptionBudgetLens {
+	return &PodDisruptionBudgetLens{PodDisruptionBudget: *pdb.DeepCopy()}
+}
+
+func (l *PodDisruptionBudgetLens) EqualResource(current client.Object) bool {
+	pdb, ok := current.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return false
+	}
+
+	return apiequality.Semantic.DeepEqual(projectPodDisruptionBudget(pdb, &l.PodDisruptionBudget),
+		projectPodDisruptionBudget(&l.PodDisruptionBudget, &l.PodDisruptionBudget))
+}
+
+func (l *PodDisruptionBudgetLens) ApplyToResource(target client.Object) error {
+	pdb, ok := target.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return fmt.Errorf("poddisruptionbudget lens: invalid target type %T", target)
+	}
+
+	return applyPodDisruptionBudget(pdb, &l.PodDisruptionBudget)
+}
+
+func (l *PodDisruptionBudgetLens) EqualStatus(_ client.Object) bool {
+	return true
+}
+
+func (l *PodDisruptionBudgetLens) ApplyToStatus(_ client.Object) error {
+	return nil
+}
+
+// ApplyObject returns the object to send with server-side apply. The disruption controller writes
+// only the status of the PodDisruptionBudget, so the rendered spec is sent as is.
+func (l *PodDisruptionBudgetLens) ApplyObject() client.Object {
+	return l.PodDisruptionBudget.DeepCopy()
+}
+
+func (l *PodDisruptionBudgetLens) DeepCopy() *PodDisruptionBudgetLens {
+	return &PodDisruptionBudgetLens{PodDisruptionBudget: *l.PodDisruptionBudget.DeepCopy()}
+}
+
+func (l *PodDisruptionBudgetLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
+
+// * PodDisruptionBudget.ObjectMeta.Labels / PodDisruptionBudget.ObjectMeta.Annotations / PodDisruptionBudget.ObjectMeta.OwnerReferences
+// - renderer: sets the operator-owned dataplane labels/annotations and a singleton owner
+//   reference to the Gateway.
+// - updater: merges top-level metadata and updates owner reference via setMetadata/addOwnerRef.
+//
+// * PodDisruptionBudget.Spec.Selector
+// - renderer: same as the Deployment selector.
+// - updater: deep-copies desired selector into current selector.
+//
+// * PodDisruptionBudget.Spec.MinAvailable / PodDisruptionBudget.Spec.MaxUnavailable
+// - renderer: copies Dataplane.Spec.PodDisruptionBudget, exactly one of the two is set.
+// - updater: overwrites both from desired, so that switching between the two clears the stale
+//   one.
+//
+// * PodDisruptionBudget.Spec.UnhealthyPodEvictionPolicy
+// - renderer: never set.
+// - updater: preserved.
+
+func applyPodDisruptionBudget(current, desired *policyv1.PodDisruptionBudget) error {
+	if err := setMetadata(current, desired); err != nil {
+		return err
+	}
+
+	current.Spec.Selector = copyLabelSelector(desired.Spec.Selector)
+	current.Spec.MinAvailable = copyIntOrString(desired.Spec.MinAvailable)
+	current.Spec.MaxUnavailable = copyIntOrString(desired.Spec.MaxUnavailable)
+
+	return nil
+}
+
+func projectPodDisruptionBudget(pdb, owned *policyv1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	ret := &policyv1.PodDisruptionBudget{ObjectMeta: projectMetadata(pdb, owned)}
+	ret.Spec.Selector = copyLabelSelector(pdb.Spec.Selector)
+	ret.Spec.MinAvailable = copyIntOrString(pdb.Spec.MinAvailable)
+	ret.Spec.MaxUnavailable = copyIntOrString(pdb.Spec.MaxUnavailable)
+	return ret
+}
+
+func copyIntOrString(v *intstr.IntOrString) *intstr.IntOrString {
+	if v == nil {
+		return nil
+	}
+
+	ret := *v
+	return &ret
+}
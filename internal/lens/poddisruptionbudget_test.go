@@ -0,0 +1,82 @@
+package lens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodDisruptionBudgetEqualDetectsRealDiff(t *testing.T) {
+	current := testPodDisruptionBudget()
+	candidate := testPodDisruptionBudget()
+	minAvailable := intstr.FromString("50%")
+	candidate.Spec.MinAvailable = &minAvailable
+
+	v := NewPodDisruptionBudgetLens(candidate)
+	assert.False(t, v.EqualResource(current), "expected minAvailable change to be detected")
+}
+
+func TestPodDisruptionBudgetEqualIgnoresUnowned(t *testing.T) {
+	current := testPodDisruptionBudget()
+	current.Labels["external-label"] = "keep"
+	policy := policyv1.AlwaysAllow
+	current.Spec.UnhealthyPodEvictionPolicy = &policy
+	current.Status.CurrentHealthy = 2
+
+	v := NewPodDisruptionBudgetLens(testPodDisruptionBudget())
+	assert.True(t, v.EqualResource(current), "unowned fields should be ignored in equality")
+}
+
+func TestPodDisruptionBudgetApply(t *testing.T) {
+	current := testPodDisruptionBudget()
+	current.Labels["external-label"] = "keep"
+	policy := policyv1.AlwaysAllow
+	current.Spec.UnhealthyPodEvictionPolicy = &policy
+
+	// switch from minAvailable to maxUnavailable
+	desired := testPodDisruptionBudget()
+	desired.Labels["owned-label"] = "set"
+	maxUnavailable := intstr.FromInt32(1)
+	desired.Spec.MinAvailable = nil
+	desired.Spec.MaxUnavailable = &maxUnavailable
+
+	v := NewPodDisruptionBudgetLens(desired)
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+
+	assert.Equal(t, "keep", current.Labels["external-label"], "external labels retained")
+	assert.Equal(t, "set", current.Labels["owned-label"], "owned labels added")
+	assert.Nil(t, current.Spec.MinAvailable, "stale minAvailable cleared")
+	require.NotNil(t, current.Spec.MaxUnavailable, "maxUnavailable set")
+	assert.Equal(t, maxUnavailable, *current.Spec.MaxUnavailable, "maxUnavailable")
+	require.NotNil(t, current.Spec.UnhealthyPodEvictionPolicy, "eviction policy retained")
+	assert.Equal(t, policyv1.AlwaysAllow, *current.Spec.UnhealthyPodEvictionPolicy,
+		"eviction policy")
+	assert.True(t, v.EqualResource(current), "equal after apply")
+}
+
+func testPodDisruptionBudget() *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt32(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "stunner"},
+			Annotations: map[string]string{
+				"team": "edge",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Gateway",
+				Name:       "gw",
+			}},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "stunner"}},
+			MinAvailable: &minAvailable,
+		},
+	}
+}
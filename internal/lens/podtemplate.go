@@ -0,0 +1,229 @@
+package lens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+// This file implements the reconciliation of the pod template fields set by a Dataplane pod
+// template patch. A patch can only touch the pod template fields the Deployment and DaemonSet
+// lenses compare and apply, listed below by their JSON name. The renderer records the fields
+// touched by the patch in the last-applied-pod-template-patch annotation of the dataplane
+// resource, using the paths below, and the lenses reset the fields that were touched by the
+// patch earlier but are no longer touched. Otherwise a field removed from the patch would keep
+// its last value forever, since most pod template fields are left alone when not set by the
+// operator.
+//
+// Field paths:
+//   - metadata.<field>: a pod template metadata field
+//   - spec.<field>: a pod spec field
+//   - spec.containers[<name>]: a container added by the patch
+//   - spec.containers[<name>].<field>: a field of a container
+//   - spec.initContainers[<name>], spec.initContainers[<name>].<field>: same for init containers
+
+// podTemplateMetaFields lists the pod template metadata fields the lenses reconcile. The pod
+// template labels and annotations are overwritten on each update, so there is nothing to reset.
+var podTemplateMetaFields = []string{"labels", "annotations"}
+
+// podSpecFields maps the pod spec fields the lenses reconcile to a function that resets the field.
+// The containers and the init containers are handled per container.
+var podSpecFields = map[string]func(s *corev1.PodSpec){
+	"hostNetwork":                   func(s *corev1.PodSpec) { s.HostNetwork = false },
+	"terminationGracePeriodSeconds": func(s *corev1.PodSpec) { s.TerminationGracePeriodSeconds = nil },
+	"affinity":                      func(s *corev1.PodSpec) { s.Affinity = nil },
+	"securityContext":               func(s *corev1.PodSpec) { s.SecurityContext = nil },
+	"tolerations":                   func(s *corev1.PodSpec) { s.Tolerations = nil },
+	"imagePullSecrets":              func(s *corev1.PodSpec) { s.ImagePullSecrets = nil },
+	"topologySpreadConstraints":     func(s *corev1.PodSpec) { s.TopologySpreadConstraints = nil },
+	"volumes":                       func(s *corev1.PodSpec) { s.Volumes = nil },
+	"nodeSelector":                  func(s *corev1.PodSpec) { s.NodeSelector = nil },
+	"serviceAccountName":            func(s *corev1.PodSpec) { s.ServiceAccountName = "" },
+	"automountServiceAccountToken":  func(s *corev1.PodSpec) { s.AutomountServiceAccountToken = nil },
+	"priorityClassName":             func(s *corev1.PodSpec) { s.PriorityClassName = "" },
+	"runtimeClassName":              func(s *corev1.PodSpec) { s.RuntimeClassName = nil },
+	"schedulerName":                 func(s *corev1.PodSpec) { s.SchedulerName = "" },
+	"dnsPolicy":                     func(s *corev1.PodSpec) { s.DNSPolicy = "" },
+	"dnsConfig":                     func(s *corev1.PodSpec) { s.DNSConfig = nil },
+	"hostAliases":                   func(s *corev1.PodSpec) { s.HostAliases = nil },
+	"shareProcessNamespace":         func(s *corev1.PodSpec) { s.ShareProcessNamespace = nil },
+}
+
+// containerFields maps the container fields the lenses reconcile to a function that resets the
+// field.
+var containerFields = map[string]func(c *corev1.Container){
+	"image":           func(c *corev1.Container) { c.Image = "" },
+	"command":         func(c *corev1.Container) { c.Command = nil },
+	"args":            func(c *corev1.Container) { c.Args = nil },
+	"ports":           func(c *corev1.Container) { c.Ports = nil },
+	"env":             func(c *corev1.Container) { c.Env = nil },
+	"volumeMounts":    func(c *corev1.Container) { c.VolumeMounts = nil },
+	"resources":       func(c *corev1.Container) { c.Resources = corev1.ResourceRequirements{} },
+	"livenessProbe":   func(c *corev1.Container) { c.LivenessProbe = nil },
+	"readinessProbe":  func(c *corev1.Container) { c.ReadinessProbe = nil },
+	"startupProbe":    func(c *corev1.Container) { c.StartupProbe = nil },
+	"imagePullPolicy": func(c *corev1.Container) { c.ImagePullPolicy = "" },
+	"securityContext": func(c *corev1.Container) { c.SecurityContext = nil },
+	"envFrom":         func(c *corev1.Container) { c.EnvFrom = nil },
+	"workingDir":      func(c *corev1.Container) { c.WorkingDir = "" },
+	"lifecycle":       func(c *corev1.Container) { c.Lifecycle = nil },
+	"restartPolicy":   func(c *corev1.Container) { c.RestartPolicy = nil },
+}
+
+// podTemplateField is a parsed pod template field path.
+type podTemplateField struct {
+	// section is either "metadata" or "spec".
+	section string
+	// list is "containers" or "initContainers" for container fields, empty otherwise.
+	list string
+	// container is the name of the container for container fields.
+	container string
+	// field is the JSON name of the field, empty for a whole container.
+	field string
+}
+
+func parsePodTemplateField(path string) (podTemplateField, error) {
+	ret := podTemplateField{}
+	section, rest, ok := strings.Cut(path, ".")
+	if !ok || (section != "metadata" && section != "spec") || rest == "" {
+		return ret, fmt.Errorf("invalid pod template field %q", path)
+	}
+	ret.section = section
+
+	list, rest, ok := strings.Cut(rest, "[")
+	if !ok {
+		ret.field = list
+		return ret, nil
+	}
+
+	if section != "spec" || (list != "containers" && list != "initContainers") {
+		return ret, fmt.Errorf("invalid pod template field %q", path)
+	}
+	ret.list = list
+
+	name, rest, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return ret, fmt.Errorf("invalid pod template field %q", path)
+	}
+	ret.container = name
+
+	if rest == "" {
+		return ret, nil
+	}
+	field, ok := strings.CutPrefix(rest, ".")
+	if !ok || field == "" {
+		return ret, fmt.Errorf("invalid pod template field %q", path)
+	}
+	ret.field = field
+
+	return ret, nil
+}
+
+// ValidatePodTemplateFields returns an error if any of the pod template field paths refers to a
+// field that the Deployment and DaemonSet lenses do not reconcile.
+func ValidatePodTemplateFields(paths []string) error {
+	for _, path := range paths {
+		f, err := parsePodTemplateField(path)
+		if err != nil {
+			return err
+		}
+
+		ok := false
+		switch {
+		case f.section == "metadata":
+			ok = slices.Contains(podTemplateMetaFields, f.field)
+		case f.list != "" && f.field == "":
+			ok = true
+		case f.list != "":
+			_, ok = containerFields[f.field]
+		default:
+			_, ok = podSpecFields[f.field]
+		}
+
+		if !ok {
+			return fmt.Errorf("pod template field %q cannot be reconciled", path)
+		}
+	}
+
+	return nil
+}
+
+// prunePodTemplate resets the pod template fields in the target that were set by a pod template
+// patch earlier, as recorded in the last-applied-pod-template-patch annotation of current, but
+// are no longer touched by the patch of desired, and updates the record of current. The fields
+// owned by desired are then applied on top by applyPodTemplateSpec.
+func prunePodTemplate(current, desired client.Object, template *corev1.PodTemplateSpec) {
+	last := store.GetLastAppliedPodTemplateFields(current)
+	fields := store.GetLastAppliedPodTemplateFields(desired)
+
+	for _, path := range last {
+		if slices.Contains(fields, path) {
+			continue
+		}
+
+		f, err := parsePodTemplateField(path)
+		if err != nil || f.section != "spec" {
+			continue
+		}
+
+		if f.list == "" {
+			if reset, ok := podSpecFields[f.field]; ok {
+				reset(&template.Spec)
+			}
+			continue
+		}
+
+		cs := &template.Spec.Containers
+		if f.list == "initContainers" {
+			cs = &template.Spec.InitContainers
+		}
+
+		if f.field == "" {
+			*cs = slices.DeleteFunc(*cs, func(c corev1.Container) bool { return c.Name == f.container })
+			if len(*cs) == 0 {
+				*cs = nil
+			}
+			continue
+		}
+
+		if c := findContainerByName(*cs, f.container); c != nil {
+			if reset, ok := containerFields[f.field]; ok {
+				reset(c)
+			}
+		}
+	}
+
+	if _, ok := desired.GetAnnotations()[opdefault.LastAppliedPodTemplatePatchAnnotationKey]; !ok {
+		as := current.GetAnnotations()
+		delete(as, opdefault.LastAppliedPodTemplatePatchAnnotationKey)
+		current.SetAnnotations(as)
+	}
+}
+
+// projectLastAppliedPodTemplate adds the last-applied-pod-template-patch annotation of an object
+// to projected metadata annotations, so that a change in the set of the patched fields, including
+// the removal of the patch, is noticed even though the projection otherwise keeps only the
+// annotations owned by the operator.
+func projectLastAppliedPodTemplate(annotations map[string]string, o client.Object) map[string]string {
+	v, ok := o.GetAnnotations()[opdefault.LastAppliedPodTemplatePatchAnnotationKey]
+	if !ok {
+		delete(annotations, opdefault.LastAppliedPodTemplatePatchAnnotationKey)
+		if len(annotations) == 0 {
+			return nil
+		}
+		return annotations
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[opdefault.LastAppliedPodTemplatePatchAnnotationKey] = v
+
+	return annotations
+}
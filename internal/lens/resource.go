@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
 func projectMetadata(current, owned client.Object) metav1.ObjectMeta {
@@ -72,6 +73,31 @@ func projectOwnedOwnerReferences(current, owned []metav1.OwnerReference) []metav
 	return ret
 }
 
+// pruneMetadata removes the labels and annotations from dst that the operator applied earlier, as
+// recorded in the last-applied-metadata annotation of dst, but are no longer set in src. Nothing is
+// removed unless src records the applied labels and annotations itself.
+func pruneMetadata(dst, src client.Object) {
+	if _, ok := src.GetAnnotations()[opdefault.LastAppliedMetadataAnnotationKey]; !ok {
+		return
+	}
+
+	last := store.GetLastAppliedMetadata(dst)
+	dst.SetLabels(store.PruneMetadata(dst.GetLabels(), src.GetLabels(), last.Labels))
+	dst.SetAnnotations(store.PruneMetadata(dst.GetAnnotations(), src.GetAnnotations(), last.Annotations))
+}
+
+// filterMetadata returns the labels or annotations with the given keys.
+func filterMetadata(m map[string]string, keys []string) map[string]string {
+	ret := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			ret[k] = v
+		}
+	}
+
+	return ret
+}
+
 func setMetadata(dst, src client.Object) error {
 	labs := store.MergeMetadata(dst.GetLabels(), src.GetLabels())
 	dst.SetLabels(labs)
@@ -3,6 +3,7 @@ package lens
 import (
 	"fmt"
 	"maps"
+	"slices"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -12,6 +13,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
@@ -38,6 +40,7 @@ func (l *ServiceLens) ApplyToResource(target client.Object) error {
 		return fmt.Errorf("service lens: invalid target type %T", target)
 	}
 
+	pruneMetadata(svc, &l.Service)
 	if err := setMetadata(svc, &l.Service); err != nil {
 		return err
 	}
@@ -56,6 +59,43 @@ func (l *ServiceLens) ApplyToStatus(_ client.Object) error {
 	return nil
 }
 
+// ApplyObject returns the object to send with server-side apply. The renderer starts from the
+// existing Service, so the labels and annotations not recorded as applied by the operator and
+// the spec fields allocated or defaulted by Kubernetes are removed to avoid claiming ownership.
+func (l *ServiceLens) ApplyObject() client.Object {
+	svc := l.Service.DeepCopy()
+
+	annotations := svc.GetAnnotations()
+	if _, ok := annotations[opdefault.LastAppliedMetadataAnnotationKey]; ok {
+		last := store.GetLastAppliedMetadata(svc)
+		svc.SetLabels(filterMetadata(svc.GetLabels(), last.Labels))
+		svc.SetAnnotations(filterMetadata(annotations,
+			append(last.Annotations, opdefault.LastAppliedMetadataAnnotationKey)))
+	}
+
+	svc.Spec.ClusterIP = ""
+	svc.Spec.ClusterIPs = nil
+	svc.Spec.HealthCheckNodePort = 0
+	svc.Spec.AllocateLoadBalancerNodePorts = nil
+	svc.Spec.LoadBalancerClass = nil
+	svc.Spec.InternalTrafficPolicy = nil
+	svc.Spec.SessionAffinityConfig = nil
+	if _, ok := annotations[opdefault.IPFamilyPolicyAnnotationKey]; !ok {
+		svc.Spec.IPFamilyPolicy = nil
+	}
+	if _, ok := annotations[opdefault.IPFamiliesAnnotationKey]; !ok {
+		svc.Spec.IPFamilies = nil
+	}
+	if !ownsNodePort(svc) {
+		for i := range svc.Spec.Ports {
+			svc.Spec.Ports[i].NodePort = 0
+		}
+	}
+	svc.Status = corev1.ServiceStatus{}
+
+	return svc
+}
+
 func (l *ServiceLens) DeepCopy() *ServiceLens {
 	return &ServiceLens{Service: *l.Service.DeepCopy()}
 }
@@ -64,8 +104,12 @@ func (l *ServiceLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
 
 // * Service.ObjectMeta.Labels / Service.ObjectMeta.Annotations / Service.ObjectMeta.OwnerReferences
 // - renderer: starts from existing Service (if present), enforces operator mandatory metadata,
-//   merges Gateway/GatewayConfig annotations, and sets Gateway owner reference.
-// - updater: merges top-level labels/annotations and updates owner reference via setMetadata/addOwnerRef.
+//   merges Gateway/GatewayConfig annotations, and sets Gateway owner reference. Records the
+//   applied label and annotation keys in the last-applied-metadata annotation, and removes the
+//   ones applied earlier but no longer requested.
+// - updater: removes the labels/annotations recorded in the last-applied-metadata annotation of
+//   the current object but no longer set in desired (pruneMetadata), then merges top-level
+//   labels/annotations and updates owner reference via setMetadata/addOwnerRef.
 //
 // * Service.Spec.Type
 // - renderer: derived from service-type annotations with fallback to operator default.
@@ -100,6 +144,11 @@ func (l *ServiceLens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
 // - renderer: set from the first Gateway requested IP address when present.
 // - updater: copied only when desired explicitly sets it; otherwise preserved from current.
 //
+// * Service.Spec.IPFamilyPolicy / Service.Spec.IPFamilies
+// - renderer: set from the ip-family-policy and ip-families annotations when present.
+// - updater: copied only when desired explicitly sets them; otherwise preserved from current
+//   (defaulted by the API server).
+//
 // * Service.Spec.LoadBalancerClass
 // - renderer: currently does not set.
 // - updater: preserved from current (externally managed/immutable).
@@ -114,6 +163,10 @@ func projectService(s, owned *corev1.Service) *corev1.Service {
 	ret.Spec.SessionAffinity = src.Spec.SessionAffinity
 	ret.Spec.ExternalTrafficPolicy = normalizeExternalTrafficPolicy(src.Spec.Type, src.Spec.ExternalTrafficPolicy)
 	ret.Spec.LoadBalancerIP = normalizeLoadBalancerIP(src, owned)
+	ret.Spec.IPFamilyPolicy = projectOwnedScalar(src.Spec.IPFamilyPolicy, owned.Spec.IPFamilyPolicy)
+	if owned.Spec.IPFamilies != nil {
+		ret.Spec.IPFamilies = slices.Clone(src.Spec.IPFamilies)
+	}
 	ret.Spec.Ports = make([]corev1.ServicePort, 0, len(src.Spec.Ports))
 	for i := range src.Spec.Ports {
 		p := src.Spec.Ports[i]
@@ -147,6 +200,11 @@ func applyServiceSpec(current, desired, owned *corev1.Service) {
 	if owned.Spec.LoadBalancerIP != "" {
 		current.Spec.LoadBalancerIP = desired.Spec.LoadBalancerIP
 	}
+
+	applyOwnedScalar(&current.Spec.IPFamilyPolicy, desired.Spec.IPFamilyPolicy)
+	if desired.Spec.IPFamilies != nil {
+		current.Spec.IPFamilies = slices.Clone(desired.Spec.IPFamilies)
+	}
 }
 
 func normalizeLoadBalancerIP(svc, owned *corev1.Service) string {
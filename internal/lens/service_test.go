@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
@@ -137,6 +138,55 @@ func TestServiceApplyPreservesExternalMetadata(t *testing.T) {
 		"service ownerrefs should keep external and add owned ownerref")
 }
 
+func TestServiceApplyPrunesLastAppliedMetadata(t *testing.T) {
+	current := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      "svc",
+		Namespace: "default",
+		Labels: map[string]string{
+			"external-label": "keep",
+			"old-label":      "remove",
+		},
+		Annotations: map[string]string{
+			"external-ann": "keep",
+			"owned-ann":    "set",
+			"old-ann":      "remove",
+		},
+	}}
+	store.SetLastAppliedMetadata(current,
+		map[string]string{"old-label": ""},
+		map[string]string{"owned-ann": "", "old-ann": ""})
+
+	desired := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      "svc",
+		Namespace: "default",
+		Annotations: map[string]string{
+			"owned-ann": "set",
+		},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Gateway",
+			Name:       "gw",
+		}},
+	}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}}
+	store.SetLastAppliedMetadata(desired, desired.GetLabels(), desired.GetAnnotations())
+
+	v := NewServiceLens(desired)
+	assert.False(t, v.EqualResource(current), "changed applied metadata should be detected")
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+
+	assert.Equal(t, map[string]string{"external-label": "keep"}, current.Labels,
+		"service labels should retain external labels only")
+	assert.Equal(t, "keep", current.Annotations["external-ann"],
+		"service annotations should retain external annotations")
+	assert.Equal(t, "set", current.Annotations["owned-ann"],
+		"service annotations should retain owned annotations")
+	assert.NotContains(t, current.Annotations, "old-ann",
+		"service annotations should remove annotations no longer applied")
+	assert.Equal(t, []string{"owned-ann"}, store.GetLastAppliedMetadata(current).Annotations,
+		"applied annotations should be recorded")
+	assert.True(t, v.EqualResource(current), "lens should match after apply")
+}
+
 func TestServiceApplyPreservesExternallyManagedSpecFields(t *testing.T) {
 	lbClass := "service.k8s.aws/nlb"
 	ipFamilyPolicy := corev1.IPFamilyPolicySingleStack
@@ -323,6 +373,40 @@ func TestServiceNodePortOwnership(t *testing.T) {
 	}
 }
 
+func TestServiceIPFamilyOwnership(t *testing.T) {
+	singleStack := corev1.IPFamilyPolicySingleStack
+	dualStack := corev1.IPFamilyPolicyRequireDualStack
+
+	current := loadBalancerService()
+	current.Spec.IPFamilyPolicy = &singleStack
+	current.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+
+	// not owned: preserved from current
+	desired := loadBalancerService()
+	desired.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Gateway",
+		Name:       "gw",
+	}}
+	current.OwnerReferences = desired.OwnerReferences
+	v := NewServiceLens(desired)
+	assert.True(t, v.EqualResource(current), "unowned ip families should not cause a diff")
+
+	// owned: drift is detected and applied
+	desired.Spec.IPFamilyPolicy = &dualStack
+	desired.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	v = NewServiceLens(desired)
+	assert.False(t, v.EqualResource(current), "owned ip families should be compared")
+
+	require.NoError(t, v.ApplyToResource(current), "apply failed")
+	require.NotNil(t, current.Spec.IPFamilyPolicy, "ipFamilyPolicy")
+	assert.Equal(t, corev1.IPFamilyPolicyRequireDualStack, *current.Spec.IPFamilyPolicy,
+		"ipFamilyPolicy after apply")
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+		current.Spec.IPFamilies, "ipFamilies after apply")
+	assert.True(t, v.EqualResource(current), "lens should match after apply")
+}
+
 func loadBalancerService() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
@@ -95,6 +95,12 @@ func (l *UDPRouteV1A2Lens) DeepCopy() *UDPRouteV1A2Lens {
 func (l *UDPRouteV1A2Lens) DeepCopyObject() runtime.Object { return l.DeepCopy() }
 
 func UDPRouteStatusEqual(current, desired gwapiv1a2.UDPRouteStatus) bool {
+	return RouteStatusEqual(current.RouteStatus, desired.RouteStatus)
+}
+
+// RouteStatusEqual compares the parent statuses of a route, ignoring condition timestamps and
+// the defaulted fields of the parent references.
+func RouteStatusEqual(current, desired gwapiv1.RouteStatus) bool {
 	normalized := desired.DeepCopy()
 	for i := range normalized.Parents {
 		dp := &normalized.Parents[i]
@@ -0,0 +1,57 @@
+package lens
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// V1A2RouteLens is the lens of the Gateway API routes rendered using the canonical UDPRoute
+// representation, e.g., TCPRoutes and TLSRoutes. Only the status of these routes is updated.
+type V1A2RouteLens[R stnrgwv1.V1A2Route] struct {
+	client.Object
+}
+
+func NewV1A2RouteLens[R stnrgwv1.V1A2Route](ro R) *V1A2RouteLens[R] {
+	return &V1A2RouteLens[R]{Object: ro.DeepCopyObject().(R)}
+}
+
+func (l *V1A2RouteLens[R]) EqualResource(_ client.Object) bool {
+	return true
+}
+
+func (l *V1A2RouteLens[R]) ApplyToResource(_ client.Object) error {
+	return nil
+}
+
+func (l *V1A2RouteLens[R]) EqualStatus(current client.Object) bool {
+	ro, ok := current.(R)
+	if !ok {
+		return false
+	}
+
+	return RouteStatusEqual(*stnrgwv1.V1A2RouteStatus(ro), *stnrgwv1.V1A2RouteStatus(l.route()))
+}
+
+func (l *V1A2RouteLens[R]) ApplyToStatus(target client.Object) error {
+	ro, ok := target.(R)
+	if !ok {
+		return fmt.Errorf("%T lens: invalid target type %T", l.Object, target)
+	}
+
+	stnrgwv1.V1A2RouteStatus(l.route()).DeepCopyInto(stnrgwv1.V1A2RouteStatus(ro))
+	return nil
+}
+
+func (l *V1A2RouteLens[R]) DeepCopy() *V1A2RouteLens[R] {
+	return NewV1A2RouteLens(l.route())
+}
+
+func (l *V1A2RouteLens[R]) DeepCopyObject() runtime.Object { return l.DeepCopy() }
+
+func (l *V1A2RouteLens[R]) route() R {
+	return l.Object.(R)
+}
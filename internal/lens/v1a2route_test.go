@@ -0,0 +1,44 @@
+package lens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestV1A2RouteLensStatus(t *testing.T) {
+	desired := &gwapiv1a2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "ro", Namespace: "default"}}
+	desired.Status.Parents = []gwapiv1.RouteParentStatus{{
+		ParentRef:      gwapiv1.ParentReference{Name: "gw"},
+		ControllerName: "stunner.l7mp.io/gateway-operator",
+		Conditions: []metav1.Condition{{
+			Type:   string(gwapiv1.RouteConditionAccepted),
+			Status: metav1.ConditionTrue,
+			Reason: string(gwapiv1.RouteReasonAccepted),
+		}},
+	}}
+
+	l, err := New(desired)
+	require.NoError(t, err, "lens")
+	v, ok := l.(*V1A2RouteLens[*gwapiv1a2.TLSRoute])
+	require.True(t, ok, "lens type")
+	assert.Equal(t, "ro", v.GetName(), "name")
+
+	current := &gwapiv1a2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "ro", Namespace: "default"}}
+	assert.False(t, v.EqualStatus(current), "status diff")
+	require.NoError(t, v.ApplyToStatus(current), "apply to status")
+	assert.True(t, v.EqualStatus(current), "equal after apply")
+	assert.Equal(t, desired.Status, current.Status, "status")
+
+	assert.False(t, v.EqualStatus(&gwapiv1a2.TCPRoute{}), "other route kind")
+	assert.Error(t, v.ApplyToStatus(&gwapiv1a2.TCPRoute{}), "other route kind")
+
+	// the lens holds a copy of the route
+	desired.Status.Parents = nil
+	assert.True(t, v.EqualStatus(current), "lens copy")
+	assert.IsType(t, &V1A2RouteLens[*gwapiv1a2.TLSRoute]{}, v.DeepCopyObject(), "deepcopy type")
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GatewayStats is the per-Gateway summary of a render.
+type GatewayStats struct {
+	ListenersRendered, ListenersConflicted, ListenersInvalid int
+	AttachedRoutes, Clusters, Endpoints                      int
+}
+
+// gateways tracks the Gateways that have metrics exported, along with their GatewayClass, so that
+// the series of deleted Gateways can be removed.
+var gateways = struct {
+	classes map[types.NamespacedName]string
+	lock    sync.Mutex
+}{classes: map[types.NamespacedName]string{}}
+
+// SetGatewayStats exports the render summary of a Gateway.
+func SetGatewayStats(class string, gw types.NamespacedName, s GatewayStats) {
+	trackGateway(class, gw)
+
+	GatewayListeners.WithLabelValues(class, gw.Namespace, gw.Name, "rendered").Set(float64(s.ListenersRendered))
+	GatewayListeners.WithLabelValues(class, gw.Namespace, gw.Name, "conflicted").Set(float64(s.ListenersConflicted))
+	GatewayListeners.WithLabelValues(class, gw.Namespace, gw.Name, "invalid").Set(float64(s.ListenersInvalid))
+	GatewayAttachedRoutes.WithLabelValues(class, gw.Namespace, gw.Name).Set(float64(s.AttachedRoutes))
+	GatewayClusters.WithLabelValues(class, gw.Namespace, gw.Name).Set(float64(s.Clusters))
+	GatewayEndpoints.WithLabelValues(class, gw.Namespace, gw.Name).Set(float64(s.Endpoints))
+}
+
+// RecordGatewayRenderError counts a render error of a Gateway.
+func RecordGatewayRenderError(class string, gw types.NamespacedName, reason string) {
+	trackGateway(class, gw)
+	GatewayRenderErrorsTotal.WithLabelValues(class, gw.Namespace, gw.Name, reason).Inc()
+}
+
+// PruneGatewayMetrics removes the series of the Gateways for which exists returns false. This
+// keeps the cardinality of the per-Gateway metrics bounded as Gateways are deleted.
+func PruneGatewayMetrics(exists func(gw types.NamespacedName) bool) {
+	gateways.lock.Lock()
+	defer gateways.lock.Unlock()
+
+	for gw := range gateways.classes {
+		if !exists(gw) {
+			deleteGatewayMetrics(gw)
+			delete(gateways.classes, gw)
+		}
+	}
+}
+
+// trackGateway registers a Gateway, removing the series with the old GatewayClass label if the
+// Gateway has been moved to a new GatewayClass.
+func trackGateway(class string, gw types.NamespacedName) {
+	gateways.lock.Lock()
+	defer gateways.lock.Unlock()
+
+	if old, ok := gateways.classes[gw]; ok && old != class {
+		deleteGatewayMetrics(gw)
+	}
+	gateways.classes[gw] = class
+}
+
+func deleteGatewayMetrics(gw types.NamespacedName) {
+	labels := prometheus.Labels{"namespace": gw.Namespace, "gateway": gw.Name}
+	GatewayListeners.DeletePartialMatch(labels)
+	GatewayAttachedRoutes.DeletePartialMatch(labels)
+	GatewayClusters.DeletePartialMatch(labels)
+	GatewayEndpoints.DeletePartialMatch(labels)
+	GatewayRenderErrorsTotal.DeletePartialMatch(labels)
+}
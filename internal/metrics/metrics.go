@@ -99,6 +99,40 @@ var (
 		Help: "Generation number of the last update acknowledged by the updater thread.",
 	})
 
+	// GatewayListeners is the number of listeners of a Gateway in the last render, split by
+	// status ("rendered", "conflicted" for listeners with a protocol/port conflict, and
+	// "invalid").
+	GatewayListeners = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stunner_gateway_operator_gateway_listeners",
+		Help: "Number of listeners of a Gateway in the last render, by status.",
+	}, []string{"gateway_class", "namespace", "gateway", "status"})
+
+	// GatewayAttachedRoutes is the number of routes attached to the rendered listeners of a
+	// Gateway.
+	GatewayAttachedRoutes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stunner_gateway_operator_gateway_attached_routes",
+		Help: "Number of routes attached to the listeners of a Gateway in the last render.",
+	}, []string{"gateway_class", "namespace", "gateway"})
+
+	// GatewayClusters is the number of clusters rendered for a Gateway.
+	GatewayClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stunner_gateway_operator_gateway_clusters",
+		Help: "Number of clusters rendered for a Gateway in the last render.",
+	}, []string{"gateway_class", "namespace", "gateway"})
+
+	// GatewayEndpoints is the number of endpoints in the clusters rendered for a Gateway.
+	GatewayEndpoints = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stunner_gateway_operator_gateway_endpoints",
+		Help: "Number of endpoints in the clusters rendered for a Gateway in the last render.",
+	}, []string{"gateway_class", "namespace", "gateway"})
+
+	// GatewayRenderErrorsTotal counts the render errors of a Gateway and the routes attached to
+	// it, split by the error reason (e.g., "PortUnavailable" or "BackendNotFound").
+	GatewayRenderErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stunner_gateway_operator_gateway_render_errors_total",
+		Help: "Total number of render errors of a Gateway and its routes, by reason.",
+	}, []string{"gateway_class", "namespace", "gateway", "reason"})
+
 	// OperatorLoopLastActive is the unix timestamp of the most recent operator
 	// main-loop select iteration. A stale value proves the loop is wedged.
 	OperatorLoopLastActive = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -184,6 +218,11 @@ func init() {
 		ReconcileEventsTotal,
 		Generation,
 		GenerationLastAcked,
+		GatewayListeners,
+		GatewayAttachedRoutes,
+		GatewayClusters,
+		GatewayEndpoints,
+		GatewayRenderErrorsTotal,
 		OperatorLoopLastActive,
 		RendererLoopLastActive,
 		UpdaterLoopLastActive,
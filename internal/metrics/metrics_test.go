@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestLoopAgeZeroBeforeFirstHeartbeat(t *testing.T) {
@@ -58,3 +60,28 @@ func TestStalenessThresholdAboveHistogramCap(t *testing.T) {
 	assert.Greater(t, LoopStalenessThreshold, 60*time.Second, "staleness > histogram cap")
 	assert.Less(t, LoopHeartbeatInterval, LoopStalenessThreshold, "heartbeat < staleness")
 }
+
+func TestPruneGatewayMetrics(t *testing.T) {
+	gw1 := types.NamespacedName{Namespace: "testnamespace", Name: "gateway-1"}
+	gw2 := types.NamespacedName{Namespace: "testnamespace", Name: "gateway-2"}
+
+	SetGatewayStats("class-1", gw1, GatewayStats{ListenersRendered: 2, Endpoints: 3})
+	SetGatewayStats("class-1", gw2, GatewayStats{ListenersRendered: 1})
+	RecordGatewayRenderError("class-1", gw2, "BackendNotFound")
+	assert.Equal(t, 3.0, testutil.ToFloat64(GatewayEndpoints.WithLabelValues("class-1", "testnamespace", "gateway-1")),
+		"endpoints")
+	assert.Equal(t, 6, testutil.CollectAndCount(GatewayListeners), "listener series")
+
+	// moving a Gateway to a new class removes the old series
+	SetGatewayStats("class-2", gw1, GatewayStats{ListenersRendered: 2})
+	assert.Equal(t, 6, testutil.CollectAndCount(GatewayListeners), "listener series")
+	assert.Equal(t, 2, testutil.CollectAndCount(GatewayEndpoints), "endpoint series")
+
+	// deleted Gateways are removed
+	PruneGatewayMetrics(func(gw types.NamespacedName) bool { return gw == gw1 })
+	assert.Equal(t, 3, testutil.CollectAndCount(GatewayListeners), "listener series")
+	assert.Equal(t, 0, testutil.CollectAndCount(GatewayRenderErrorsTotal), "error series")
+
+	PruneGatewayMetrics(func(gw types.NamespacedName) bool { return false })
+	assert.Equal(t, 0, testutil.CollectAndCount(GatewayListeners), "listener series")
+}
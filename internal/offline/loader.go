@@ -0,0 +1,244 @@
+package offline
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// readManifests decodes all objects from a list of files or directories. Directories are
+// walked recursively for .yaml, .yml and .json files, "-" stands for the standard input.
+func readManifests(scheme *runtime.Scheme, paths []string, stdin io.Reader) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	objs := []client.Object{}
+
+	for _, p := range paths {
+		if p == "-" {
+			docs, err := decodeManifest(decoder, stdin, "<stdin>")
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, docs...)
+			continue
+		}
+
+		if err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			// files given explicitly are always read, files found in a directory only if
+			// they look like manifests
+			if path != p {
+				switch strings.ToLower(filepath.Ext(path)) {
+				case ".yaml", ".yml", ".json":
+				default:
+					return nil
+				}
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			docs, err := decodeManifest(decoder, f, path)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, docs...)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return objs, nil
+}
+
+// decodeManifest decodes a multi-document YAML or JSON stream. Lists are flattened.
+func decodeManifest(decoder runtime.Decoder, r io.Reader, name string) ([]client.Object, error) {
+	objs := []client.Object{}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for i := 0; ; i++ {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", name, err)
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		o, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			// skip comment-only documents
+			if runtime.IsMissingKind(err) && isEmptyDocument(doc) {
+				continue
+			}
+			return nil, fmt.Errorf("error decoding document %d in %s: %w", i, name, err)
+		}
+
+		if list, ok := o.(*corev1.List); ok {
+			for j, item := range list.Items {
+				o, _, err := decoder.Decode(item.Raw, nil, nil)
+				if err != nil {
+					return nil, fmt.Errorf("error decoding item %d of document %d in %s: %w",
+						j, i, name, err)
+				}
+				obj, ok := o.(client.Object)
+				if !ok {
+					return nil, fmt.Errorf("invalid item %d of document %d in %s", j, i, name)
+				}
+				objs = append(objs, obj)
+			}
+			continue
+		}
+
+		obj, ok := o.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("invalid document %d in %s", i, name)
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+func isEmptyDocument(doc []byte) bool {
+	for _, l := range strings.Split(string(doc), "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// loadStores resets the global stores from a list of objects. Objects of unknown kinds are
+// returned. Namespaces that are referenced by an object but not given explicitly are created
+// with no labels.
+func loadStores(objs []client.Object) []client.Object {
+	var gatewayClasses, gatewayConfigs, gateways, udpRoutes, udpRoutesV1A2, tcpRoutes, tlsRoutes, services, endpoints,
+		endpointSlices, secrets, namespaces, staticServices, dataplanes, referenceGrants, nodes,
+		deployments, daemonSets, pdbs, hpas, unknown []client.Object
+
+	for _, o := range objs {
+		switch ro := o.(type) {
+		case *gwapiv1.GatewayClass:
+			gatewayClasses = append(gatewayClasses, o)
+		case *stnrgwv1.GatewayConfig:
+			gatewayConfigs = append(gatewayConfigs, o)
+		case *gwapiv1.Gateway:
+			gateways = append(gateways, o)
+		case *stnrgwv1.UDPRoute:
+			udpRoutes = append(udpRoutes, o)
+		case *gwapiv1a2.UDPRoute:
+			udpRoutesV1A2 = append(udpRoutesV1A2, stnrgwv1.ConvertV1A2UDPRouteToV1(ro))
+		case *gwapiv1a2.TCPRoute:
+			tcpRoutes = append(tcpRoutes, stnrgwv1.ConvertV1A2RouteToV1(ro))
+		case *gwapiv1a2.TLSRoute:
+			tlsRoutes = append(tlsRoutes, stnrgwv1.ConvertV1A2RouteToV1(ro))
+		case *corev1.Service:
+			services = append(services, o)
+		case *corev1.Endpoints:
+			endpoints = append(endpoints, o)
+		case *discoveryv1.EndpointSlice:
+			endpointSlices = append(endpointSlices, o)
+		case *corev1.Secret:
+			secrets = append(secrets, o)
+		case *corev1.Namespace:
+			namespaces = append(namespaces, o)
+		case *stnrgwv1.StaticService:
+			staticServices = append(staticServices, o)
+		case *stnrgwv1.Dataplane:
+			dataplanes = append(dataplanes, o)
+		case *gwapiv1b1.ReferenceGrant:
+			referenceGrants = append(referenceGrants, o)
+		case *corev1.Node:
+			nodes = append(nodes, o)
+		case *appv1.Deployment:
+			deployments = append(deployments, o)
+		case *appv1.DaemonSet:
+			daemonSets = append(daemonSets, o)
+		case *policyv1.PodDisruptionBudget:
+			pdbs = append(pdbs, o)
+		case *autoscalingv2.HorizontalPodAutoscaler:
+			hpas = append(hpas, o)
+		default:
+			unknown = append(unknown, o)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, ns := range namespaces {
+		known[ns.GetName()] = true
+	}
+	for _, o := range objs {
+		if ns := o.GetNamespace(); ns != "" && !known[ns] {
+			namespaces = append(namespaces, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: ns},
+			})
+			known[ns] = true
+		}
+	}
+
+	store.GatewayClasses.Reset(gatewayClasses)
+	store.GatewayConfigs.Reset(gatewayConfigs)
+	store.Gateways.Reset(gateways)
+	store.UDPRoutes.Reset(udpRoutes)
+	store.UDPRoutesV1A2.Reset(udpRoutesV1A2)
+	store.TCPRoutes.Reset(tcpRoutes)
+	store.TLSRoutes.Reset(tlsRoutes)
+	store.Services.Reset(services)
+	store.Endpoints.Reset(endpoints)
+	store.EndpointSlices.Reset(endpointSlices)
+	// the renderer looks up Secrets by name, so it is safe to load all Secrets into both stores
+	store.TLSSecrets.Reset(secrets)
+	store.AuthSecrets.Reset(secrets)
+	store.Namespaces.Reset(namespaces)
+	store.StaticServices.Reset(staticServices)
+	store.Dataplanes.Reset(dataplanes)
+	store.ReferenceGrants.Reset(referenceGrants)
+	store.Nodes.Reset(nodes)
+	store.Deployments.Reset(deployments)
+	store.DaemonSets.Reset(daemonSets)
+	store.PodDisruptionBudgets.Reset(pdbs)
+	store.HorizontalPodAutoscalers.Reset(hpas)
+
+	return unknown
+}
@@ -0,0 +1,316 @@
+// Package offline runs the rendering pipeline on a set of YAML manifests without a Kubernetes
+// cluster, for testing and debugging configurations.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
+	"github.com/l7mp/stunner-gateway-operator/internal/renderer"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+const (
+	OutputYAML = "yaml"
+	OutputJSON = "json"
+)
+
+// Options configures an offline render.
+type Options struct {
+	// Manifests is a list of files or directories to load the resources from. "-" stands for
+	// the standard input.
+	Manifests []string
+	// DataplaneMode is the dataplane mode, either "managed" or "legacy".
+	DataplaneMode string
+	// ControllerName is the controller name GatewayClasses must refer to.
+	ControllerName string
+	// EnableEndpointDiscovery enables EDS for rendering the backend endpoints.
+	EnableEndpointDiscovery bool
+	// Output is the output format, either "yaml" or "json".
+	Output string
+	// Stdin is the reader to use for the "-" manifest, default is os.Stdin.
+	Stdin io.Reader
+	// Scheme must know about all the resource types in the manifests.
+	Scheme *runtime.Scheme
+	Logger logr.Logger
+}
+
+// Result is the outcome of an offline render.
+type Result struct {
+	// Configs are the rendered stunnerd configs, sorted by name.
+	Configs []*stnrconfv1.StunnerConfig `json:"configs"`
+	// Resources are the Kubernetes resources the operator would create or update.
+	Resources []map[string]any `json:"resources"`
+	// Statuses are the statuses the operator would write back to the resources.
+	Statuses []map[string]any `json:"statuses"`
+}
+
+// Render loads the manifests, renders the configuration and writes the result to w.
+func Render(opts Options, w io.Writer) error {
+	var marshal func(any) ([]byte, error)
+	switch opts.Output {
+	case "", OutputYAML:
+		marshal = yaml.Marshal
+	case OutputJSON:
+		marshal = func(v any) ([]byte, error) {
+			out, err := json.MarshalIndent(v, "", "  ")
+			return append(out, '\n'), err
+		}
+	default:
+		return fmt.Errorf("unknown output format %q (must be either %q or %q)",
+			opts.Output, OutputYAML, OutputJSON)
+	}
+
+	res, err := RenderResult(opts)
+	if err != nil {
+		return err
+	}
+
+	out, err := marshal(res)
+	if err != nil {
+		return fmt.Errorf("error encoding output: %w", err)
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+// RenderResult loads the manifests and renders the configuration. Note that this resets the
+// global object stores and overrides some of the global config settings.
+func RenderResult(opts Options) (*Result, error) {
+	if opts.Scheme == nil {
+		return nil, errors.New("no scheme")
+	}
+
+	// NewDataplaneMode falls back to legacy mode silently, be explicit here
+	mode := config.NewDataplaneMode(opts.DataplaneMode)
+	if !strings.EqualFold(opts.DataplaneMode, mode.String()) {
+		return nil, fmt.Errorf("unknown dataplane mode %q (must be either %q or %q)",
+			opts.DataplaneMode, config.DataplaneModeManaged.String(), config.DataplaneModeLegacy.String())
+	}
+
+	if len(opts.Manifests) == 0 {
+		return nil, errors.New("no manifests")
+	}
+
+	log := opts.Logger
+	stdin := opts.Stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+
+	objs, err := readManifests(opts.Scheme, opts.Manifests, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range loadStores(objs) {
+		log.Info("Ignoring resource of unknown kind", "kind", kindOf(opts.Scheme, o),
+			"resource", store.GetObjectKey(o))
+	}
+
+	config.DataplaneMode = mode
+	if opts.ControllerName != "" {
+		config.ControllerName = opts.ControllerName
+	}
+	config.EnableEndpointDiscovery = opts.EnableEndpointDiscovery
+	config.EndpointSliceAvailable = true
+
+	updates := render(opts.Scheme, log)
+
+	return newResult(opts.Scheme, updates)
+}
+
+// render runs a single render pass and collects the updates sent to the operator.
+func render(scheme *runtime.Scheme, log logr.Logger) []*event.EventUpdate {
+	r := renderer.NewRenderer(renderer.RendererConfig{
+		Scheme:         scheme,
+		LicenseManager: licensemgr.NewStubManager("", log),
+		Logger:         log,
+	})
+
+	ch := make(chan event.Event)
+	r.SetOperatorChannel(event.NewEventChannel(ch))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Render(event.NewEventRender(1))
+	}()
+
+	updates := []*event.EventUpdate{}
+	for {
+		select {
+		case e := <-ch:
+			if u, ok := e.(*event.EventUpdate); ok {
+				updates = append(updates, u)
+			}
+		case <-done:
+			return updates
+		}
+	}
+}
+
+func newResult(scheme *runtime.Scheme, updates []*event.EventUpdate) (*Result, error) {
+	res := &Result{
+		Configs:   []*stnrconfv1.StunnerConfig{},
+		Resources: []map[string]any{},
+		Statuses:  []map[string]any{},
+	}
+
+	for _, u := range updates {
+		res.Configs = append(res.Configs, u.ConfigQueue...)
+
+		// in legacy mode the configs are rendered into ConfigMaps
+		for _, o := range u.UpsertQueue.ConfigMaps.Objects() {
+			cm, ok := o.(*corev1.ConfigMap)
+			if !ok {
+				continue
+			}
+			conf, err := store.UnpackConfigMap(cm)
+			if err != nil {
+				return nil, fmt.Errorf("error unpacking ConfigMap %s: %w",
+					store.GetObjectKey(cm), err)
+			}
+			res.Configs = append(res.Configs, &conf)
+		}
+
+		q := u.UpsertQueue
+		for _, s := range []store.Store{q.Services, q.ConfigMaps, q.Deployments, q.DaemonSets,
+			q.PodDisruptionBudgets, q.HorizontalPodAutoscalers} {
+			for _, o := range s.Objects() {
+				r, err := toResource(scheme, o)
+				if err != nil {
+					return nil, err
+				}
+				res.Resources = append(res.Resources, r)
+			}
+		}
+
+		for _, s := range []store.Store{q.GatewayClasses, q.GatewayConfigs, q.Dataplanes, q.Gateways,
+			q.UDPRoutes, q.UDPRoutesV1A2, q.TCPRoutes, q.TLSRoutes} {
+			for _, o := range s.Objects() {
+				st, err := toStatus(scheme, o)
+				if err != nil {
+					return nil, err
+				}
+				res.Statuses = append(res.Statuses, st)
+			}
+		}
+	}
+
+	// stores are unordered
+	sort.SliceStable(res.Configs, func(i, j int) bool {
+		return res.Configs[i].Admin.Name < res.Configs[j].Admin.Name
+	})
+	sortObjects(res.Resources)
+	sortObjects(res.Statuses)
+
+	return res, nil
+}
+
+// toResource converts an object into a generic map with the TypeMeta filled in.
+func toResource(scheme *runtime.Scheme, o client.Object) (map[string]any, error) {
+	o = o.DeepCopyObject().(client.Object)
+	gvk, err := apiutil.GVKForObject(o, scheme)
+	if err != nil {
+		return nil, err
+	}
+	o.GetObjectKind().SetGroupVersionKind(gvk)
+	o.SetManagedFields(nil)
+
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+}
+
+// toStatus returns the status of an object, along with the fields that identify the object.
+func toStatus(scheme *runtime.Scheme, o client.Object) (map[string]any, error) {
+	o = o.DeepCopyObject().(client.Object)
+	gvk, err := apiutil.GVKForObject(o, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+
+	status, ok := u["status"].(map[string]any)
+	if !ok {
+		status = map[string]any{}
+	}
+	clearTimestamps(status)
+
+	metadata := map[string]any{"name": o.GetName()}
+	if o.GetNamespace() != "" {
+		metadata["namespace"] = o.GetNamespace()
+	}
+
+	return map[string]any{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   metadata,
+		"status":     status,
+	}, nil
+}
+
+// clearTimestamps removes the lastTransitionTime from all conditions so that the output is
+// reproducible.
+func clearTimestamps(v any) {
+	switch x := v.(type) {
+	case map[string]any:
+		if _, ok := x["lastTransitionTime"]; ok {
+			if _, ok := x["type"]; ok {
+				delete(x, "lastTransitionTime")
+			}
+		}
+		for _, e := range x {
+			clearTimestamps(e)
+		}
+	case []any:
+		for _, e := range x {
+			clearTimestamps(e)
+		}
+	}
+}
+
+func sortObjects(objs []map[string]any) {
+	key := func(o map[string]any) string {
+		kind, _ := o["kind"].(string)
+		ns, name := "", ""
+		if m, ok := o["metadata"].(map[string]any); ok {
+			ns, _ = m["namespace"].(string)
+			name, _ = m["name"].(string)
+		}
+		return kind + "/" + ns + "/" + name
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return key(objs[i]) < key(objs[j]) })
+}
+
+func kindOf(scheme *runtime.Scheme, o runtime.Object) string {
+	if gvk, err := apiutil.GVKForObject(o, scheme); err == nil {
+		return gvk.Kind
+	}
+	if a, err := meta.TypeAccessor(o); err == nil {
+		return a.GetKind()
+	}
+	return "<unknown>"
+}
@@ -0,0 +1,171 @@
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+func testScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1a2.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1b1.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1.AddToScheme(scheme))
+	utilruntime.Must(stnrgwv1.AddToScheme(scheme))
+	return scheme
+}
+
+func findStatus(res *Result, kind, name string) map[string]any {
+	for _, s := range res.Statuses {
+		if s["kind"] == kind && s["metadata"].(map[string]any)["name"] == name {
+			return s["status"].(map[string]any)
+		}
+	}
+	return nil
+}
+
+func TestRenderManaged(t *testing.T) {
+	defer func(m config.DataplaneModeType) { config.DataplaneMode = m }(config.DataplaneMode)
+
+	res, err := RenderResult(Options{
+		Manifests:     []string{"testdata/managed"},
+		DataplaneMode: "managed",
+		Scheme:        testScheme(),
+		Logger:        logr.Discard(),
+	})
+	assert.NoError(t, err, "render")
+
+	assert.Len(t, res.Configs, 1, "configs len")
+	conf := res.Configs[0]
+	assert.Equal(t, "stunner/udp-gateway", conf.Admin.Name, "admin name")
+	assert.Len(t, conf.Listeners, 1, "listeners len")
+	assert.Equal(t, "stunner/udp-gateway/udp-listener", conf.Listeners[0].Name, "listener name")
+	assert.Len(t, conf.Clusters, 1, "clusters len")
+	assert.Equal(t, "stunner/media-plane", conf.Clusters[0].Name, "cluster name")
+
+	kinds := []string{}
+	for _, r := range res.Resources {
+		kinds = append(kinds, r["kind"].(string))
+	}
+	assert.Equal(t, []string{"Deployment", "Service"}, kinds, "resources")
+
+	assert.Len(t, res.Statuses, 5, "statuses len")
+	assert.Equal(t, "Dataplane", res.Statuses[0]["kind"], "statuses sorted")
+	for _, kind := range []string{"GatewayClass", "GatewayConfig", "Dataplane", "Gateway", "UDPRoute"} {
+		name := map[string]string{
+			"GatewayClass":  "stunner-gatewayclass",
+			"GatewayConfig": "stunner-gatewayconfig",
+			"Dataplane":     "default",
+			"Gateway":       "udp-gateway",
+			"UDPRoute":      "media-plane",
+		}[kind]
+		assert.NotNil(t, findStatus(res, kind, name), "status: %s", kind)
+	}
+
+	// the cross-namespace backend is allowed by the ReferenceGrant
+	rs := findStatus(res, "UDPRoute", "media-plane")
+	parents := rs["parents"].([]any)
+	assert.Len(t, parents, 1, "parents len")
+	conds := parents[0].(map[string]any)["conditions"].([]any)
+	for _, c := range conds {
+		c := c.(map[string]any)
+		assert.Equal(t, "True", c["status"], "route condition: %s", c["type"])
+		assert.NotContains(t, c, "lastTransitionTime", "timestamps cleared")
+	}
+}
+
+func TestRenderLegacy(t *testing.T) {
+	defer func(m config.DataplaneModeType) { config.DataplaneMode = m }(config.DataplaneMode)
+
+	res, err := RenderResult(Options{
+		Manifests:     []string{"testdata/legacy.yaml"},
+		DataplaneMode: "legacy",
+		Scheme:        testScheme(),
+		Logger:        logr.Discard(),
+	})
+	assert.NoError(t, err, "render")
+
+	assert.Len(t, res.Configs, 1, "configs len")
+	assert.Equal(t, opdefault.DefaultStunnerdInstanceName, res.Configs[0].Admin.Name, "admin name")
+	assert.Len(t, res.Configs[0].Listeners, 1, "listeners len")
+	assert.Equal(t, "stunner/udp-gateway/udp-listener", res.Configs[0].Listeners[0].Name, "listener name")
+
+	assert.Len(t, res.Resources, 2, "resources len")
+	assert.Equal(t, "ConfigMap", res.Resources[0]["kind"], "configmap")
+	assert.Equal(t, "v1", res.Resources[0]["apiVersion"], "configmap apiVersion")
+	assert.Equal(t, "Service", res.Resources[1]["kind"], "service")
+}
+
+func TestRenderOutput(t *testing.T) {
+	defer func(m config.DataplaneModeType) { config.DataplaneMode = m }(config.DataplaneMode)
+
+	manifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "stunner.l7mp.io/v1",
+		"kind":       "StaticService",
+		"metadata":   map[string]any{"name": "static", "namespace": "default"},
+		"spec":       map[string]any{"prefixes": []string{"10.0.0.0/8"}},
+	})
+	assert.NoError(t, err, "marshal")
+
+	opts := Options{
+		Manifests:     []string{"-"},
+		DataplaneMode: "managed",
+		Output:        OutputJSON,
+		Stdin:         bytes.NewReader(manifest),
+		Scheme:        testScheme(),
+		Logger:        logr.Discard(),
+	}
+
+	// no GatewayClasses: empty output
+	buf := &bytes.Buffer{}
+	assert.NoError(t, Render(opts, buf), "render json")
+	res := Result{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &res), "unmarshal")
+	assert.Empty(t, res.Configs, "configs")
+	assert.Empty(t, res.Statuses, "statuses")
+
+	opts.Output = "xml"
+	opts.Stdin = bytes.NewReader(manifest)
+	assert.Error(t, Render(opts, &bytes.Buffer{}), "invalid output")
+}
+
+func TestRenderErrors(t *testing.T) {
+	defer func(m config.DataplaneModeType) { config.DataplaneMode = m }(config.DataplaneMode)
+
+	opts := Options{
+		Manifests:     []string{"testdata/invalid.yaml"},
+		DataplaneMode: "managed",
+		Scheme:        testScheme(),
+		Logger:        logr.Discard(),
+	}
+	_, err := RenderResult(opts)
+	assert.Error(t, err, "invalid manifest")
+	assert.True(t, strings.Contains(err.Error(), "testdata/invalid.yaml"), "error names the file")
+
+	opts.Manifests = []string{"testdata/nonexistent"}
+	_, err = RenderResult(opts)
+	assert.Error(t, err, "missing manifest")
+
+	opts.Manifests = []string{"testdata/legacy.yaml"}
+	opts.DataplaneMode = "dummy"
+	_, err = RenderResult(opts)
+	assert.Error(t, err, "invalid dataplane mode")
+}
@@ -3,21 +3,26 @@ package operator
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/controllers"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
 	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
 )
@@ -31,6 +36,7 @@ var scheme = runtime.NewScheme()
 
 func init() {
 	_ = gwapiv1a2.AddToScheme(scheme) //nolint:staticcheck
+	_ = gwapiv1b1.AddToScheme(scheme) //nolint:staticcheck
 	_ = gwapiv1.AddToScheme(scheme)   //nolint:staticcheck
 	_ = stnrgwv1.AddToScheme(scheme)  //nolint:staticcheck
 	_ = apiv1.AddToScheme(scheme)     //nolint:staticcheck
@@ -41,7 +47,9 @@ type OperatorConfig struct {
 	ControllerName string
 	RenderCh       chan event.Event
 	ConfigCh       chan event.Event
+	ConfigAckCh    chan event.Event
 	UpdaterCh      chan event.Event
+	RecordUpdates  bool
 	Logger         logr.Logger
 }
 
@@ -51,11 +59,18 @@ type Operator struct {
 	gwConfC, dpC, gwC, rouC, nodeC controllers.Controller
 	operatorCh                     event.EventChannel
 	renderCh, updaterCh, configCh  chan event.Event
+	configAckCh                    chan event.Event
 	manager                        manager.Manager
 	tracker                        *config.ProgressTracker
 	progressReporters              []config.ProgressReporter
 	finalizer                      bool
 	gen, lastAckedGen              int
+	recordUpdates                  bool
+	lastUpdate                     *event.EventUpdate
+	changes                        map[event.ObjectKey]bool
+	fullRender                     bool
+	throttleCtx                    context.Context
+	throttleSpan                   trace.Span
 	ackLock                        sync.RWMutex
 	log, logger                    logr.Logger
 }
@@ -66,16 +81,18 @@ func NewOperator(cfg OperatorConfig) *Operator {
 
 	opCh := make(chan event.Event, channelBufferSize)
 	return &Operator{
-		mgr:          cfg.Manager,
-		renderCh:     cfg.RenderCh,
-		operatorCh:   event.NewEventChannel(opCh),
-		updaterCh:    cfg.UpdaterCh,
-		configCh:     cfg.ConfigCh,
-		tracker:      config.NewProgressTracker(),
-		finalizer:    config.EnableFinalizer,
-		gen:          0,
-		lastAckedGen: -1,
-		logger:       cfg.Logger,
+		mgr:           cfg.Manager,
+		renderCh:      cfg.RenderCh,
+		operatorCh:    event.NewEventChannel(opCh),
+		updaterCh:     cfg.UpdaterCh,
+		configCh:      cfg.ConfigCh,
+		configAckCh:   cfg.ConfigAckCh,
+		tracker:       config.NewProgressTracker(),
+		finalizer:     config.EnableFinalizer,
+		gen:           0,
+		lastAckedGen:  -1,
+		recordUpdates: cfg.RecordUpdates,
+		logger:        cfg.Logger,
 	}
 }
 
@@ -158,6 +175,9 @@ func (o *Operator) eventLoop(ctx context.Context, cancel context.CancelFunc) {
 			metrics.RecordOperatorHeartbeat()
 			switch e.GetType() {
 			case event.EventTypeUpdate:
+				if o.recordUpdates {
+					o.setLastUpdate(e.(*event.EventUpdate).DeepCopy())
+				}
 				if n := sendCoalesced(o.updaterCh, e); n > 0 {
 					o.log.V(3).Info("Coalesced stale updater events", "count", n)
 				}
@@ -166,9 +186,16 @@ func (o *Operator) eventLoop(ctx context.Context, cancel context.CancelFunc) {
 				}
 
 			case event.EventTypeReconcile:
+				// collect the changes even if the request is throttled
+				re := e.(*event.EventReconcile)
+				o.recordChanges(re)
+
 				// rate-limit rendering requests before passing on to the renderer
 				// render request in progress: do nothing
 				if throttling {
+					if o.throttleSpan != nil && re.SpanContext().IsValid() {
+						o.throttleSpan.AddLink(trace.Link{SpanContext: re.SpanContext()})
+					}
 					metrics.ReconcileEventsTotal.WithLabelValues("throttled").Inc()
 					o.log.V(3).Info("Rendering request throttled", "event",
 						e.String())
@@ -180,15 +207,26 @@ func (o *Operator) eventLoop(ctx context.Context, cancel context.CancelFunc) {
 				throttling = true
 				throttler.Reset(config.ThrottleTimeout)
 				o.tracker.ProgressUpdate(1)
+				o.throttleCtx, o.throttleSpan = tracing.Start(re.GetTraceContext(ctx), "Throttle")
 
 				o.log.V(3).Info("Initiating new rendering request", "event",
 					e.String())
 
 			case event.EventTypeAck:
 				// administer
-				gen := e.(*event.EventAck).Generation
+				ack := e.(*event.EventAck)
+				gen := ack.Generation
+				_, span := tracing.Start(ack.GetTraceContext(ctx), "Ack",
+					trace.WithAttributes(attribute.Int("generation", gen)))
 				o.setLastAckedGeneration(gen)
 				metrics.GenerationLastAcked.Set(float64(gen))
+				span.End()
+
+				// let the config discovery server know the update has been applied:
+				// only the latest ack matters
+				if o.configAckCh != nil {
+					sendCoalesced(o.configAckCh, ack)
+				}
 
 			default:
 				o.log.Info("Internal error: operator received a request it should "+
@@ -210,9 +248,9 @@ func (o *Operator) eventLoop(ctx context.Context, cancel context.CancelFunc) {
 
 			o.log.Info("Starting new reconcile generation", "generation", o.gen,
 				"last-acked-generation", o.GetLastAckedGeneration())
-			o.gen += 1
+			o.setGeneration(o.gen + 1)
 			metrics.Generation.Set(float64(o.gen))
-			o.renderCh <- event.NewEventRender(o.gen)
+			o.renderCh <- o.newRenderEvent(ctx)
 
 		case <-ctx.Done():
 			o.Terminate()
@@ -249,9 +287,56 @@ func (o *Operator) Terminate() {
 	o.operatorCh.Put()
 }
 
+// recordChanges collects the changes from a reconcile event. A reconcile event with no changes
+// requests a full render.
+func (o *Operator) recordChanges(e *event.EventReconcile) {
+	if len(e.Changes) == 0 {
+		o.fullRender = true
+		return
+	}
+
+	if o.changes == nil {
+		o.changes = map[event.ObjectKey]bool{}
+	}
+	for _, k := range e.Changes {
+		o.changes[k] = true
+	}
+}
+
+// newRenderEvent creates a new render event with the changes collected since the last render. The
+// render event inherits the trace context of the throttle span, which is ended here.
+func (o *Operator) newRenderEvent(ctx context.Context) *event.EventRender {
+	e := event.NewEventRender(o.gen)
+	e.SetTraceContext(ctx)
+	if o.throttleSpan != nil {
+		e.SetTraceContext(o.throttleCtx)
+		o.throttleSpan.SetAttributes(attribute.Int("generation", o.gen),
+			attribute.Bool("full-render", o.fullRender || len(o.changes) == 0),
+			attribute.Int("changes", len(o.changes)))
+		o.throttleSpan.End()
+		o.throttleCtx, o.throttleSpan = nil, nil
+	}
+
+	if !o.fullRender && len(o.changes) > 0 {
+		e.Changes = make([]event.ObjectKey, 0, len(o.changes))
+		for k := range o.changes {
+			e.Changes = append(e.Changes, k)
+		}
+		sort.Slice(e.Changes, func(i, j int) bool {
+			return e.Changes[i].String() < e.Changes[j].String()
+		})
+	}
+
+	o.changes = nil
+	o.fullRender = false
+
+	return e
+}
+
 // sendCoalesced delivers e to ch with coalescing semantics: it loops until the
 // send succeeds, draining stale pending events to make room whenever the channel
-// is full. Returns the number of stale events dropped.
+// is full. Stale update events are merged into e, since an incremental render
+// produces only a partial update. Returns the number of stale events dropped.
 func sendCoalesced(ch chan event.Event, e event.Event) int {
 	dropped := 0
 	for {
@@ -260,7 +345,8 @@ func sendCoalesced(ch chan event.Event, e event.Event) int {
 			return dropped
 		default:
 			select {
-			case <-ch:
+			case stale := <-ch:
+				e = coalesce(e, stale)
 				dropped++
 			default:
 				// A concurrent reader emptied the channel between the failed
@@ -271,6 +357,24 @@ func sendCoalesced(ch chan event.Event, e event.Event) int {
 	}
 }
 
+// coalesce merges a stale update event into a copy of e.
+func coalesce(e, stale event.Event) event.Event {
+	u, ok := e.(*event.EventUpdate)
+	if !ok {
+		return e
+	}
+	s, ok := stale.(*event.EventUpdate)
+	if !ok {
+		return e
+	}
+
+	merged := u.DeepCopy()
+	merged.SetRequestAck(u.GetRequestAck())
+	merged.Coalesce(s)
+
+	return merged
+}
+
 // Finalize invalidates the status on all the managed resources. Note that Finalize must be called
 // with the main even loop blocked.
 func (o *Operator) Finalize() {
@@ -8,6 +8,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
@@ -154,3 +160,216 @@ func TestEventLoopStillProcessesReconcileWhileCDSBlocked(t *testing.T) {
 			cap(configCh))
 	}
 }
+
+// TestRenderEventCarriesChanges asserts that the render event collects the changes from the
+// reconcile events since the last render, and that a reconcile event with no changes requests a
+// full render.
+func TestRenderEventCarriesChanges(t *testing.T) {
+	o := newTestOperator(nil, nil, nil, nil)
+
+	k1 := event.ObjectKey{Kind: "EndpointSlice", NamespacedName: types.NamespacedName{
+		Namespace: "testnamespace", Name: "testendpointslice"}}
+	k2 := event.ObjectKey{Kind: "Gateway", NamespacedName: types.NamespacedName{
+		Namespace: "testnamespace", Name: "gateway-1"}}
+
+	o.recordChanges(event.NewEventReconcile(k2))
+	o.recordChanges(event.NewEventReconcile(k1, k2))
+	e := o.newRenderEvent(context.Background())
+	assert.Equal(t, []event.ObjectKey{k1, k2}, e.Changes, "changes")
+
+	// changes are reset after each render
+	e = o.newRenderEvent(context.Background())
+	assert.Empty(t, e.Changes, "no changes")
+
+	// an empty reconcile event masks all changes
+	o.recordChanges(event.NewEventReconcile(k1))
+	o.recordChanges(event.NewEventReconcile())
+	o.recordChanges(event.NewEventReconcile(k2))
+	e = o.newRenderEvent(context.Background())
+	assert.Empty(t, e.Changes, "full render")
+
+	o.recordChanges(event.NewEventReconcile(k1))
+	e = o.newRenderEvent(context.Background())
+	assert.Equal(t, []event.ObjectKey{k1}, e.Changes, "changes after full render")
+}
+
+// TestSendCoalescedMergesUpdates asserts that stale updates are merged into the new update
+// instead of being dropped when the channel is full.
+func TestSendCoalescedMergesUpdates(t *testing.T) {
+	svc := func(name, ip string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace", Name: name},
+			Spec:       corev1.ServiceSpec{ClusterIP: ip},
+		}
+	}
+
+	ch := make(chan event.Event, 1)
+
+	stale := event.NewEventUpdate(1)
+	stale.UpsertQueue.Services.Upsert(svc("svc-1", "1.1.1.1"))
+	stale.UpsertQueue.Services.Upsert(svc("svc-2", "2.2.2.2"))
+	stale.DeleteQueue.Services.Upsert(svc("svc-3", ""))
+	assert.Equal(t, 0, sendCoalesced(ch, stale), "no drops")
+
+	u := event.NewEventUpdate(2)
+	u.UpsertQueue.Services.Upsert(svc("svc-1", "3.3.3.3"))
+	u.DeleteQueue.Services.Upsert(svc("svc-2", ""))
+	u.SetRequestAck(true)
+	assert.Equal(t, 1, sendCoalesced(ch, u), "one drop")
+
+	e := <-ch
+	merged, ok := e.(*event.EventUpdate)
+	require.True(t, ok, "update event")
+	assert.Equal(t, 2, merged.Generation, "generation")
+	assert.True(t, merged.GetRequestAck(), "ack request")
+
+	// the new update takes precedence
+	upserts := merged.UpsertQueue.Services
+	assert.Equal(t, 1, upserts.Len(), "upsert queue len")
+	s1 := upserts.Get(types.NamespacedName{Namespace: "testnamespace", Name: "svc-1"})
+	require.NotNil(t, s1, "svc-1 upserted")
+	assert.Equal(t, "3.3.3.3", s1.(*corev1.Service).Spec.ClusterIP, "svc-1 from the new update")
+
+	deletes := merged.DeleteQueue.Services
+	assert.Equal(t, 2, deletes.Len(), "delete queue len")
+	assert.NotNil(t, deletes.Get(types.NamespacedName{Namespace: "testnamespace", Name: "svc-2"}),
+		"svc-2 deleted")
+	assert.NotNil(t, deletes.Get(types.NamespacedName{Namespace: "testnamespace", Name: "svc-3"}),
+		"svc-3 deleted by the stale update")
+}
+
+// TestEventLoopPropagatesTraceContext asserts that the render event inherits the trace of the
+// reconcile event that initiated the render, and that the ack is traced in the same trace.
+func TestEventLoopPropagatesTraceContext(t *testing.T) {
+	origThrottle := config.ThrottleTimeout
+	config.ThrottleTimeout = 10 * time.Millisecond
+	defer func() { config.ThrottleTimeout = origThrottle }()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	renderCh := make(chan event.Event, 10)
+	opCh := make(chan event.Event, channelBufferSize)
+	o := newTestOperator(opCh, nil, nil, renderCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.operatorCh.Get()
+	go o.eventLoop(ctx, nil)
+
+	tracer := tp.Tracer("test")
+	rctx, root := tracer.Start(context.Background(), "root")
+	e := event.NewEventReconcile()
+	e.SetTraceContext(rctx)
+	opCh <- e
+	root.End()
+
+	// a throttled reconcile event is linked to the throttle span
+	lctx, linked := tracer.Start(context.Background(), "linked")
+	e = event.NewEventReconcile()
+	e.SetTraceContext(lctx)
+	opCh <- e
+	linked.End()
+
+	var render *event.EventRender
+	select {
+	case re := <-renderCh:
+		render = re.(*event.EventRender)
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "timeout", "no render event within 3s")
+	}
+
+	traceID := root.SpanContext().TraceID()
+	assert.Equal(t, traceID, render.SpanContext().TraceID(), "render event trace id")
+
+	ack := event.NewEventAck(render.Generation)
+	ack.TraceContext = render.TraceContext
+	opCh <- ack
+	assert.Eventually(t, func() bool { return o.GetLastAckedGeneration() == render.Generation },
+		3*time.Second, 10*time.Millisecond, "ack processed")
+
+	spans := map[string]tracetest.SpanStub{}
+	assert.Eventually(t, func() bool {
+		for _, s := range exp.GetSpans() {
+			spans[s.Name] = s
+		}
+		_, ok := spans["Ack"]
+		return ok
+	}, 3*time.Second, 10*time.Millisecond, "ack span")
+
+	throttle, ok := spans["Throttle"]
+	require.True(t, ok, "throttle span")
+	assert.Equal(t, traceID, throttle.SpanContext.TraceID(), "throttle span trace id")
+	assert.Equal(t, root.SpanContext().SpanID(), throttle.Parent.SpanID(), "throttle span parent")
+	assert.Equal(t, throttle.SpanContext.SpanID(), render.SpanContext().SpanID(),
+		"render event parent")
+	require.Len(t, throttle.Links, 1, "throttle span links")
+	assert.Equal(t, linked.SpanContext().SpanID(), throttle.Links[0].SpanContext.SpanID(),
+		"throttle span link")
+	assert.Equal(t, traceID, spans["Ack"].SpanContext.TraceID(), "ack span trace id")
+}
+
+// TestEventLoopForwardsAcks asserts that acks are forwarded to the config discovery server and
+// only the latest ack is kept when the server lags behind.
+func TestEventLoopForwardsAcks(t *testing.T) {
+	opCh := make(chan event.Event, channelBufferSize)
+	ackCh := make(chan event.Event, 1)
+	o := newTestOperator(opCh, nil, nil, nil)
+	o.configAckCh = ackCh
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.operatorCh.Get()
+	go o.eventLoop(ctx, nil)
+
+	opCh <- event.NewEventAck(1)
+	opCh <- event.NewEventAck(2)
+	assert.Eventually(t, func() bool { return o.GetLastAckedGeneration() == 2 },
+		3*time.Second, 10*time.Millisecond, "acks processed")
+
+	e := <-ackCh
+	ack, ok := e.(*event.EventAck)
+	require.True(t, ok, "ack event")
+	assert.Equal(t, 2, ack.Generation, "latest ack")
+	assert.Len(t, ackCh, 0, "stale ack dropped")
+}
+
+func TestEventLoopRecordsLastUpdate(t *testing.T) {
+	for _, record := range []bool{false, true} {
+		opCh := make(chan event.Event, channelBufferSize)
+		updaterCh := make(chan event.Event, 1)
+		configCh := make(chan event.Event, 1)
+		o := newTestOperator(opCh, updaterCh, configCh, nil)
+		o.recordUpdates = record
+
+		ctx, cancel := context.WithCancel(context.Background())
+		o.operatorCh.Get()
+		go o.eventLoop(ctx, nil)
+
+		e := event.NewEventUpdate(1)
+		e.UpsertQueue.Services.Upsert(&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testnamespace", Name: "gateway-1"}})
+		opCh <- e
+		<-updaterCh
+		<-configCh
+
+		u := o.GetLastUpdate()
+		if !record {
+			assert.Nil(t, u, "not recorded")
+			cancel()
+			continue
+		}
+
+		require.NotNil(t, u, "recorded")
+		assert.Equal(t, 1, u.Generation, "generation")
+		assert.Equal(t, 1, u.UpsertQueue.Services.Len(), "upsert queue")
+		assert.NotSame(t, e, u, "copied")
+		assert.NotSame(t, u, o.GetLastUpdate(), "each call returns a new copy")
+		cancel()
+	}
+}
@@ -52,6 +52,20 @@ func (o *Operator) ProgressReport() int {
 	return progress + op
 }
 
+// GetGeneration returns the current render generation.
+func (o *Operator) GetGeneration() int {
+	o.ackLock.RLock()
+	defer o.ackLock.RUnlock()
+	return o.gen
+}
+
+// setGeneration sets the current render generation.
+func (o *Operator) setGeneration(gen int) {
+	o.ackLock.Lock()
+	defer o.ackLock.Unlock()
+	o.gen = gen
+}
+
 // GetLastAckedGeneration returns the last update generation acknowledged by the updater.
 func (o *Operator) GetLastAckedGeneration() int {
 	o.ackLock.RLock()
@@ -66,6 +80,24 @@ func (o *Operator) setLastAckedGeneration(gen int) {
 	o.lastAckedGen = gen
 }
 
+// GetLastUpdate returns a copy of the last update event, or nil if no update has been rendered yet
+// or recording updates is disabled.
+func (o *Operator) GetLastUpdate() *event.EventUpdate {
+	o.ackLock.RLock()
+	defer o.ackLock.RUnlock()
+	if o.lastUpdate == nil {
+		return nil
+	}
+	return o.lastUpdate.DeepCopy()
+}
+
+// setLastUpdate stores the last update event.
+func (o *Operator) setLastUpdate(e *event.EventUpdate) {
+	o.ackLock.Lock()
+	defer o.ackLock.Unlock()
+	o.lastUpdate = e
+}
+
 // Stabilize waits until all internal progress has stopped by checking if there's no activity 3 times.
 func (o *Operator) Stabilize() {
 	d := 50 * time.Millisecond
@@ -0,0 +1,192 @@
+// Package recorder emits Kubernetes Events on the resources managed by the operator, with
+// deduplication and rate limiting so that a render loop does not flood the events API.
+package recorder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+)
+
+const (
+	// ReasonRenderFailed is the reason for critical render errors.
+	ReasonRenderFailed = "RenderFailed"
+	// ReasonRenderWarning is the reason for non-critical render errors.
+	ReasonRenderWarning = "RenderWarning"
+	// ReasonUpdateFailed is the reason for failing to create or update a resource.
+	ReasonUpdateFailed = "UpdateFailed"
+	// ReasonStatusUpdateFailed is the reason for failing to update the status of a resource.
+	ReasonStatusUpdateFailed = "StatusUpdateFailed"
+
+	// ActionRender is the action for render events.
+	ActionRender = "Render"
+	// ActionUpdate is the action for updater events.
+	ActionUpdate = "Update"
+
+	// maxEntries is the number of tracked objects above which expired entries are pruned.
+	maxEntries = 1024
+)
+
+// Recorder emits Kubernetes Events.
+type Recorder interface {
+	// Warning emits a warning Event on an object.
+	Warning(o client.Object, reason, action, message string)
+	// Normal emits a normal Event on an object.
+	Normal(o client.Object, reason, action, message string)
+}
+
+type RecorderConfig struct {
+	// EventRecorder is the recorder to send the events to, usually obtained from the manager.
+	EventRecorder events.EventRecorder
+	// DedupInterval is the time interval during which identical events on the same object are
+	// suppressed.
+	DedupInterval time.Duration
+	// RateLimit is the average time interval between subsequent events on the same object.
+	RateLimit time.Duration
+	// Burst is the maximum number of events on the same object in a burst.
+	Burst  int
+	Logger logr.Logger
+}
+
+type entry struct {
+	limiter  *rate.Limiter
+	events   map[string]time.Time
+	lastSeen time.Time
+}
+
+type recorder struct {
+	recorder      events.EventRecorder
+	dedupInterval time.Duration
+	rateLimit     rate.Limit
+	burst         int
+	entries       map[string]*entry
+	now           func() time.Time
+	lock          sync.Mutex
+	log           logr.Logger
+}
+
+// NewRecorder creates a new event recorder. Zero values in the config are replaced with the
+// defaults.
+func NewRecorder(cfg RecorderConfig) Recorder {
+	dedupInterval := cfg.DedupInterval
+	if dedupInterval == 0 {
+		dedupInterval = opdefault.DefaultEventDedupInterval
+	}
+	rateLimit := cfg.RateLimit
+	if rateLimit == 0 {
+		rateLimit = opdefault.DefaultEventRateLimit
+	}
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = opdefault.DefaultEventBurst
+	}
+
+	return &recorder{
+		recorder:      cfg.EventRecorder,
+		dedupInterval: dedupInterval,
+		rateLimit:     rate.Every(rateLimit),
+		burst:         burst,
+		entries:       map[string]*entry{},
+		now:           time.Now,
+		log:           cfg.Logger.WithName("recorder"),
+	}
+}
+
+func (r *recorder) Warning(o client.Object, reason, action, message string) {
+	r.event(o, corev1.EventTypeWarning, reason, action, message)
+}
+
+func (r *recorder) Normal(o client.Object, reason, action, message string) {
+	r.event(o, corev1.EventTypeNormal, reason, action, message)
+}
+
+func (r *recorder) event(o client.Object, eventType, reason, action, message string) {
+	if o == nil || r.recorder == nil {
+		return
+	}
+
+	if !r.allow(o, eventType, reason, action, message) {
+		return
+	}
+
+	r.log.V(2).Info("Emitting event", "object", objectKey(o), "type", eventType,
+		"reason", reason, "message", message)
+
+	r.recorder.Eventf(o, nil, eventType, reason, action, "%s", message)
+}
+
+// allow returns true if an event can be emitted: the same event was not emitted on the object
+// within the dedup interval and the rate limit for the object is not exceeded.
+func (r *recorder) allow(o client.Object, eventType, reason, action, message string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if len(r.entries) > maxEntries {
+		r.prune(now)
+	}
+
+	key := objectKey(o)
+	e, ok := r.entries[key]
+	if !ok {
+		e = &entry{
+			limiter: rate.NewLimiter(r.rateLimit, r.burst),
+			events:  map[string]time.Time{},
+		}
+		r.entries[key] = e
+	}
+	e.lastSeen = now
+
+	id := fmt.Sprintf("%s/%s/%s/%s", eventType, reason, action, message)
+	if last, ok := e.events[id]; ok && now.Sub(last) < r.dedupInterval {
+		r.log.V(4).Info("Suppressing duplicate event", "object", key, "reason", reason)
+		return false
+	}
+
+	if !e.limiter.AllowN(now, 1) {
+		r.log.V(4).Info("Suppressing event: rate limit exceeded", "object", key, "reason", reason)
+		return false
+	}
+
+	e.events[id] = now
+
+	return true
+}
+
+// prune removes the objects with no events within the dedup interval.
+func (r *recorder) prune(now time.Time) {
+	for key, e := range r.entries {
+		if now.Sub(e.lastSeen) >= r.dedupInterval {
+			delete(r.entries, key)
+			continue
+		}
+		for id, last := range e.events {
+			if now.Sub(last) >= r.dedupInterval {
+				delete(e.events, id)
+			}
+		}
+	}
+}
+
+// objectKey identifies an object: the UID makes sure a recreated object is not affected by the
+// events emitted on its predecessor.
+func objectKey(o client.Object) string {
+	return fmt.Sprintf("%T/%s/%s/%s", o, o.GetNamespace(), o.GetName(), o.GetUID())
+}
+
+// noopRecorder discards all events.
+type noopRecorder struct{}
+
+// NewNoopRecorder creates a recorder that discards all events.
+func NewNoopRecorder() Recorder { return &noopRecorder{} }
+
+func (*noopRecorder) Warning(_ client.Object, _, _, _ string) {}
+func (*noopRecorder) Normal(_ client.Object, _, _, _ string)  {}
@@ -0,0 +1,110 @@
+package recorder
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func testGateway(name, uid string) *gwapiv1.Gateway {
+	return &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "testnamespace",
+		Name:      name,
+		UID:       types.UID("uid-" + uid),
+	}}
+}
+
+func drain(ch chan string) []string {
+	ret := []string{}
+	for {
+		select {
+		case e := <-ch:
+			ret = append(ret, e)
+		default:
+			return ret
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	fake := events.NewFakeRecorder(100)
+	now := time.Now()
+	r := NewRecorder(RecorderConfig{
+		EventRecorder: fake,
+		DedupInterval: time.Minute,
+		RateLimit:     10 * time.Second,
+		Burst:         2,
+		Logger:        logr.Discard(),
+	}).(*recorder)
+	r.now = func() time.Time { return now }
+
+	gw := testGateway("gateway-1", "1")
+
+	// identical events are deduplicated
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error")
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error")
+	assert.Equal(t, []string{"Warning RenderFailed error"}, drain(fake.Events), "dedup")
+
+	// different events on the same object are rate limited
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error-2")
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error-3")
+	assert.Equal(t, []string{"Warning RenderFailed error-2"}, drain(fake.Events), "rate limit")
+
+	// other objects are not affected
+	gw2 := testGateway("gateway-2", "2")
+	r.Normal(gw2, ReasonRenderFailed, ActionRender, "error")
+	assert.Equal(t, []string{"Normal RenderFailed error"}, drain(fake.Events), "other object")
+
+	// a recreated object is a new object
+	gw3 := testGateway("gateway-1", "3")
+	r.Warning(gw3, ReasonRenderFailed, ActionRender, "error")
+	assert.Equal(t, []string{"Warning RenderFailed error"}, drain(fake.Events), "recreated object")
+
+	// the rate limiter refills
+	now = now.Add(10 * time.Second)
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error-3")
+	assert.Equal(t, []string{"Warning RenderFailed error-3"}, drain(fake.Events), "refill")
+
+	// a duplicate is emitted again after the dedup interval
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error")
+	assert.Empty(t, drain(fake.Events), "dedup")
+	now = now.Add(time.Minute)
+	r.Warning(gw, ReasonRenderFailed, ActionRender, "error")
+	assert.Equal(t, []string{"Warning RenderFailed error"}, drain(fake.Events), "dedup expired")
+}
+
+func TestRecorderPrune(t *testing.T) {
+	fake := events.NewFakeRecorder(2 * maxEntries)
+	now := time.Now()
+	r := NewRecorder(RecorderConfig{
+		EventRecorder: fake,
+		DedupInterval: time.Minute,
+		Logger:        logr.Discard(),
+	}).(*recorder)
+	r.now = func() time.Time { return now }
+
+	for i := 0; i <= maxEntries; i++ {
+		r.Warning(testGateway(fmt.Sprintf("gateway-%d", i), fmt.Sprintf("%d", i)),
+			ReasonRenderFailed, ActionRender, "error")
+	}
+	assert.Len(t, r.entries, maxEntries+1, "entries")
+
+	now = now.Add(time.Minute)
+	r.Warning(testGateway("gateway-0", "0"), ReasonRenderFailed, ActionRender, "error")
+	assert.Len(t, r.entries, 1, "expired entries pruned")
+	assert.Len(t, drain(fake.Events), maxEntries+2, "events")
+}
+
+func TestNoopRecorder(t *testing.T) {
+	r := NewRecorder(RecorderConfig{Logger: logr.Discard()})
+	r.Warning(testGateway("gateway-1", "1"), ReasonRenderFailed, ActionRender, "error")
+	NewNoopRecorder().Warning(testGateway("gateway-1", "1"), ReasonRenderFailed, ActionRender,
+		"error")
+}
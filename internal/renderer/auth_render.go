@@ -8,6 +8,8 @@ import (
 	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
 )
 
 var _ configRenderer = &authRenderer{}
@@ -28,9 +30,23 @@ func (r *authRenderer) render(c *RenderContext, _ ...any) (stnrconfv1.Config, er
 }
 
 func (r *authRenderer) renderInlineAuth(c *RenderContext) (stnrconfv1.Config, error) {
+	auth, err := getInlineAuthConfig(c.gwConf)
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.V(2).Info("Rendering inline auth config ready", "gateway-config",
+		store.GetObjectKey(c.gwConf), "result", fmt.Sprintf("%#v", auth))
+
+	return auth, nil
+}
+
+// getInlineAuthConfig generates an auth config from the inline authentication settings of a
+// GatewayConfig. This is also used by the admission webhook to validate GatewayConfigs.
+func getInlineAuthConfig(gwConf *stnrgwv1.GatewayConfig) (*stnrconfv1.AuthConfig, error) {
 	realm := stnrconfv1.DefaultRealm
-	if c.gwConf.Spec.Realm != nil {
-		realm = *c.gwConf.Spec.Realm
+	if gwConf.Spec.Realm != nil {
+		realm = *gwConf.Spec.Realm
 	}
 
 	auth := stnrconfv1.AuthConfig{
@@ -38,25 +54,25 @@ func (r *authRenderer) renderInlineAuth(c *RenderContext) (stnrconfv1.Config, er
 		Credentials: make(map[string]string),
 	}
 
-	atype, err := getAuthType(c.gwConf.Spec.AuthType)
+	atype, err := getAuthType(gwConf.Spec.AuthType)
 	if err != nil {
 		return nil, err
 	}
 
 	switch atype {
 	case stnrconfv1.AuthTypePlainText:
-		if c.gwConf.Spec.Username == nil || c.gwConf.Spec.Password == nil {
+		if gwConf.Spec.Username == nil || gwConf.Spec.Password == nil {
 			return nil, NewCriticalError(InvalidUsernamePassword)
 		}
 
-		auth.Credentials["username"] = *c.gwConf.Spec.Username
-		auth.Credentials["password"] = *c.gwConf.Spec.Password
+		auth.Credentials["username"] = *gwConf.Spec.Username
+		auth.Credentials["password"] = *gwConf.Spec.Password
 
 	case stnrconfv1.AuthTypeLongTerm:
-		if c.gwConf.Spec.SharedSecret == nil {
+		if gwConf.Spec.SharedSecret == nil {
 			return nil, NewCriticalError(InvalidSharedSecret)
 		}
-		auth.Credentials["secret"] = *c.gwConf.Spec.SharedSecret
+		auth.Credentials["secret"] = *gwConf.Spec.SharedSecret
 	}
 
 	auth.Type = atype.String()
@@ -66,9 +82,6 @@ func (r *authRenderer) renderInlineAuth(c *RenderContext) (stnrconfv1.Config, er
 		return nil, NewCriticalError(InvalidAuthConfig)
 	}
 
-	c.log.V(2).Info("Rendering inline auth config ready", "gateway-config",
-		store.GetObjectKey(c.gwConf), "result", fmt.Sprintf("%#v", auth))
-
 	return &auth, nil
 }
 
@@ -86,22 +99,12 @@ func (r *authRenderer) renderExternalAuth(c *RenderContext) (stnrconfv1.Config,
 		Credentials: make(map[string]string),
 	}
 
-	ref := c.gwConf.Spec.AuthRef
-	n, err := getSecretNameFromRef(ref, gwConf.GetNamespace())
+	secret, err := getAuthSecret(c)
 	if err != nil {
-		// report concrete error here, return a critical error
-		c.log.Info("Invalid auth Secret", "gateway-config", store.GetObjectKey(c.gwConf),
-			"ref", dumpSecretRef(ref, gwConf.GetNamespace()), "error", err.Error())
-		return nil, NewCriticalError(ExternalAuthCredentialsNotFound)
-	}
-
-	secret := store.AuthSecrets.GetObject(n)
-	if secret == nil {
-		// report concrete error here, return a critical error
-		c.log.Info("Auth Secret not found", "gateway-config", store.GetObjectKey(c.gwConf),
-			"ref", dumpSecretRef(ref, gwConf.GetNamespace()), "name", n)
+		// concrete error already reported, return a critical error
 		return nil, NewCriticalError(ExternalAuthCredentialsNotFound)
 	}
+	n := store.GetNamespacedName(secret)
 
 	if secret.Type != corev1.SecretTypeOpaque {
 		c.log.Info("Expecting Secret of type \"Opaque\" (trying to use Secret anyway)",
@@ -158,6 +161,37 @@ func (r *authRenderer) renderExternalAuth(c *RenderContext) (stnrconfv1.Config,
 	return &auth, nil
 }
 
+// getAuthSecret returns the Secret referenced by the authRef of a GatewayConfig. Returns a
+// non-critical RefNotPermitted error if the Secret is in another namespace and no ReferenceGrant
+// allows the reference, or a critical error if the Secret cannot be found.
+func getAuthSecret(c *RenderContext) (*corev1.Secret, error) {
+	gwConf := c.gwConf
+	ref := gwConf.Spec.AuthRef
+	n, err := getSecretNameFromRef(ref, gwConf.GetNamespace())
+	if err != nil {
+		c.log.Info("Invalid auth Secret", "gateway-config", store.GetObjectKey(gwConf),
+			"ref", dumpSecretRef(ref, gwConf.GetNamespace()), "error", err.Error())
+		return nil, NewCriticalError(ExternalAuthCredentialsNotFound)
+	}
+
+	if !isReferenceGranted(stnrgwv1.GroupVersion.Group, "GatewayConfig", gwConf.GetNamespace(),
+		corev1.GroupName, "Secret", n) {
+		c.log.Info("Cross-namespace auth Secret reference not permitted by any ReferenceGrant",
+			"gateway-config", store.GetObjectKey(gwConf),
+			"ref", dumpSecretRef(ref, gwConf.GetNamespace()), "name", n)
+		return nil, NewNonCriticalError(RefNotPermitted)
+	}
+
+	secret := store.AuthSecrets.GetObject(n)
+	if secret == nil {
+		c.log.Info("Auth Secret not found", "gateway-config", store.GetObjectKey(gwConf),
+			"ref", dumpSecretRef(ref, gwConf.GetNamespace()), "name", n)
+		return nil, NewCriticalError(ExternalAuthCredentialsNotFound)
+	}
+
+	return secret, nil
+}
+
 func getAuthType(hint *string) (stnrconfv1.AuthType, error) {
 	authType := stnrconfv1.DefaultAuthType
 	if hint != nil {
@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
 
@@ -527,5 +528,84 @@ func TestRenderAuthRender(t *testing.T) {
 				assert.Error(t, err, "mixed inline/external auth")
 			},
 		},
+		{
+			name: "cross-namespace secret not permitted errs",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				// add a cross-namespace AuthRef to gwconf and remove inline auth
+				w := testutils.TestGwConfig.DeepCopy()
+				namespace := gwapiv1.Namespace("dummy-ns")
+				w.Spec.AuthRef = &gwapiv1.SecretObjectReference{
+					Namespace: &namespace,
+					Name:      gwapiv1.ObjectName("testauthsecret-ok"),
+				}
+				w.Spec.AuthType = nil
+				w.Spec.Username = nil
+				w.Spec.Password = nil
+				w.Spec.SharedSecret = nil
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+
+				s := testutils.TestAuthSecret.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				c.ascrts = []corev1.Secret{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				_, err = r.renderAuth(c)
+				assert.Error(t, err, "renderAuth")
+				assert.True(t, IsCritical(err), "critical err")
+			},
+		},
+		{
+			name: "cross-namespace secret permitted by ReferenceGrant ok",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				// add a cross-namespace AuthRef to gwconf and remove inline auth
+				w := testutils.TestGwConfig.DeepCopy()
+				namespace := gwapiv1.Namespace("dummy-ns")
+				w.Spec.AuthRef = &gwapiv1.SecretObjectReference{
+					Namespace: &namespace,
+					Name:      gwapiv1.ObjectName("testauthsecret-ok"),
+				}
+				w.Spec.AuthType = nil
+				w.Spec.Username = nil
+				w.Spec.Password = nil
+				w.Spec.SharedSecret = nil
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+
+				s := testutils.TestAuthSecret.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				c.ascrts = []corev1.Secret{*s}
+
+				// allow GatewayConfigs in "testnamespace" to refer to Secrets in "dummy-ns"
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.Spec.From[0].Kind = "GatewayConfig"
+				rg.Spec.To[0].Kind = "Secret"
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				auth, err := r.renderAuth(c)
+				assert.NoError(t, err, "renderAuth")
+
+				assert.Equal(t, "static", auth.Type, "auth-type")
+				assert.Equal(t, "ext-testuser", auth.Credentials["username"],
+					"username")
+				assert.Equal(t, "ext-testpass", auth.Credentials["password"],
+					"password")
+			},
+		},
 	})
 }
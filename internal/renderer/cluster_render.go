@@ -23,134 +23,152 @@ func (r *renderer) renderCluster(ro *stnrgwv1.UDPRoute) (*stnrconfv1.ClusterConf
 		return nil, NewCriticalError(NoRuleFound)
 	}
 
-	if len(rs) > 1 {
-		r.log.V(1).Info("Cluster rendering error: too many rules (%d) in route %q, "+
-			"considering only the first one", len(rs), store.GetObjectKey(ro))
-	}
-
 	eps := []string{}
 
 	// the rest of the errors are not critical, but we still need to keep track of each in
-	// order to set the ResolvedRefs Route status: last error is reported only
+	// order to set the ResolvedRefs Route status: last error is reported only, along with the
+	// index of the rule the offending backend belongs to
 	var routeError error
 
+	// all rules are rendered into the same cluster: backends must agree on the cluster type
 	ctype, prevCType := stnrconfv1.ClusterTypeStatic, stnrconfv1.ClusterTypeUnknown
-	for _, b := range rs[0].BackendRefs {
-		b := b
-
-		if b.Group != nil && string(*b.Group) != corev1.GroupName &&
-			string(*b.Group) != stnrgwv1.GroupVersion.Group {
-			routeError = NewNonCriticalError(InvalidBackendGroup)
-			r.log.V(2).Info("Cluster rendering error: invalid backend Group", "route",
-				store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b), "group",
-				*b.Group, "error", routeError.Error())
-			continue
-		}
+	for i := range rs {
+		var ruleError error
+		for _, b := range rs[i].BackendRefs {
+			b := b
+
+			if b.Group != nil && string(*b.Group) != corev1.GroupName &&
+				string(*b.Group) != stnrgwv1.GroupVersion.Group {
+				ruleError = NewNonCriticalError(InvalidBackendGroup)
+				r.log.V(2).Info("Cluster rendering error: invalid backend Group", "route",
+					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b), "group",
+					*b.Group, "error", ruleError.Error())
+				continue
+			}
 
-		if b.Kind != nil && string(*b.Kind) != "Service" && string(*b.Kind) != "StaticService" {
-			routeError = NewNonCriticalError(InvalidBackendKind)
-			r.log.V(2).Info("Cluster rendering error: invalid backend Kind", "route",
-				store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b), "kind", *b.Kind,
-				"error", routeError)
-			continue
-		}
+			if b.Kind != nil && string(*b.Kind) != "Service" && string(*b.Kind) != "StaticService" {
+				ruleError = NewNonCriticalError(InvalidBackendKind)
+				r.log.V(2).Info("Cluster rendering error: invalid backend Kind", "route",
+					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b), "kind", *b.Kind,
+					"error", ruleError)
+				continue
+			}
 
-		// default is the local namespace of the route
-		ns := ro.GetNamespace()
-		if b.Namespace != nil {
-			ns = string(*b.Namespace)
-		}
+			// default is the local namespace of the route
+			ns := ro.GetNamespace()
+			if b.Namespace != nil {
+				ns = string(*b.Namespace)
+			}
 
-		ep := []string{}
-		switch ref := &b; {
-		case store.IsReferenceService(ref):
-			var errEDS error
+			// cross-namespace backends must be permitted by a ReferenceGrant
+			if !isBackendReferenceGranted(ro, &b, ns) {
+				ruleError = NewNonCriticalError(RefNotPermitted)
+				r.log.Info("Cluster rendering error: cross-namespace backend reference not permitted",
+					"route", store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
+					"error", ruleError)
+				continue
+			}
+
+			ep := []string{}
+			switch ref := &b; {
+			case store.IsReferenceService(ref):
+				var errEDS error
+
+				// get endpoints (checks EDS inline)
+				if config.EnableEndpointDiscovery {
+					epEDS, ctypeEDS, err := getEndpointsForService(ref, ns)
+					if err != nil {
+						r.log.V(1).Info("Cluster rendering error: could not render Endpoints for Service backend",
+							"route", store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
+							"error", err)
+						errEDS = err
+						ruleError = err
+					} else {
+						ep = append(ep, epEDS...)
+						ctype = ctypeEDS
+					}
+				}
+
+				// the clusterIP or STRICT_DNS cluster if EDS is disabled
+				epCluster, ctypeCluster, errCluster := getClusterRouteForService(ref, ns)
+				if errCluster != nil {
+					r.log.V(1).Info("Cluster rendering error: could not render service-route (ClusterIP/DNS "+
+						"route) for Service backend", "route",
+						store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
+						"error", errCluster)
+
+					ruleError = errCluster
+				} else {
+					ep = append(ep, epCluster...)
+					ctype = ctypeCluster
+				}
+
+				if errCluster != nil && errEDS != nil {
+					// both attempts failed: skip backend
+					r.log.V(1).Info("Cluster rendering: skipping Service backend", "route",
+						store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
+						"reason", ruleError)
+					ruleError = NewNonCriticalError(BackendNotFound)
+					continue
+				}
 
-			// get endpoints (checks EDS inline)
-			if config.EnableEndpointDiscovery {
-				epEDS, ctypeEDS, err := getEndpointsForService(ref, ns)
+			case store.IsReferenceStaticService(ref):
+				var err error
+				ep, ctype, err = getEndpointsForStaticService(ref, ns)
 				if err != nil {
-					r.log.V(1).Info("Cluster rendering error: could not render Endpoints for Service backend",
+					ruleError = err
+					r.log.Info("Cluster rendering error: could not render endpoints for StaticService backend",
 						"route", store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
-						"error", err)
-					errEDS = err
-					routeError = err
-				} else {
-					ep = append(ep, epEDS...)
-					ctype = ctypeEDS
+						"error", ruleError)
+					continue
 				}
+			default:
+				// error could also be InvalidBackendGroup: both are reported with the same
+				// reason in the route status
+				ruleError = NewNonCriticalError(InvalidBackendKind)
+				r.log.Info("Cluster rendering error: invalid backend Kind and/or Group", "route", store.GetObjectKey(ro),
+					"backendRef", store.DumpBackendRef(&b), "error", ruleError)
+				continue
 			}
 
-			// the clusterIP or STRICT_DNS cluster if EDS is disabled
-			epCluster, ctypeCluster, errCluster := getClusterRouteForService(ref, ns)
-			if errCluster != nil {
-				r.log.V(1).Info("Cluster rendering error: could not render service-route (ClusterIP/DNS "+
-					"route) for Service backend", "route",
-					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
-					"error", errCluster)
-
-				routeError = errCluster
-			} else {
-				ep = append(ep, epCluster...)
-				ctype = ctypeCluster
+			if IsNonCriticalError(ruleError, BackendNotFound) {
+				r.log.Info("Cluster rendering: skipping backend", "route", store.GetObjectKey(ro),
+					"backendRef", store.DumpBackendRef(&b))
+				continue
 			}
 
-			if errCluster != nil && errEDS != nil {
-				// both attempts failed: skip backend
-				r.log.V(1).Info("Cluster rendering: skipping Service backend", "route",
-					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
-					"reason", routeError)
-				routeError = NewNonCriticalError(BackendNotFound)
+			if prevCType != stnrconfv1.ClusterTypeUnknown && prevCType != ctype {
+				ruleError = NewNonCriticalError(InconsitentClusterType)
+				r.log.Info("Cluster rendering error: inconsistent cluster type", "route",
+					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
+					"prevous-ctype", fmt.Sprintf("%#v", prevCType))
 				continue
 			}
 
-		case store.IsReferenceStaticService(ref):
-			var err error
-			ep, ctype, err = getEndpointsForStaticService(ref, ns)
-			if err != nil {
-				routeError = err
-				r.log.Info("Cluster rendering error: could not render endpoints for StaticService backend",
-					"route", store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(ref),
-					"error", routeError)
+			if err := injectPortRange(&b, ep, ctype); err != nil {
+				ruleError = NewNonCriticalError(InvalidPortRange)
+				r.log.Info("Cluster rendering error", "route",
+					store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
+					"cluster-ctype", ctype.String(), "error", err.Error())
 				continue
 			}
-		default:
-			// error could also be InvalidBackendGroup: both are reported with the same
-			// reason in the route status
-			routeError = NewNonCriticalError(InvalidBackendKind)
-			r.log.Info("Cluster rendering error: invalid backend Kind and/or Group", "route", store.GetObjectKey(ro),
-				"backendRef", store.DumpBackendRef(&b), "error", routeError)
-			continue
-		}
 
-		if IsNonCriticalError(routeError, BackendNotFound) {
-			r.log.Info("Cluster rendering: skipping backend", "route", store.GetObjectKey(ro),
-				"backendRef", store.DumpBackendRef(&b))
-			continue
-		}
-
-		if prevCType != stnrconfv1.ClusterTypeUnknown && prevCType != ctype {
-			routeError = NewNonCriticalError(InconsitentClusterType)
-			r.log.Info("Cluster rendering error: inconsistent cluster type", "route",
+			r.log.V(2).Info("Cluster rendering: adding Endpoints for backend", "route",
 				store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
-				"prevous-ctype", fmt.Sprintf("%#v", prevCType))
-			continue
-		}
+				"cluster-type", ctype.String(), "endpoints", ep)
 
-		if err := injectPortRange(&b, ep, ctype); err != nil {
-			routeError = NewNonCriticalError(InvalidPortRange)
-			r.log.Info("Cluster rendering error", "route",
-				store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
-				"cluster-ctype", ctype.String(), "error", err.Error())
-			continue
+			eps = append(eps, ep...)
+			prevCType = ctype
 		}
 
-		r.log.V(2).Info("Cluster rendering: adding Endpoints for backend", "route",
-			store.GetObjectKey(ro), "backendRef", store.DumpBackendRef(&b),
-			"cluster-type", ctype.String(), "endpoints", ep)
+		if ruleError != nil {
+			routeError = NewRuleError(i, ruleError)
+		}
+	}
 
-		eps = append(eps, ep...)
-		prevCType = ctype
+	// a failed backend in a later rule must not invalidate the backends rendered so far
+	if ctype == stnrconfv1.ClusterTypeUnknown && prevCType != stnrconfv1.ClusterTypeUnknown {
+		ctype = prevCType
 	}
 
 	if ctype == stnrconfv1.ClusterTypeUnknown {
@@ -249,6 +267,24 @@ func getEndpointsForStaticService(b *stnrgwv1.BackendRef, ns string) ([]string,
 	return ep, stnrconfv1.ClusterTypeStatic, nil
 }
 
+// validatePortRange checks the port range of a backend. The conditions are the ones
+// injectPortRange checks, but here invalid settings are reported instead of being ignored.
+func validatePortRange(b *stnrgwv1.BackendRef) error {
+	if b.Port != nil && (int(*b.Port) <= 0 || int(*b.Port) >= 65536) {
+		return fmt.Errorf("port %d out of range [1, 65535]", *b.Port)
+	}
+
+	if b.EndPort != nil && (int(*b.EndPort) <= 0 || int(*b.EndPort) >= 65536) {
+		return fmt.Errorf("endPort %d out of range [1, 65535]", *b.EndPort)
+	}
+
+	if b.Port != nil && b.EndPort != nil && int(*b.EndPort) < int(*b.Port) {
+		return fmt.Errorf("endPort %d smaller than port %d", *b.EndPort, *b.Port)
+	}
+
+	return nil
+}
+
 func injectPortRange(b *stnrgwv1.BackendRef, eps []string, ctype stnrconfv1.ClusterType) error {
 	// only static clusters know how to handle port ranges
 	if ctype != stnrconfv1.ClusterTypeStatic {
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"errors"
 	// "fmt"
 	"testing"
 
@@ -8,7 +9,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
@@ -381,6 +385,9 @@ func TestRenderClusterRender(t *testing.T) {
 				ns := gwapiv1.Namespace("dummy")
 				udp.Spec.Rules[0].BackendRefs[0].Namespace = &ns
 				c.rs = []stnrgwv1.UDPRoute{*udp}
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.SetNamespace("dummy")
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
 			},
 			tester: func(t *testing.T, r *renderer) {
 				rs := r.allUDPRoutes()
@@ -429,6 +436,7 @@ func TestRenderClusterRender(t *testing.T) {
 				udp.Spec.Rules[0].BackendRefs[1].Name = "testservice-ok-1"
 				udp.Spec.Rules[0].BackendRefs[2].Name = "testservice-ok-2"
 				c.rs = []stnrgwv1.UDPRoute{*udp}
+				c.rgs = []gwapiv1b1.ReferenceGrant{testutils.TestReferenceGrant}
 			},
 			tester: func(t *testing.T, r *renderer) {
 				rs := r.allUDPRoutes()
@@ -831,6 +839,9 @@ func TestRenderClusterRender(t *testing.T) {
 				ns := gwapiv1.Namespace("dummy")
 				udp.Spec.Rules[0].BackendRefs[0].Namespace = &ns
 				c.rs = []stnrgwv1.UDPRoute{*udp}
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.SetNamespace("dummy")
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
 
 				s1 := testutils.TestSvc.DeepCopy()
 				s1.SetNamespace("dummy")
@@ -894,6 +905,7 @@ func TestRenderClusterRender(t *testing.T) {
 				udp.Spec.Rules[0].BackendRefs[1].Name = "testservice-ok-1"
 				udp.Spec.Rules[0].BackendRefs[2].Name = "testservice-ok-2"
 				c.rs = []stnrgwv1.UDPRoute{*udp}
+				c.rgs = []gwapiv1b1.ReferenceGrant{testutils.TestReferenceGrant}
 
 				s1 := testutils.TestSvc.DeepCopy()
 				s1.SetNamespace("dummy-ns")
@@ -981,6 +993,9 @@ func TestRenderClusterRender(t *testing.T) {
 				udp.Spec.Rules[0].BackendRefs[1].Name = "testservice-ok-1"
 				udp.Spec.Rules[0].BackendRefs[2].Name = "testservice-ok-2"
 				c.rsV1A2 = []stnrgwv1.UDPRoute{*udp}
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.Spec.From[0].Group = gwapiv1.GroupName
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
 
 				s1 := testutils.TestSvc.DeepCopy()
 				s1.SetNamespace("dummy-ns")
@@ -1085,6 +1100,7 @@ func TestRenderClusterRender(t *testing.T) {
 					},
 				}
 				c.rs = []stnrgwv1.UDPRoute{*udp}
+				c.rgs = []gwapiv1b1.ReferenceGrant{testutils.TestReferenceGrant}
 
 				s1 := testutils.TestSvc.DeepCopy()
 				s1.SetName("testservice-ok-1")
@@ -1381,5 +1397,290 @@ func TestRenderClusterRender(t *testing.T) {
 				config.EnableRelayToClusterIP = opdefault.DefaultEnableRelayToClusterIP
 			},
 		},
+		// multiple rules
+		{
+			name:  "multiple rules ok",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs:  []corev1.Service{testutils.TestSvc},
+			esls:  []discoveryv1.EndpointSlice{testutils.TestEndpointSlice},
+			ssvcs: []stnrgwv1.StaticService{testutils.TestStaticSvc},
+			prep: func(c *renderTestConfig) {
+				group := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				kind := gwapiv1.Kind("StaticService")
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.Rules = []stnrgwv1.UDPRouteRule{{
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Group: &group,
+							Kind:  &kind,
+							Name:  "teststaticservice-ok",
+						},
+					}},
+				}, {
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Name: "testservice-ok",
+						},
+					}},
+				}}
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rs := r.allUDPRoutes()
+				assert.Len(t, rs, 1, "route len")
+
+				config.EnableEndpointDiscovery = true
+				config.EnableRelayToClusterIP = false
+				config.EndpointSliceAvailable = true
+
+				rc, err := r.renderCluster(rs[0])
+				assert.NoError(t, err, "render cluster")
+
+				assert.Equal(t, "testnamespace/udproute-ok", rc.Name, "cluster name")
+				assert.Equal(t, "STATIC", rc.Type, "cluster type")
+				assert.Len(t, rc.Endpoints, 7, "endpoints len")
+				// rule 0: static svc
+				assert.Contains(t, rc.Endpoints, "10.11.12.13", "StaticService endpoint ip-1")
+				assert.Contains(t, rc.Endpoints, "10.11.12.14", "StaticService endpoint ip-2")
+				assert.Contains(t, rc.Endpoints, "10.11.12.15", "StaticService endpoint ip-3")
+				// rule 1: service
+				assert.Contains(t, rc.Endpoints, "1.2.3.4", "Service endpoint ip-1")
+				assert.Contains(t, rc.Endpoints, "1.2.3.5", "Service endpoint ip-2")
+				assert.Contains(t, rc.Endpoints, "1.2.3.6", "Service endpoint ip-3")
+				assert.Contains(t, rc.Endpoints, "1.2.3.7", "Service endpoint ip-4")
+
+				// restore
+				config.EnableEndpointDiscovery = opdefault.DefaultEnableEndpointDiscovery
+				config.EnableRelayToClusterIP = opdefault.DefaultEnableRelayToClusterIP
+			},
+		},
+		{
+			name:  "multiple rules - missing backend reported with rule index",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			ssvcs: []stnrgwv1.StaticService{testutils.TestStaticSvc},
+			prep: func(c *renderTestConfig) {
+				group := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				kind := gwapiv1.Kind("StaticService")
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.Rules = []stnrgwv1.UDPRouteRule{{
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Group: &group,
+							Kind:  &kind,
+							Name:  "teststaticservice-ok",
+						},
+					}},
+				}, {
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Group: &group,
+							Kind:  &kind,
+							Name:  "teststaticservice-dummy",
+						},
+					}},
+				}}
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rs := r.allUDPRoutes()
+				assert.Len(t, rs, 1, "route len")
+				ro := rs[0]
+
+				rc, err := r.renderCluster(ro)
+				assert.Error(t, err, "render cluster")
+				assert.True(t, IsNonCritical(err), "non-critical error")
+				assert.True(t, IsNonCriticalError(err, BackendNotFound), "backend not found")
+
+				var ruleErr *RuleError
+				assert.True(t, errors.As(err, &ruleErr), "rule error")
+				assert.Equal(t, 1, ruleErr.Rule, "rule index")
+
+				// the backends of the first rule are still rendered
+				assert.Equal(t, "testnamespace/udproute-ok", rc.Name, "cluster name")
+				assert.Equal(t, "STATIC", rc.Type, "cluster type")
+				assert.Len(t, rc.Endpoints, 3, "endpoints len")
+				assert.Contains(t, rc.Endpoints, "10.11.12.13", "StaticService endpoint ip-1")
+				assert.Contains(t, rc.Endpoints, "10.11.12.14", "StaticService endpoint ip-2")
+				assert.Contains(t, rc.Endpoints, "10.11.12.15", "StaticService endpoint ip-3")
+
+				// the route status names the offending rule
+				initRouteStatus(ro)
+				p := ro.Spec.ParentRefs[0]
+				setRouteConditionStatus(ro, &p, config.ControllerName, true, true, err)
+				assert.Len(t, ro.Status.Parents, 1, "parent status len")
+				d := meta.FindStatusCondition(ro.Status.Parents[0].Conditions,
+					string(gwapiv1.RouteConditionResolvedRefs))
+				assert.NotNil(t, d, "resolved-refs cond found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "resolved-refs status")
+				assert.Equal(t, string(gwapiv1.RouteReasonBackendNotFound), d.Reason,
+					"resolved-refs reason")
+				assert.Contains(t, d.Message, "rule 1", "resolved-refs message")
+			},
+		},
+		{
+			name:  "multiple rules - inconsistent cluster type across rules errs",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs:  []corev1.Service{testutils.TestSvc},
+			ssvcs: []stnrgwv1.StaticService{testutils.TestStaticSvc},
+			prep: func(c *renderTestConfig) {
+				group := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				kind := gwapiv1.Kind("StaticService")
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.Rules = []stnrgwv1.UDPRouteRule{{
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Group: &group,
+							Kind:  &kind,
+							Name:  "teststaticservice-ok",
+						},
+					}},
+				}, {
+					BackendRefs: []stnrgwv1.BackendRef{{
+						BackendObjectReference: stnrgwv1.BackendObjectReference{
+							Name: "testservice-ok",
+						},
+					}},
+				}}
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rs := r.allUDPRoutes()
+				assert.Len(t, rs, 1, "route len")
+
+				// switch EDS off: would render a DNS cluster for the second rule
+				config.EnableEndpointDiscovery = false
+				config.EnableRelayToClusterIP = false
+
+				_, err := r.renderCluster(rs[0])
+				assert.Error(t, err, "render cluster")
+				assert.True(t, IsNonCritical(err), "critical error")
+				assert.True(t, IsNonCriticalError(err, InconsitentClusterType), "inconsistent type")
+
+				var ruleErr *RuleError
+				assert.True(t, errors.As(err, &ruleErr), "rule error")
+				assert.Equal(t, 1, ruleErr.Rule, "rule index")
+
+				// restore
+				config.EnableEndpointDiscovery = opdefault.DefaultEnableEndpointDiscovery
+				config.EnableRelayToClusterIP = opdefault.DefaultEnableRelayToClusterIP
+			},
+		},
+		{
+			name:  "cross-namespace backend not permitted errs",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			ssvcs: []stnrgwv1.StaticService{testutils.TestStaticSvc},
+			prep: func(c *renderTestConfig) {
+				group := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				kind := gwapiv1.Kind("StaticService")
+				ns := gwapiv1.Namespace("dummy-ns")
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.Rules[0].BackendRefs = []stnrgwv1.BackendRef{{
+					BackendObjectReference: stnrgwv1.BackendObjectReference{
+						Group: &group,
+						Kind:  &kind,
+						Name:  "teststaticservice-ok",
+					},
+				}, {
+					BackendObjectReference: stnrgwv1.BackendObjectReference{
+						Group:     &group,
+						Kind:      &kind,
+						Namespace: &ns,
+						Name:      "teststaticservice-ok",
+					},
+				}}
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+
+				s := testutils.TestStaticSvc.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				s.Spec.Prefixes = []string{"10.11.12.16"}
+				c.ssvcs = []stnrgwv1.StaticService{testutils.TestStaticSvc, *s}
+
+				// the grant is for Services only
+				c.rgs = []gwapiv1b1.ReferenceGrant{testutils.TestReferenceGrant}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rs := r.allUDPRoutes()
+				assert.Len(t, rs, 1, "route len")
+				ro := rs[0]
+
+				rc, err := r.renderCluster(ro)
+				assert.Error(t, err, "render cluster")
+				assert.True(t, IsNonCriticalError(err, RefNotPermitted), "ref not permitted")
+
+				assert.Equal(t, "testnamespace/udproute-ok", rc.Name, "cluster name")
+				assert.Equal(t, "STATIC", rc.Type, "cluster type")
+				assert.Len(t, rc.Endpoints, 3, "endpoints len")
+				assert.NotContains(t, rc.Endpoints, "10.11.12.16", "cross-namespace endpoint")
+
+				initRouteStatus(ro)
+				p := ro.Spec.ParentRefs[0]
+				setRouteConditionStatus(ro, &p, config.ControllerName, true, true, err)
+				assert.Len(t, ro.Status.Parents, 1, "parent status len")
+				d := meta.FindStatusCondition(ro.Status.Parents[0].Conditions,
+					string(gwapiv1.RouteConditionResolvedRefs))
+				assert.NotNil(t, d, "resolved-refs cond found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "resolved-refs status")
+				assert.Equal(t, string(gwapiv1.RouteReasonRefNotPermitted), d.Reason,
+					"resolved-refs reason")
+			},
+		},
+		{
+			name:  "cross-namespace backend permitted by ReferenceGrant ok",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			ssvcs: []stnrgwv1.StaticService{testutils.TestStaticSvc},
+			prep: func(c *renderTestConfig) {
+				group := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				kind := gwapiv1.Kind("StaticService")
+				ns := gwapiv1.Namespace("dummy-ns")
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.Rules[0].BackendRefs = []stnrgwv1.BackendRef{{
+					BackendObjectReference: stnrgwv1.BackendObjectReference{
+						Group:     &group,
+						Kind:      &kind,
+						Namespace: &ns,
+						Name:      "teststaticservice-ok",
+					},
+				}}
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+
+				s := testutils.TestStaticSvc.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				c.ssvcs = []stnrgwv1.StaticService{*s}
+
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				name := gwapiv1.ObjectName("teststaticservice-ok")
+				rg.Spec.To[0].Group = group
+				rg.Spec.To[0].Kind = kind
+				rg.Spec.To[0].Name = &name
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rs := r.allUDPRoutes()
+				assert.Len(t, rs, 1, "route len")
+
+				rc, err := r.renderCluster(rs[0])
+				assert.NoError(t, err, "render cluster")
+
+				assert.Equal(t, "testnamespace/udproute-ok", rc.Name, "cluster name")
+				assert.Equal(t, "STATIC", rc.Type, "cluster type")
+				assert.Len(t, rc.Endpoints, 3, "endpoints len")
+				assert.Contains(t, rc.Endpoints, "10.11.12.13", "StaticService endpoint ip-1")
+			},
+		},
 	})
 }
@@ -1,16 +1,21 @@
 package renderer
 
 import (
+	"fmt"
 	"net"
 	"net/url"
+	"sort"
+	"strings"
 
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
 
@@ -104,8 +109,13 @@ func (r *dataplaneGenerator) generate(c *RenderContext) (client.Object, error) {
 		return nil, err
 	}
 
-	// copy replicas
-	if dataplane.Spec.Replicas != nil {
+	// apply the pod template patch: mandatory fields are enforced on the result
+	if err := patchPodTemplate(c, dataplane, &deployment, &deployment.Spec.Template); err != nil {
+		return nil, err
+	}
+
+	// copy replicas, unless the replicas are managed by an autoscaler
+	if dataplane.Spec.Replicas != nil && !isAutoscaledDataplane(dataplane) {
 		deployment.Spec.Replicas = dataplane.Spec.Replicas
 	}
 
@@ -285,12 +295,15 @@ func cdsServerURI(addr string) string {
 }
 
 func getDataplane(c *RenderContext) (*stnrgwv1.Dataplane, error) {
-	// default dataplane
-	dataplaneName := opdefault.DefaultDataplaneName
+	var gw *gwapiv1.Gateway
+	if c.gws != nil {
+		gw = c.gws.GetFirst()
+	}
 
-	// gwConf override
-	if c.gwConf != nil && c.gwConf.Spec.Dataplane != nil {
-		dataplaneName = *c.gwConf.Spec.Dataplane
+	dataplaneName, err := getDataplaneName(c.gwConf, gw)
+	if err != nil {
+		c.log.Error(err, "Invalid Dataplane reference", "gateway", store.GetObjectKey(gw))
+		return nil, NewCriticalError(InvalidDataplane)
 	}
 
 	dataplane := store.Dataplanes.GetObject(types.NamespacedName{Name: dataplaneName})
@@ -302,6 +315,131 @@ func getDataplane(c *RenderContext) (*stnrgwv1.Dataplane, error) {
 	return dataplane, nil
 }
 
+// getDataplaneName returns the name of the Dataplane to use for a Gateway. In the order of
+// precedence, this is the Dataplane referenced in the infrastructure parameters of the Gateway,
+// the Dataplane set in the dataplane annotation of the Gateway, the Dataplane set in the
+// GatewayConfig, or the default Dataplane. The Gateway may be nil.
+func getDataplaneName(gwConf *stnrgwv1.GatewayConfig, gw *gwapiv1.Gateway) (string, error) {
+	if gw != nil && gw.Spec.Infrastructure != nil && gw.Spec.Infrastructure.ParametersRef != nil {
+		ref := gw.Spec.Infrastructure.ParametersRef
+		if string(ref.Group) != stnrgwv1.GroupVersion.Group || string(ref.Kind) != "Dataplane" {
+			return "", fmt.Errorf("invalid infrastructure parameters reference %s/%s, "+
+				"expecting %s/Dataplane", ref.Group, ref.Kind, stnrgwv1.GroupVersion.Group)
+		}
+		return ref.Name, nil
+	}
+
+	if gw != nil {
+		if name, ok := gw.GetAnnotations()[opdefault.DataplaneAnnotationKey]; ok && name != "" {
+			return name, nil
+		}
+	}
+
+	if gwConf != nil && gwConf.Spec.Dataplane != nil {
+		return *gwConf.Spec.Dataplane, nil
+	}
+
+	return opdefault.DefaultDataplaneName, nil
+}
+
+// setDataplaneStatus sets the status of a Dataplane from the Deployments/DaemonSets generated for
+// the Gateways that use the Dataplane. The rollout state is taken from the dataplane resources
+// in the global store.
+func setDataplaneStatus(dp *stnrgwv1.Dataplane, gws []*gwapiv1.Gateway) {
+	gws = append([]*gwapiv1.Gateway{}, gws...)
+	sort.Slice(gws, func(i, j int) bool {
+		return store.GetObjectKey(gws[i]) < store.GetObjectKey(gws[j])
+	})
+
+	statuses := []stnrgwv1.DataplaneGatewayStatus{}
+	notFound, pending := []string{}, []string{}
+	for _, gw := range gws {
+		status, found, ready := getDataplaneGatewayStatus(gw)
+		switch {
+		case !found:
+			notFound = append(notFound, store.GetObjectKey(gw))
+		case !ready:
+			pending = append(pending, store.GetObjectKey(gw))
+		}
+		statuses = append(statuses, status)
+	}
+	dp.Status.Gateways = statuses
+
+	cond := metav1.Condition{
+		Type:               string(stnrgwv1.DataplaneConditionReady),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dp.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(stnrgwv1.DataplaneReasonReady),
+		Message:            fmt.Sprintf("dataplane ready for %d Gateway(s)", len(gws)),
+	}
+
+	switch {
+	case len(gws) == 0:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = string(stnrgwv1.DataplaneReasonNotInUse)
+		cond.Message = "dataplane is not used by any Gateway"
+	case len(notFound) > 0:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = string(stnrgwv1.DataplaneReasonNotFound)
+		cond.Message = fmt.Sprintf("dataplane resource not found for Gateway(s): %s",
+			strings.Join(notFound, ", "))
+	case len(pending) > 0:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = string(stnrgwv1.DataplaneReasonPending)
+		cond.Message = fmt.Sprintf("dataplane rollout in progress for Gateway(s): %s",
+			strings.Join(pending, ", "))
+	}
+
+	meta.SetStatusCondition(&dp.Status.Conditions, cond)
+}
+
+// getDataplaneGatewayStatus returns the rollout state of the Deployment or DaemonSet generated
+// for a Gateway, along with whether the dataplane resource exists and whether it has rolled out
+// the current spec.
+func getDataplaneGatewayStatus(gw *gwapiv1.Gateway) (stnrgwv1.DataplaneGatewayStatus, bool, bool) {
+	status := stnrgwv1.DataplaneGatewayStatus{
+		Namespace: gw.GetNamespace(),
+		Name:      gw.GetName(),
+	}
+
+	n := store.GetNamespacedName(gw)
+	if dep := store.Deployments.GetObject(n); dep != nil {
+		replicas := int32(1)
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+
+		status.Kind = stnrgwv1.DataplaneResourceDeployment
+		status.Replicas = replicas
+		status.ReadyReplicas = dep.Status.ReadyReplicas
+		status.UpdatedReplicas = dep.Status.UpdatedReplicas
+
+		// old replicas must be gone for the rollout to complete
+		ready := dep.Status.ObservedGeneration >= dep.Generation &&
+			dep.Status.UpdatedReplicas >= replicas &&
+			dep.Status.Replicas == dep.Status.UpdatedReplicas &&
+			dep.Status.ReadyReplicas >= replicas
+
+		return status, true, ready
+	}
+
+	if ds := store.DaemonSets.GetObject(n); ds != nil {
+		status.Kind = stnrgwv1.DataplaneResourceDaemonSet
+		status.Replicas = ds.Status.DesiredNumberScheduled
+		status.ReadyReplicas = ds.Status.NumberReady
+		status.UpdatedReplicas = ds.Status.UpdatedNumberScheduled
+
+		ready := ds.Status.ObservedGeneration >= ds.Generation &&
+			ds.Status.UpdatedNumberScheduled >= ds.Status.DesiredNumberScheduled &&
+			ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled
+
+		return status, true, ready
+	}
+
+	return status, false, false
+}
+
 func getHealthCheckParameters(c *RenderContext) (*corev1.Probe, *corev1.Probe) {
 	if c.dp != nil && c.dp.Spec.DisableHealthCheck {
 		return nil, nil
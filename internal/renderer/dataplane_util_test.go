@@ -11,8 +11,10 @@ import (
 
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	apiutil "k8s.io/apimachinery/pkg/util/intstr"
 
 	// "k8s.io/apimachinery/pkg/types"
@@ -54,6 +56,48 @@ func TestCDSServerURI(t *testing.T) {
 	}
 }
 
+func TestGetDataplaneName(t *testing.T) {
+	gwConf := testutils.TestGwConfig.DeepCopy()
+	gwConf.Spec.Dataplane = &[]string{"dataplane-conf"}[0]
+	annotated := testutils.TestGw.DeepCopy()
+	annotated.SetAnnotations(map[string]string{opdefault.DataplaneAnnotationKey: "dataplane-ann"})
+	infra := annotated.DeepCopy()
+	infra.Spec.Infrastructure = &gwapiv1.GatewayInfrastructure{
+		ParametersRef: &gwapiv1.LocalParametersReference{
+			Group: gwapiv1.Group(stnrgwv1.GroupVersion.Group),
+			Kind:  "Dataplane",
+			Name:  "dataplane-infra",
+		},
+	}
+	invalid := infra.DeepCopy()
+	invalid.Spec.Infrastructure.ParametersRef.Kind = "ConfigMap"
+
+	cases := []struct {
+		name   string
+		gwConf *stnrgwv1.GatewayConfig
+		gw     *gwapiv1.Gateway
+		want   string
+		err    bool
+	}{
+		{name: "default", gwConf: nil, gw: nil, want: opdefault.DefaultDataplaneName},
+		{name: "gateway config", gwConf: gwConf, gw: testutils.TestGw.DeepCopy(), want: "dataplane-conf"},
+		{name: "annotation overrides gateway config", gwConf: gwConf, gw: annotated, want: "dataplane-ann"},
+		{name: "parameters ref overrides annotation", gwConf: gwConf, gw: infra, want: "dataplane-infra"},
+		{name: "invalid parameters ref", gwConf: gwConf, gw: invalid, err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, err := getDataplaneName(tc.gwConf, tc.gw)
+			if tc.err {
+				assert.Error(t, err, "dataplane name error")
+				return
+			}
+			assert.NoError(t, err, "dataplane name")
+			assert.Equal(t, tc.want, name, "dataplane name")
+		})
+	}
+}
+
 func TestRenderDataplaneUtil(t *testing.T) {
 	renderTester(t, []renderTestConfig{
 		{
@@ -208,6 +252,8 @@ func TestRenderDataplaneUtil(t *testing.T) {
 					"termination grace")
 				assert.True(t, podSpec.HostNetwork, "hostnetwork")
 				assert.Nil(t, podSpec.Affinity, "affinity")
+				assert.Empty(t, podSpec.Volumes, "volumes")
+				assert.Empty(t, container.VolumeMounts, "volume mounts")
 			},
 		},
 		{
@@ -499,5 +545,412 @@ func TestRenderDataplaneUtil(t *testing.T) {
 				assert.Equal(t, store.GetObjectKey(gw), gwName, "related-gateway annotation")
 			},
 		},
+		{
+			name: "pod template patch: strategic merge",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := c.dps[0].DeepCopy()
+				dp.Spec.PodTemplatePatch = &stnrgwv1.DataplanePodTemplatePatch{
+					Patch: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{
+"metadata":{"labels":{"app":"dummy","extra":"label"}},
+"spec":{"nodeSelector":{"role":"turn"},"priorityClassName":"high",
+"containers":[{"name":%q,"image":"dummy","args":["-l","all:DEBUG"],"workingDir":"/tmp","env":[{"name":%q,"value":"dummy"},{"name":"EXTRA","value":"env"}]},
+{"name":"sidecar","image":"sidecar:v1"}]}}`, opdefault.DefaultStunnerdInstanceName,
+						stnrconfv1.DefaultEnvVarName))},
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, gws: store.NewGatewayStore(), log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+				c.update = event.NewEventUpdate(0)
+				c.gws.ResetGateways(r.getGateways4Class(c))
+				gw := c.gws.GetFirst()
+
+				obj, err := r.generateDataplane(c)
+				assert.NoError(t, err, "create deployment")
+				deploy, ok := obj.(*appv1.Deployment)
+				assert.True(t, ok, "deployment cast")
+
+				// patched labels, mandatory labels enforced
+				labs := deploy.Spec.Template.GetLabels()
+				assert.Equal(t, opdefault.AppLabelValue, labs[opdefault.AppLabelKey], "app label")
+				assert.Equal(t, gw.GetName(), labs[opdefault.RelatedGatewayKey], "related-gw label")
+				assert.Equal(t, "label", labs["extra"], "patched label")
+
+				podSpec := &deploy.Spec.Template.Spec
+				assert.Equal(t, map[string]string{"role": "turn"}, podSpec.NodeSelector, "node selector")
+				assert.Equal(t, "high", podSpec.PriorityClassName, "priority class")
+				assert.Equal(t, int64(60), *podSpec.TerminationGracePeriodSeconds, "termination grace")
+
+				assert.Len(t, podSpec.Containers, 2, "containers len")
+				container := podSpec.Containers[0]
+				assert.Equal(t, opdefault.DefaultStunnerdInstanceName, container.Name, "stunnerd name")
+				assert.Equal(t, "testimage-1", container.Image, "image")
+				assert.Equal(t, "/tmp", container.WorkingDir, "patched working dir")
+				assert.Contains(t, container.Env, corev1.EnvVar{
+					Name:  stnrconfv1.DefaultEnvVarName,
+					Value: gw.GetName(),
+				}, "env: gateway name enforced")
+				assert.Contains(t, container.Env, corev1.EnvVar{
+					Name:  "EXTRA",
+					Value: "env",
+				}, "env: patched env")
+				assert.Equal(t, "sidecar", podSpec.Containers[1].Name, "sidecar name")
+				assert.Equal(t, "sidecar:v1", podSpec.Containers[1].Image, "sidecar image")
+
+				// the stunnerd command line is enforced
+				assert.Equal(t, []string{"testcommand-1"}, container.Command, "command")
+				assert.Equal(t, []string{"arg-1", "arg-2"}, container.Args, "args")
+
+				// the patched fields are recorded
+				stunnerd := "spec.containers[" + opdefault.DefaultStunnerdInstanceName + "]"
+				assert.ElementsMatch(t, []string{
+					"metadata.labels",
+					"spec.containers[sidecar]",
+					"spec.containers[sidecar].image",
+					stunnerd + ".env",
+					stunnerd + ".workingDir",
+					"spec.nodeSelector",
+					"spec.priorityClassName",
+				}, store.GetLastAppliedPodTemplateFields(deploy), "patched fields")
+			},
+		},
+		{
+			name: "pod template patch: JSON patch",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := c.dps[0].DeepCopy()
+				patchType := stnrgwv1.DataplanePodTemplatePatchJSONPatch
+				dp.Spec.PodTemplatePatch = &stnrgwv1.DataplanePodTemplatePatch{
+					Type: &patchType,
+					Patch: runtime.RawExtension{Raw: []byte(`[
+{"op":"add","path":"/spec/schedulerName","value":"custom"},
+{"op":"replace","path":"/spec/containers/0/name","value":"renamed"}]`)},
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, gws: store.NewGatewayStore(), log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+				c.update = event.NewEventUpdate(0)
+				c.gws.ResetGateways(r.getGateways4Class(c))
+
+				obj, err := r.generateDataplane(c)
+				assert.NoError(t, err, "create deployment")
+				deploy, ok := obj.(*appv1.Deployment)
+				assert.True(t, ok, "deployment cast")
+
+				podSpec := &deploy.Spec.Template.Spec
+				assert.Equal(t, "custom", podSpec.SchedulerName, "scheduler name")
+
+				// the stunnerd container is enforced
+				assert.Len(t, podSpec.Containers, 2, "containers len")
+				container := podSpec.Containers[0]
+				assert.Equal(t, opdefault.DefaultStunnerdInstanceName, container.Name, "stunnerd name")
+				assert.Equal(t, "renamed", podSpec.Containers[1].Name, "renamed container")
+			},
+		},
+		{
+			name: "pod template patch: invalid patch",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := c.dps[0].DeepCopy()
+				patchType := stnrgwv1.DataplanePodTemplatePatchJSONPatch
+				dp.Spec.PodTemplatePatch = &stnrgwv1.DataplanePodTemplatePatch{
+					Type:  &patchType,
+					Patch: runtime.RawExtension{Raw: []byte(`{"spec":{}}`)},
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, gws: store.NewGatewayStore(), log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+				c.update = event.NewEventUpdate(0)
+				c.gws.ResetGateways(r.getGateways4Class(c))
+
+				_, err = r.generateDataplane(c)
+				assert.Error(t, err, "invalid patch")
+				assert.True(t, IsCriticalError(err, InvalidDataplane), "invalid dataplane error")
+			},
+		},
+		{
+			name: "pod template patch: unreconciled field",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				for _, patch := range []string{
+					`{"spec":{"hostPID":true}}`,
+					`{"spec":{"preemptionPolicy":"Never"}}`,
+					`{"spec":{"enableServiceLinks":false}}`,
+					`{"spec":{"readinessGates":[{"conditionType":"dummy"}]}}`,
+					fmt.Sprintf(`{"spec":{"containers":[{"name":%q,"tty":true}]}}`,
+						opdefault.DefaultStunnerdInstanceName),
+					`{"spec":{"containers":[{"name":"sidecar","image":"sidecar:v1","resizePolicy":[{"resourceName":"cpu","restartPolicy":"NotRequired"}]}]}}`,
+				} {
+					dp := testutils.TestDataplane.DeepCopy()
+					dp.Spec.PodTemplatePatch = &stnrgwv1.DataplanePodTemplatePatch{
+						Patch: runtime.RawExtension{Raw: []byte(patch)},
+					}
+					store.Dataplanes.Upsert(dp)
+
+					gc, err := r.getGatewayClass()
+					assert.NoError(t, err, "gw-class found")
+					c := &RenderContext{gc: gc, gws: store.NewGatewayStore(), log: log}
+					c.gwConf, err = r.getGatewayConfig4Class(c)
+					assert.NoError(t, err, "gw-conf found")
+					c.update = event.NewEventUpdate(0)
+					c.gws.ResetGateways(r.getGateways4Class(c))
+
+					_, err = r.generateDataplane(c)
+					assert.Error(t, err, "unreconciled field: %s", patch)
+					assert.True(t, IsCriticalError(err, InvalidDataplane), "invalid dataplane error")
+				}
+			},
+		},
+		{
+			name: "dataplane status: no gateways",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				c.gws = []gwapiv1.Gateway{}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				assert.Len(t, dp.Status.Gateways, 0, "gateway num")
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionUnknown, cond.Status, "ready status")
+				assert.Equal(t, string(stnrgwv1.DataplaneReasonNotInUse), cond.Reason, "ready reason")
+			},
+		},
+		{
+			name: "dataplane status: deployment ready",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dep := appv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       testutils.TestGw.GetName(),
+						Namespace:  testutils.TestGw.GetNamespace(),
+						Generation: 2,
+					},
+					Spec: appv1.DeploymentSpec{Replicas: &testutils.TestReplicas},
+					Status: appv1.DeploymentStatus{
+						ObservedGeneration: 2,
+						Replicas:           3,
+						UpdatedReplicas:    3,
+						ReadyReplicas:      3,
+					},
+				}
+				c.deps = []appv1.Deployment{dep}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				assert.Len(t, dp.Status.Gateways, 1, "gateway num")
+				s := dp.Status.Gateways[0]
+				assert.Equal(t, testutils.TestGw.GetNamespace(), s.Namespace, "gateway namespace")
+				assert.Equal(t, testutils.TestGw.GetName(), s.Name, "gateway name")
+				assert.Equal(t, stnrgwv1.DataplaneResourceDeployment, s.Kind, "kind")
+				assert.Equal(t, int32(3), s.Replicas, "replicas")
+				assert.Equal(t, int32(3), s.ReadyReplicas, "ready replicas")
+				assert.Equal(t, int32(3), s.UpdatedReplicas, "updated replicas")
+
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "ready status")
+				assert.Equal(t, string(stnrgwv1.DataplaneReasonReady), cond.Reason, "ready reason")
+			},
+		},
+		{
+			name: "dataplane status: deployment rollout pending",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dep := appv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       testutils.TestGw.GetName(),
+						Namespace:  testutils.TestGw.GetNamespace(),
+						Generation: 2,
+					},
+					Spec: appv1.DeploymentSpec{Replicas: &testutils.TestReplicas},
+					Status: appv1.DeploymentStatus{
+						ObservedGeneration: 2,
+						Replicas:           3,
+						UpdatedReplicas:    1,
+						ReadyReplicas:      3,
+					},
+				}
+				c.deps = []appv1.Deployment{dep}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				assert.Len(t, dp.Status.Gateways, 1, "gateway num")
+				s := dp.Status.Gateways[0]
+				assert.Equal(t, int32(3), s.Replicas, "replicas")
+				assert.Equal(t, int32(3), s.ReadyReplicas, "ready replicas")
+				assert.Equal(t, int32(1), s.UpdatedReplicas, "updated replicas")
+
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "ready status")
+				assert.Equal(t, string(stnrgwv1.DataplaneReasonPending), cond.Reason, "ready reason")
+				assert.Contains(t, cond.Message, store.GetObjectKey(&testutils.TestGw), "ready message")
+			},
+		},
+		{
+			name: "dataplane status: deployment generation not observed",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dep := appv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       testutils.TestGw.GetName(),
+						Namespace:  testutils.TestGw.GetNamespace(),
+						Generation: 2,
+					},
+					Spec: appv1.DeploymentSpec{Replicas: &testutils.TestReplicas},
+					Status: appv1.DeploymentStatus{
+						ObservedGeneration: 1,
+						Replicas:           3,
+						UpdatedReplicas:    3,
+						ReadyReplicas:      3,
+					},
+				}
+				c.deps = []appv1.Deployment{dep}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "ready status")
+				assert.Equal(t, string(stnrgwv1.DataplaneReasonPending), cond.Reason, "ready reason")
+			},
+		},
+		{
+			name: "dataplane status: dataplane resource not found",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				assert.Len(t, dp.Status.Gateways, 1, "gateway num")
+				s := dp.Status.Gateways[0]
+				assert.Equal(t, testutils.TestGw.GetName(), s.Name, "gateway name")
+				assert.Equal(t, stnrgwv1.DataplaneResourceType(""), s.Kind, "kind")
+
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "ready status")
+				assert.Equal(t, string(stnrgwv1.DataplaneReasonNotFound), cond.Reason, "ready reason")
+			},
+		},
+		{
+			name: "dataplane status: daemonset ready",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				ds := appv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testutils.TestGw.GetName(),
+						Namespace: testutils.TestGw.GetNamespace(),
+					},
+					Status: appv1.DaemonSetStatus{
+						DesiredNumberScheduled: 2,
+						UpdatedNumberScheduled: 2,
+						NumberReady:            2,
+					},
+				}
+				c.dss = []appv1.DaemonSet{ds}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				dp := testutils.TestDataplane.DeepCopy()
+				gws := []*gwapiv1.Gateway{}
+				for _, gw := range store.Gateways.GetAll() {
+					gws = append(gws, gw)
+				}
+				setDataplaneStatus(dp, gws)
+
+				assert.Len(t, dp.Status.Gateways, 1, "gateway num")
+				s := dp.Status.Gateways[0]
+				assert.Equal(t, stnrgwv1.DataplaneResourceDaemonSet, s.Kind, "kind")
+				assert.Equal(t, int32(2), s.Replicas, "replicas")
+				assert.Equal(t, int32(2), s.ReadyReplicas, "ready replicas")
+				assert.Equal(t, int32(2), s.UpdatedReplicas, "updated replicas")
+
+				cond := meta.FindStatusCondition(dp.Status.Conditions,
+					string(stnrgwv1.DataplaneConditionReady))
+				assert.NotNil(t, cond, "ready cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "ready status")
+			},
+		},
 	})
 }
@@ -1,5 +1,14 @@
 package renderer
 
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/recorder"
+)
+
 // ErrorType species the type of a non-critical rendering error
 type ErrorType int
 
@@ -31,8 +40,64 @@ const (
 	InvalidPortRange
 	PublicAddressNotFound
 	PublicListenerAddressNotFound
+	RefNotPermitted
 )
 
+// String returns the name of the error type.
+func (t ErrorType) String() string {
+	switch t {
+	case NoError:
+		return "NoError"
+	case InvalidAuthType:
+		return "InvalidAuthType"
+	case InvalidUsernamePassword:
+		return "InvalidUsernamePassword"
+	case InvalidSharedSecret:
+		return "InvalidSharedSecret"
+	case InvalidDataplane:
+		return "InvalidDataplane"
+	case NoRuleFound:
+		return "NoRuleFound"
+	case ExternalAuthCredentialsNotFound:
+		return "ExternalAuthCredentialsNotFound"
+	case InvalidAuthConfig:
+		return "InvalidAuthConfig"
+	case RenderingError:
+		return "RenderingError"
+	case InternalError:
+		return "InternalError"
+	case InvalidBackendGroup:
+		return "InvalidBackendGroup"
+	case InvalidBackendKind:
+		return "InvalidBackendKind"
+	case BackendNotFound:
+		return "BackendNotFound"
+	case ServiceNotFound:
+		return "ServiceNotFound"
+	case ClusterIPNotFound:
+		return "ClusterIPNotFound"
+	case EndpointNotFound:
+		return "EndpointNotFound"
+	case InconsitentClusterType:
+		return "InconsitentClusterType"
+	case InvalidProtocol:
+		return "InvalidProtocol"
+	case PortUnavailable:
+		return "PortUnavailable"
+	case InvalidCertificateRef:
+		return "InvalidCertificateRef"
+	case InvalidPortRange:
+		return "InvalidPortRange"
+	case PublicAddressNotFound:
+		return "PublicAddressNotFound"
+	case PublicListenerAddressNotFound:
+		return "PublicListenerAddressNotFound"
+	case RefNotPermitted:
+		return "RefNotPermitted"
+	}
+	return "Unknown"
+}
+
 type TypedError struct {
 	reason ErrorType
 }
@@ -114,30 +179,83 @@ func (e *NonCriticalError) Error() string {
 		return "no public address found for gateway"
 	case PublicListenerAddressNotFound:
 		return "no public address found for one or more listeners"
+	case RefNotPermitted:
+		return "cross-namespace reference not permitted by any ReferenceGrant"
 	}
 	return "Unknown error"
 }
 
+// RuleError is a non-critical error that is tied to a particular rule of a route, e.g., because
+// one of the backends referred to by the rule could not be resolved.
+type RuleError struct {
+	// Rule is the index of the offending rule in the route spec.
+	Rule int
+	err  error
+}
+
+// NewRuleError wraps an error with the index of the route rule it pertains to.
+func NewRuleError(rule int, err error) error {
+	return &RuleError{Rule: rule, err: err}
+}
+
+// Error returns an error message.
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %d: %s", e.Rule, e.err.Error())
+}
+
+// Unwrap returns the underlying typed error.
+func (e *RuleError) Unwrap() error {
+	return e.err
+}
+
 // IsCritical returns true of an error is critical.
 func IsCritical(e error) bool {
-	_, ok := e.(*CriticalError)
-	return ok
+	var err *CriticalError
+	return errors.As(e, &err)
 }
 
 // IsCriticalError returns true of an error is a critical error of the given type.
 func IsCriticalError(e error, reason ErrorType) bool {
-	err, ok := e.(*CriticalError)
-	return ok && err.reason == reason
+	var err *CriticalError
+	return errors.As(e, &err) && err.reason == reason
 }
 
 // IsNonCritical returns true of an error is critical.
 func IsNonCritical(e error) bool {
-	_, ok := e.(*NonCriticalError)
-	return ok
+	var err *NonCriticalError
+	return errors.As(e, &err)
 }
 
 // IsNonCriticalError returns true of an error is a critical error of the given type.
 func IsNonCriticalError(e error, reason ErrorType) bool {
-	err, ok := e.(*NonCriticalError)
-	return err != nil && ok && err.reason == reason
+	var err *NonCriticalError
+	return errors.As(e, &err) && err.reason == reason
+}
+
+// errorReason returns the type of a render error as a string, or "Unknown" for untyped errors.
+func errorReason(e error) string {
+	var critical *CriticalError
+	if errors.As(e, &critical) {
+		return critical.reason.String()
+	}
+	var nonCritical *NonCriticalError
+	if errors.As(e, &nonCritical) {
+		return nonCritical.reason.String()
+	}
+	return "Unknown"
+}
+
+// recordError emits a Kubernetes Event on an object for a render error.
+func (r *renderer) recordError(o client.Object, err error) {
+	if err == nil || IsNonCriticalError(err, ClusterIPNotFound) {
+		// missing ClusterIP is fine for headless Services
+		return
+	}
+
+	reason := recorder.ReasonRenderFailed
+	if IsNonCritical(err) {
+		reason = recorder.ReasonRenderWarning
+	}
+
+	r.recorder.Warning(o, reason, recorder.ActionRender, err.Error())
 }
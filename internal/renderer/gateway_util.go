@@ -19,6 +19,8 @@ import (
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
 )
 
 // maxConds is the maximum number of conditions that can be stored at one in a Gateway object
@@ -108,25 +110,115 @@ func initGatewayStatus(gw *gwapiv1.Gateway, reason error) {
 
 	// reinit listener statuses
 	gw.Status.Listeners = gw.Status.Listeners[:0]
-	groupgwapiv1a2 := gwapiv1.Group(gwapiv1a2.GroupVersion.Group)
-	groupstnrv1 := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
-
 	for _, l := range gw.Spec.Listeners {
+		kinds, _ := getListenerRouteKinds(&l)
 		gw.Status.Listeners = append(gw.Status.Listeners,
 			gwapiv1.ListenerStatus{
-				Name: l.Name,
-				SupportedKinds: []gwapiv1.RouteGroupKind{{
-					Group: &groupgwapiv1a2,
-					Kind:  gwapiv1.Kind("UDPRoute"),
-				}, {
-					Group: &groupstnrv1,
-					Kind:  gwapiv1.Kind("UDPRoute"),
-				}},
-				Conditions: []metav1.Condition{},
+				Name:           l.Name,
+				SupportedKinds: kinds,
+				Conditions:     []metav1.Condition{},
 			})
 	}
 }
 
+// getSupportedRouteKinds returns the route kinds that may attach to a listener of the given
+// protocol: UDPRoutes (both the STUNner and the Gateway API variant) on all listeners, plus
+// TCPRoutes on TURN-TCP and TLSRoutes on TURN-TLS listeners.
+func getSupportedRouteKinds(l *gwapiv1.Listener) []gwapiv1.RouteGroupKind {
+	groupgwapiv1a2 := gwapiv1.Group(gwapiv1a2.GroupVersion.Group)
+	groupstnrv1 := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+
+	kinds := []gwapiv1.RouteGroupKind{{
+		Group: &groupgwapiv1a2,
+		Kind:  gwapiv1.Kind("UDPRoute"),
+	}, {
+		Group: &groupstnrv1,
+		Kind:  gwapiv1.Kind("UDPRoute"),
+	}}
+
+	proto, err := getProtocol(l.Protocol)
+	if err != nil {
+		return kinds
+	}
+
+	switch proto {
+	case stnrconfv1.ListenerProtocolTURNTCP:
+		kinds = append(kinds, gwapiv1.RouteGroupKind{
+			Group: &groupgwapiv1a2,
+			Kind:  gwapiv1.Kind("TCPRoute"),
+		})
+	case stnrconfv1.ListenerProtocolTURNTLS:
+		kinds = append(kinds, gwapiv1.RouteGroupKind{
+			Group: &groupgwapiv1a2,
+			Kind:  gwapiv1.Kind("TLSRoute"),
+		})
+	}
+
+	return kinds
+}
+
+// getListenerRouteKinds returns the route kinds a listener accepts: all supported kinds if the
+// listener does not restrict the allowed route kinds, otherwise the supported subset of the
+// allowed kinds. The second return value is true if the listener lists a route kind that is not
+// supported for its protocol.
+func getListenerRouteKinds(l *gwapiv1.Listener) ([]gwapiv1.RouteGroupKind, bool) {
+	supported := getSupportedRouteKinds(l)
+	if l.AllowedRoutes == nil || len(l.AllowedRoutes.Kinds) == 0 {
+		return supported, false
+	}
+
+	kinds, invalid := []gwapiv1.RouteGroupKind{}, false
+	for _, k := range l.AllowedRoutes.Kinds {
+		g := gwapiv1.GroupName
+		if k.Group != nil {
+			g = string(*k.Group)
+		}
+		if !routeKindInList(g, string(k.Kind), supported) {
+			invalid = true
+			continue
+		}
+		if !routeKindInList(g, string(k.Kind), kinds) {
+			group := gwapiv1.Group(g)
+			kinds = append(kinds, gwapiv1.RouteGroupKind{Group: &group, Kind: k.Kind})
+		}
+	}
+
+	return kinds, invalid
+}
+
+func routeKindInList(group, kind string, kinds []gwapiv1.RouteGroupKind) bool {
+	for _, k := range kinds {
+		g := gwapiv1.GroupName
+		if k.Group != nil {
+			g = string(*k.Group)
+		}
+		if g == group && string(k.Kind) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// maxGatewayStatusAddresses is the maximum number of addresses in the Gateway status, as
+// enforced by the Gateway API CRD.
+const maxGatewayStatusAddresses = 16
+
+func addGatewayStatusAddress(gw *gwapiv1.Gateway, ap gwAddrPort) {
+	if ap.addr == "" || len(gw.Status.Addresses) >= maxGatewayStatusAddresses {
+		return
+	}
+	for _, a := range gw.Status.Addresses {
+		if a.Value == ap.addr && a.Type != nil && *a.Type == ap.aType {
+			return
+		}
+	}
+	aType := ap.aType
+	gw.Status.Addresses = append(gw.Status.Addresses, gwapiv1.GatewayStatusAddress{
+		Type:  &aType,
+		Value: ap.addr,
+	})
+}
+
 func setGatewayStatusProgrammed(gw *gwapiv1.Gateway, err error, pubAddrs []gwAddrPort) {
 	if err != nil {
 		meta.SetStatusCondition(&gw.Status.Conditions, metav1.Condition{
@@ -141,23 +233,18 @@ func setGatewayStatusProgrammed(gw *gwapiv1.Gateway, err error, pubAddrs []gwAdd
 		return
 	}
 
+	// report all distinct public addresses, including the alternative addresses (e.g., the
+	// other IP family of a dual-stack LB)
 	progd := true
-	var gwAddr gwAddrPort
+	gw.Status.Addresses = []gwapiv1.GatewayStatusAddress{}
 	for _, ap := range pubAddrs {
 		if ap.isEmpty() {
 			progd = false
-		} else {
-			gwAddr = ap
+			continue
+		}
+		for _, a := range append([]gwAddrPort{ap}, ap.alts...) {
+			addGatewayStatusAddress(gw, a)
 		}
-	}
-
-	gw.Status.Addresses = []gwapiv1.GatewayStatusAddress{}
-	if !gwAddr.isEmpty() {
-		aType := gwAddr.aType
-		gw.Status.Addresses = []gwapiv1.GatewayStatusAddress{{
-			Type:  &aType,
-			Value: gwAddr.addr,
-		}}
 	}
 
 	if progd {
@@ -181,6 +268,36 @@ func setGatewayStatusProgrammed(gw *gwapiv1.Gateway, err error, pubAddrs []gwAdd
 	}
 }
 
+// setGatewayStatusDataplaneConfigSynced reports whether the dataplane pods of the Gateway run
+// the latest config. Clients are tracked per config discovery connection, so each connected
+// dataplane pod is counted separately.
+func setGatewayStatusDataplaneConfigSynced(gw *gwapiv1.Gateway, s config.SyncStatus, served bool) {
+	cond := metav1.Condition{
+		Type:               opdefault.GatewayConditionDataplaneConfigSynced,
+		ObservedGeneration: gw.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	switch {
+	case !served || s.Clients == 0:
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = opdefault.GatewayReasonDataplaneConfigNoClients
+		cond.Message = "no dataplane pod has fetched the config yet"
+	case s.Synced == s.Clients:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = opdefault.GatewayReasonDataplaneConfigSynced
+		cond.Message = fmt.Sprintf("%d dataplane pod(s) on the latest config version %d",
+			s.Synced, s.Version)
+	default:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = opdefault.GatewayReasonDataplaneConfigPending
+		cond.Message = fmt.Sprintf("%d/%d dataplane pod(s) on the latest config version %d, "+
+			"oldest version in use: %d", s.Synced, s.Clients, s.Version, s.OldestVersion)
+	}
+
+	meta.SetStatusCondition(&gw.Status.Conditions, cond)
+}
+
 // listener status
 func getStatus4Listener(gw *gwapiv1.Gateway, l *gwapiv1.Listener) *gwapiv1.ListenerStatus {
 	for i := range gw.Status.Listeners {
@@ -192,7 +309,9 @@ func getStatus4Listener(gw *gwapiv1.Gateway, l *gwapiv1.Listener) *gwapiv1.Liste
 }
 
 // sets "Detached" to true with reason "UnsupportedProtocol" or false, depending on "accepted"
-// sets ResolvedRefs to true
+// sets ResolvedRefs to true, or to false if a certificate ref cannot be resolved or an allowed
+// route kind is not supported
+// sets SupportedKinds to the route kinds accepted by the listener
 // sets "Ready" to <ready> depending on "ready"
 func setListenerStatus(gw *gwapiv1.Gateway, l *gwapiv1.Listener, err error, conflicted bool, routes int) {
 	s := getStatus4Listener(gw, l)
@@ -203,9 +322,11 @@ func setListenerStatus(gw *gwapiv1.Gateway, l *gwapiv1.Listener, err error, conf
 
 	setListenerStatusAccepted(gw, s, err)
 	setListenerStatusConflicted(gw, s, conflicted)
-	setListenerStatusResolvedRefs(gw, s, err)
+	kinds, invalidKinds := getListenerRouteKinds(l)
+	setListenerStatusResolvedRefs(gw, s, err, invalidKinds)
 	// listener ready status deprecated
 	// setListenerStatusReady(gw, s, ready)
+	s.SupportedKinds = kinds
 	s.AttachedRoutes = int32(routes)
 }
 
@@ -229,7 +350,7 @@ func setListenerStatusAccepted(gw *gwapiv1.Gateway, s *gwapiv1.ListenerStatus, r
 			Reason:             string(gwapiv1.ListenerReasonUnsupportedProtocol),
 			Message:            "unsupported protocol",
 		})
-	case IsNonCriticalError(reason, InvalidCertificateRef):
+	case IsNonCriticalError(reason, InvalidCertificateRef), IsNonCriticalError(reason, RefNotPermitted):
 		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
 			Type:               string(gwapiv1.ListenerConditionAccepted),
 			Status:             metav1.ConditionTrue,
@@ -281,7 +402,7 @@ func setListenerStatusConflicted(gw *gwapiv1.Gateway, s *gwapiv1.ListenerStatus,
 	}
 }
 
-func setListenerStatusResolvedRefs(gw *gwapiv1.Gateway, s *gwapiv1.ListenerStatus, reason error) {
+func setListenerStatusResolvedRefs(gw *gwapiv1.Gateway, s *gwapiv1.ListenerStatus, reason error, invalidKinds bool) {
 	if IsNonCriticalError(reason, InvalidCertificateRef) {
 		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
 			Type:               string(gwapiv1.ListenerConditionResolvedRefs),
@@ -294,6 +415,30 @@ func setListenerStatusResolvedRefs(gw *gwapiv1.Gateway, s *gwapiv1.ListenerStatu
 		return
 	}
 
+	if IsNonCriticalError(reason, RefNotPermitted) {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:               string(gwapiv1.ListenerConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gw.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gwapiv1.ListenerReasonRefNotPermitted),
+			Message:            "cross-namespace certificate reference not permitted by any ReferenceGrant",
+		})
+		return
+	}
+
+	if invalidKinds {
+		meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+			Type:               string(gwapiv1.ListenerConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gw.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gwapiv1.ListenerReasonInvalidRouteKinds),
+			Message:            "at least one allowed route kind is not supported by the listener",
+		})
+		return
+	}
+
 	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
 		Type:               string(gwapiv1.ListenerConditionResolvedRefs),
 		Status:             metav1.ConditionTrue,
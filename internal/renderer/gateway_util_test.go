@@ -333,6 +333,93 @@ func TestRenderGatewayUtil(t *testing.T) {
 					d.Reason, "reason")
 			},
 		},
+		{
+			name: "listener status supported and invalid route kinds",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				stnrGroup := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				gw.Spec.Listeners = []gwapiv1.Listener{{
+					Name:     gwapiv1.SectionName("gateway-1-listener-udp"),
+					Port:     gwapiv1.PortNumber(1),
+					Protocol: gwapiv1.ProtocolType("TURN-UDP"),
+				}, {
+					Name:     gwapiv1.SectionName("gateway-1-listener-tcp"),
+					Port:     gwapiv1.PortNumber(2),
+					Protocol: gwapiv1.ProtocolType("TURN-TCP"),
+				}, {
+					Name:     gwapiv1.SectionName("gateway-1-listener-tls"),
+					Port:     gwapiv1.PortNumber(3),
+					Protocol: gwapiv1.ProtocolType("TURN-TLS"),
+					AllowedRoutes: &gwapiv1.AllowedRoutes{
+						Kinds: []gwapiv1.RouteGroupKind{
+							{Group: &stnrGroup, Kind: gwapiv1.Kind("UDPRoute")},
+							{Kind: gwapiv1.Kind("TCPRoute")},
+							{Kind: gwapiv1.Kind("HTTPRoute")},
+						},
+					},
+				}}
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+
+				initGatewayStatus(gw, nil)
+				for _, l := range gw.Spec.Listeners {
+					setListenerStatus(gw, &l, nil, false, 0)
+				}
+				assert.Len(t, gw.Status.Listeners, 3, "listener status num")
+
+				kinds := func(s gwapiv1.ListenerStatus) []string {
+					ret := []string{}
+					for _, k := range s.SupportedKinds {
+						assert.NotNil(t, k.Group, "group")
+						ret = append(ret, fmt.Sprintf("%s/%s", *k.Group, k.Kind))
+					}
+					return ret
+				}
+
+				// UDP listener
+				s := gw.Status.Listeners[0]
+				assert.Equal(t, []string{"gateway.networking.k8s.io/UDPRoute", "stunner.l7mp.io/UDPRoute"},
+					kinds(s), "supported kinds")
+				d := meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, d, "resolvedrefs found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.ListenerReasonResolvedRefs), d.Reason, "reason")
+
+				// TCP listener
+				s = gw.Status.Listeners[1]
+				assert.Equal(t, []string{"gateway.networking.k8s.io/UDPRoute", "stunner.l7mp.io/UDPRoute",
+					"gateway.networking.k8s.io/TCPRoute"}, kinds(s), "supported kinds")
+				d = meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, d, "resolvedrefs found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+
+				// TLS listener: TCPRoute and HTTPRoute are not supported
+				s = gw.Status.Listeners[2]
+				assert.Equal(t, []string{"stunner.l7mp.io/UDPRoute"}, kinds(s), "supported kinds")
+				d = meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionAccepted))
+				assert.NotNil(t, d, "accepted found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+				d = meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, d, "resolvedrefs found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.ListenerReasonInvalidRouteKinds), d.Reason, "reason")
+			},
+		},
 		{
 			name: "invalid listener status",
 			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
@@ -2,7 +2,10 @@ package renderer
 
 import (
 	"fmt"
+	"sort"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
@@ -31,3 +34,110 @@ func (r *renderer) getGatewayConfig4Class(c *RenderContext) (*stnrgwv1.GatewayCo
 
 	return gwConf, nil
 }
+
+// getGatewayClassNames4Config returns the sorted names of the GatewayClasses that refer to a
+// GatewayConfig in their parametersRef.
+func (r *renderer) getGatewayClassNames4Config(gwConf *stnrgwv1.GatewayConfig) []string {
+	ret := []string{}
+	for _, gc := range store.GatewayClasses.GetAll() {
+		if err := r.validateGatewayClass(gc); err != nil {
+			continue
+		}
+
+		ref := gc.Spec.ParametersRef
+		if ref.Name == gwConf.GetName() && string(*ref.Namespace) == gwConf.GetNamespace() {
+			ret = append(ret, gc.GetName())
+		}
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+// setGatewayConfigStatus resolves the authRef and renders the auth config in order to update the
+// status of the GatewayConfig in the render context. The status is written into a copy, the
+// GatewayConfig in the store is left intact. The rendered auth config is cached in the render
+// context for the Gateways of the class.
+func (r *renderer) setGatewayConfigStatus(c *RenderContext) *stnrgwv1.GatewayConfig {
+	gwConf := c.gwConf.DeepCopy()
+
+	gwConf.Status.GatewayClasses = r.getGatewayClassNames4Config(gwConf)
+
+	gwConf.Status.AuthSecretResourceVersion = ""
+	var refErr error
+	if gwConf.Spec.AuthRef != nil {
+		secret, err := getAuthSecret(c)
+		if err != nil {
+			refErr = err
+		} else {
+			gwConf.Status.AuthSecretResourceVersion = secret.GetResourceVersion()
+		}
+	}
+	setGatewayConfigStatusResolvedRefs(gwConf, refErr)
+	r.recordError(gwConf, refErr)
+
+	gwConf.Status.AuthType = ""
+	auth, err := r.getAuth(c)
+	if err == nil {
+		gwConf.Status.AuthType = auth.Type
+	}
+	setGatewayConfigStatusAccepted(gwConf, err)
+	r.recordError(gwConf, err)
+
+	return gwConf
+}
+
+func setGatewayConfigStatusAccepted(gwConf *stnrgwv1.GatewayConfig, err error) {
+	if err == nil {
+		meta.SetStatusCondition(&gwConf.Status.Conditions, metav1.Condition{
+			Type:               string(stnrgwv1.GatewayConfigConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: gwConf.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(stnrgwv1.GatewayConfigReasonAccepted),
+			Message:            "gateway-config accepted",
+		})
+	} else {
+		meta.SetStatusCondition(&gwConf.Status.Conditions, metav1.Condition{
+			Type:               string(stnrgwv1.GatewayConfigConditionAccepted),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gwConf.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(stnrgwv1.GatewayConfigReasonInvalid),
+			Message:            fmt.Sprintf("invalid gateway-config: %s", err.Error()),
+		})
+	}
+}
+
+func setGatewayConfigStatusResolvedRefs(gwConf *stnrgwv1.GatewayConfig, err error) {
+	switch {
+	case err == nil:
+		meta.SetStatusCondition(&gwConf.Status.Conditions, metav1.Condition{
+			Type:               string(stnrgwv1.GatewayConfigConditionResolvedRefs),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: gwConf.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(stnrgwv1.GatewayConfigReasonResolvedRefs),
+			Message:            "gateway-config object references sucessfully resolved",
+		})
+	case IsNonCriticalError(err, RefNotPermitted):
+		meta.SetStatusCondition(&gwConf.Status.Conditions, metav1.Condition{
+			Type:               string(stnrgwv1.GatewayConfigConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gwConf.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(stnrgwv1.GatewayConfigReasonRefNotPermitted),
+			Message:            "cross-namespace auth Secret reference not permitted by any ReferenceGrant",
+		})
+	default:
+		meta.SetStatusCondition(&gwConf.Status.Conditions, metav1.Condition{
+			Type:               string(stnrgwv1.GatewayConfigConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gwConf.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(stnrgwv1.GatewayConfigReasonInvalidAuthRef),
+			Message:            "auth Secret reference failed to be successfully resolved",
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
@@ -96,5 +98,163 @@ func TestRenderGatewayConfigUtil(t *testing.T) {
 				assert.Error(t, err, "gw-conf found")
 			},
 		},
+		{
+			name: "gatewayconfig status ok",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				// a second class referring to the same gateway-config
+				gc := testutils.TestGwClass.DeepCopy()
+				gc.SetName("dummy-gateway-class")
+				c.cls = []gwapiv1.GatewayClass{testutils.TestGwClass, *gc}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				s := r.setGatewayConfigStatus(c).Status
+				assert.Equal(t, "static", s.AuthType, "auth-type")
+				assert.Equal(t, "", s.AuthSecretResourceVersion, "secret resource version")
+				assert.Equal(t, []string{"dummy-gateway-class", "gatewayclass-ok"},
+					s.GatewayClasses, "gateway-classes")
+
+				// the GatewayConfig in the store is not modified
+				assert.Len(t, c.gwConf.Status.Conditions, 0, "store status")
+				assert.Nil(t, c.gwConf.Status.GatewayClasses, "store status")
+				assert.NotNil(t, c.auth, "auth config cached")
+
+				cond := meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionAccepted))
+				assert.NotNil(t, cond, "accepted cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "accepted status")
+				assert.Equal(t, string(stnrgwv1.GatewayConfigReasonAccepted), cond.Reason,
+					"accepted reason")
+
+				cond = meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionResolvedRefs))
+				assert.NotNil(t, cond, "resolved-refs cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "resolved-refs status")
+				assert.Equal(t, string(stnrgwv1.GatewayConfigReasonResolvedRefs), cond.Reason,
+					"resolved-refs reason")
+			},
+		},
+		{
+			name:   "gatewayconfig status with external auth ok",
+			cls:    []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:    []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			ascrts: []corev1.Secret{testutils.TestAuthSecret},
+			prep: func(c *renderTestConfig) {
+				w := testutils.TestGwConfig.DeepCopy()
+				w.Spec.AuthRef = &gwapiv1.SecretObjectReference{
+					Name: gwapiv1.ObjectName("testauthsecret-ok"),
+				}
+				w.Spec.AuthType = nil
+				w.Spec.Username = nil
+				w.Spec.Password = nil
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+
+				s := testutils.TestAuthSecret.DeepCopy()
+				s.SetResourceVersion("12")
+				c.ascrts = []corev1.Secret{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				s := r.setGatewayConfigStatus(c).Status
+				assert.Equal(t, "static", s.AuthType, "auth-type")
+				assert.Equal(t, "12", s.AuthSecretResourceVersion, "secret resource version")
+				assert.Equal(t, []string{"gatewayclass-ok"}, s.GatewayClasses, "gateway-classes")
+
+				cond := meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionAccepted))
+				assert.NotNil(t, cond, "accepted cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "accepted status")
+
+				cond = meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionResolvedRefs))
+				assert.NotNil(t, cond, "resolved-refs cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "resolved-refs status")
+			},
+		},
+		{
+			name: "gatewayconfig status with missing auth secret",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				w := testutils.TestGwConfig.DeepCopy()
+				w.Spec.AuthRef = &gwapiv1.SecretObjectReference{
+					Name: gwapiv1.ObjectName("dummy-secret"),
+				}
+				w.Spec.AuthType = nil
+				w.Spec.Username = nil
+				w.Spec.Password = nil
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				s := r.setGatewayConfigStatus(c).Status
+				assert.Equal(t, "", s.AuthType, "auth-type")
+				assert.Equal(t, "", s.AuthSecretResourceVersion, "secret resource version")
+
+				cond := meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionAccepted))
+				assert.NotNil(t, cond, "accepted cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "accepted status")
+				assert.Equal(t, string(stnrgwv1.GatewayConfigReasonInvalid), cond.Reason,
+					"accepted reason")
+
+				cond = meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionResolvedRefs))
+				assert.NotNil(t, cond, "resolved-refs cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "resolved-refs status")
+				assert.Equal(t, string(stnrgwv1.GatewayConfigReasonInvalidAuthRef), cond.Reason,
+					"resolved-refs reason")
+			},
+		},
+		{
+			name: "gatewayconfig status with invalid shared secret",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				w := testutils.TestGwConfig.DeepCopy()
+				atype := "ephemeral"
+				w.Spec.AuthType = &atype
+				w.Spec.SharedSecret = nil
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				s := r.setGatewayConfigStatus(c).Status
+				assert.Equal(t, "", s.AuthType, "auth-type")
+
+				cond := meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionAccepted))
+				assert.NotNil(t, cond, "accepted cond")
+				assert.Equal(t, metav1.ConditionFalse, cond.Status, "accepted status")
+				assert.Contains(t, cond.Message, "missing shared-secret", "accepted message")
+
+				cond = meta.FindStatusCondition(s.Conditions,
+					string(stnrgwv1.GatewayConfigConditionResolvedRefs))
+				assert.NotNil(t, cond, "resolved-refs cond")
+				assert.Equal(t, metav1.ConditionTrue, cond.Status, "resolved-refs status")
+			},
+		},
 	})
 }
@@ -0,0 +1,143 @@
+package renderer
+
+import (
+	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+var _ resourceGenerator = &hpaGenerator{}
+
+// The HorizontalPodAutoscaler generator creates an autoscaler for the dataplane Deployment of a
+// managed Gateway, provided that the Dataplane used by the Gateway enables autoscaling. The
+// HorizontalPodAutoscaler is named after the Gateway, scales the Deployment of the Gateway and
+// is owned by the Gateway. Labels and annotations are the same as for the PodDisruptionBudget.
+//
+// The min replicas and the metrics are always set explicitly: otherwise the API server would
+// default them and the updater would see a diff on every render.
+type hpaGenerator struct {
+	scheme *runtime.Scheme
+}
+
+func newHPAGenerator(scheme *runtime.Scheme) resourceGenerator {
+	return &hpaGenerator{scheme: scheme}
+}
+
+// generate returns the HorizontalPodAutoscaler for the Gateway, or nil if the Dataplane does not
+// enable autoscaling or deploys the dataplane into a DaemonSet.
+func (r *hpaGenerator) generate(c *RenderContext) (client.Object, error) {
+	gw := c.gws.GetFirst()
+	if gw == nil {
+		c.log.Info("Internal error: empty Gateway ref in managed mode")
+		return nil, NewCriticalError(RenderingError)
+	}
+
+	dataplane, err := getDataplane(c)
+	if err != nil {
+		return nil, err
+	}
+
+	as := dataplane.Spec.Autoscaling
+	if as == nil || !isDeploymentDataplane(dataplane) {
+		return nil, nil
+	}
+
+	minReplicas := int32(1)
+	if as.MinReplicas != nil {
+		minReplicas = *as.MinReplicas
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        gw.GetName(),
+			Namespace:   gw.GetNamespace(),
+			Labels:      getDataplaneLabels(c),
+			Annotations: getDataplaneAnnotations(c),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       gw.GetName(),
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: as.MaxReplicas,
+			Metrics:     getAutoscalingMetrics(as),
+		},
+	}
+
+	// owned by the Gateway
+	if err := controllerutil.SetOwnerReference(gw, hpa, r.scheme); err != nil {
+		c.log.Error(err, "Cannot set owner reference", "owner", store.GetObjectKey(gw),
+			"reference", store.GetObjectKey(hpa))
+		return nil, NewCriticalError(RenderingError)
+	}
+
+	return hpa, nil
+}
+
+// getAutoscalingMetrics returns the metric targets of the autoscaler, falling back to the
+// default CPU utilization target if none is set.
+func getAutoscalingMetrics(as *stnrgwv1.DataplaneAutoscaling) []autoscalingv2.MetricSpec {
+	cpuMetric := func(target int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &target,
+				},
+			},
+		}
+	}
+
+	ret := []autoscalingv2.MetricSpec{}
+	if as.TargetCPUUtilizationPercentage != nil {
+		ret = append(ret, cpuMetric(*as.TargetCPUUtilizationPercentage))
+	}
+
+	if as.TargetActiveAllocations != nil {
+		target := resource.NewQuantity(int64(*as.TargetActiveAllocations), resource.DecimalSI)
+		ret = append(ret, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: opdefault.AllocationsActiveMetricName,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: target,
+				},
+			},
+		})
+	}
+
+	if len(ret) == 0 {
+		ret = append(ret, cpuMetric(opdefault.DefaultAutoscalingTargetCPUUtilization))
+	}
+
+	return ret
+}
+
+// isDeploymentDataplane returns true if the dataplane is deployed into a Deployment.
+func isDeploymentDataplane(dataplane *stnrgwv1.Dataplane) bool {
+	r := dataplane.Spec.DataplaneResource
+	return r == nil || *r == stnrgwv1.DataplaneResourceDeployment
+}
+
+// isAutoscaledDataplane returns true if the replicas of the dataplane Deployment are managed by
+// an autoscaler.
+func isAutoscaledDataplane(dataplane *stnrgwv1.Dataplane) bool {
+	return dataplane.Spec.Autoscaling != nil && isDeploymentDataplane(dataplane)
+}
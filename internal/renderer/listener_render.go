@@ -121,6 +121,8 @@ func (r *listenerRenderer) getTLS(gw *gwapiv1.Gateway, l *gwapiv1.Listener) (str
 			"gateway", store.GetObjectKey(gw), "listener", l.Name)
 	}
 
+	// report RefNotPermitted only if no certificate reference could be resolved at all
+	var refErr error
 	for _, ref := range l.TLS.CertificateRefs {
 		ref := ref
 
@@ -133,6 +135,14 @@ func (r *listenerRenderer) getTLS(gw *gwapiv1.Gateway, l *gwapiv1.Listener) (str
 			continue
 		}
 
+		if !isReferenceGranted(gwapiv1.GroupVersion.Group, "Gateway", gw.GetNamespace(),
+			corev1.GroupName, "Secret", n) {
+			refErr = NewNonCriticalError(RefNotPermitted)
+			r.log.Info("Ignoring cross-namespace secret-reference not permitted by any ReferenceGrant",
+				"gateway", store.GetObjectKey(gw), "listener", l.Name, "secret", n.String())
+			continue
+		}
+
 		secret := store.TLSSecrets.GetObject(n)
 		if secret == nil {
 			r.log.Info("Secret not found", "gateway", store.GetObjectKey(gw),
@@ -171,6 +181,10 @@ func (r *listenerRenderer) getTLS(gw *gwapiv1.Gateway, l *gwapiv1.Listener) (str
 			base64.StdEncoding.EncodeToString(key), true, nil
 	}
 
+	if refErr != nil {
+		return "", "", false, refErr
+	}
+
 	return "", "", false, NewNonCriticalError(InvalidCertificateRef)
 }
 
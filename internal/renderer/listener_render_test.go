@@ -9,11 +9,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
-	// metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	// "k8s.io/apimachinery/pkg/types"
 	// "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
@@ -47,7 +49,7 @@ func TestRenderListenerRender(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 
 				addr := gwAddrPort{
@@ -166,7 +168,7 @@ func TestRenderListenerRender(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 
 				addr := gwAddrPort{
@@ -214,7 +216,7 @@ func TestRenderListenerRender(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 
 				addr := gwAddrPort{
@@ -909,5 +911,143 @@ func TestRenderListenerRender(t *testing.T) {
 				assert.Equal(t, testutils.TestKey64, lc.Key, "key")
 			},
 		},
+		{
+			name:  "TLS/DTLS listener - cross-namespace secret ref not permitted",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs:  []corev1.Service{testutils.TestSvc},
+			scrts: []corev1.Secret{testutils.TestSecret},
+			prep: func(c *renderTestConfig) {
+				s := testutils.TestSecret.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				c.scrts = []corev1.Secret{*s}
+
+				gw := testutils.TestGw.DeepCopy()
+				mode := gwapiv1.TLSModeTerminate
+				ns := gwapiv1.Namespace("dummy-ns")
+				tls := gwapiv1.ListenerTLSConfig{
+					Mode: &mode,
+					CertificateRefs: []gwapiv1.SecretObjectReference{{
+						Namespace: &ns,
+						Name:      gwapiv1.ObjectName("testsecret-ok"),
+					}},
+				}
+				gw.Spec.Listeners = []gwapiv1.Listener{{
+					Name:     gwapiv1.SectionName("gateway-1-listener-tls"),
+					Protocol: gwapiv1.ProtocolType("TURN-TLS"),
+					Port:     gwapiv1.PortNumber(1),
+					TLS:      &tls,
+				}}
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+				c.gws = store.NewGatewayStore()
+				c.gws.ResetGateways([]*gwapiv1.Gateway{gw})
+
+				ls := gw.Spec.Listeners
+				l := ls[0]
+
+				rs := []*stnrgwv1.UDPRoute{}
+				addr := gwAddrPort{
+					addr: "1.2.3.4",
+					port: 1234,
+				}
+
+				_, err = r.renderListener(c, &l, rs, addr, nil)
+				assert.Error(t, err, "renderListener")
+				assert.True(t, IsNonCriticalError(err, RefNotPermitted), "ref not permitted")
+
+				initGatewayStatus(gw, nil)
+				setListenerStatus(gw, &l, err, false, 0)
+				s := gw.Status.Listeners[0]
+				accepted := meta.FindStatusCondition(s.Conditions,
+					string(gwapiv1.ListenerConditionAccepted))
+				assert.NotNil(t, accepted, "accepted cond")
+				assert.Equal(t, metav1.ConditionTrue, accepted.Status, "accepted status")
+				resolved := meta.FindStatusCondition(s.Conditions,
+					string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, resolved, "resolved-refs cond")
+				assert.Equal(t, metav1.ConditionFalse, resolved.Status, "resolved-refs status")
+				assert.Equal(t, string(gwapiv1.ListenerReasonRefNotPermitted), resolved.Reason,
+					"resolved-refs reason")
+			},
+		},
+		{
+			name:  "TLS/DTLS listener - cross-namespace secret ref permitted by ReferenceGrant",
+			cls:   []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:   []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:   []gwapiv1.Gateway{testutils.TestGw},
+			rs:    []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs:  []corev1.Service{testutils.TestSvc},
+			scrts: []corev1.Secret{testutils.TestSecret},
+			prep: func(c *renderTestConfig) {
+				s := testutils.TestSecret.DeepCopy()
+				s.SetNamespace("dummy-ns")
+				c.scrts = []corev1.Secret{*s}
+
+				gw := testutils.TestGw.DeepCopy()
+				mode := gwapiv1.TLSModeTerminate
+				ns := gwapiv1.Namespace("dummy-ns")
+				tls := gwapiv1.ListenerTLSConfig{
+					Mode: &mode,
+					CertificateRefs: []gwapiv1.SecretObjectReference{{
+						Namespace: &ns,
+						Name:      gwapiv1.ObjectName("testsecret-ok"),
+					}},
+				}
+				gw.Spec.Listeners = []gwapiv1.Listener{{
+					Name:     gwapiv1.SectionName("gateway-1-listener-tls"),
+					Protocol: gwapiv1.ProtocolType("TURN-TLS"),
+					Port:     gwapiv1.PortNumber(1),
+					TLS:      &tls,
+				}}
+				c.gws = []gwapiv1.Gateway{*gw}
+				// allow Gateways in "testnamespace" to refer to Secrets in "dummy-ns"
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.Spec.From[0].Group = gwapiv1.GroupName
+				rg.Spec.From[0].Kind = "Gateway"
+				rg.Spec.To[0].Kind = "Secret"
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+				c.gws = store.NewGatewayStore()
+				c.gws.ResetGateways([]*gwapiv1.Gateway{gw})
+
+				ls := gw.Spec.Listeners
+				l := ls[0]
+
+				rs := []*stnrgwv1.UDPRoute{}
+				addr := gwAddrPort{
+					addr: "1.2.3.4",
+					port: 1234,
+				}
+
+				lc, err := r.renderListener(c, &l, rs, addr, nil)
+				assert.NoError(t, err, "renderListener")
+				assert.Equal(t, "testnamespace/gateway-1/gateway-1-listener-tls", lc.Name, "name")
+				assert.Equal(t, testutils.TestCert64, lc.Cert, "cert")
+				assert.Equal(t, testutils.TestKey64, lc.Key, "key")
+			},
+		},
 	})
 }
@@ -0,0 +1,53 @@
+package renderer
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+// gatewayStats collects the per-Gateway statistics of a render.
+type gatewayStats struct {
+	metrics.GatewayStats
+	// routes is the set of routes attached to the rendered listeners of the Gateway
+	routes map[string]bool
+}
+
+func newGatewayStats() *gatewayStats {
+	return &gatewayStats{routes: map[string]bool{}}
+}
+
+// setGatewayMetrics exports the statistics of a Gateway, counting the clusters rendered for the
+// routes attached to the Gateway.
+func setGatewayMetrics(c *RenderContext, gw *gwapiv1.Gateway, s *gatewayStats, clusters []stnrconfv1.ClusterConfig) {
+	s.AttachedRoutes = len(s.routes)
+	for _, cluster := range clusters {
+		if s.routes[cluster.Name] {
+			s.Clusters++
+			s.Endpoints += len(cluster.Endpoints)
+		}
+	}
+
+	metrics.SetGatewayStats(c.gc.GetName(), store.GetNamespacedName(gw), s.GatewayStats)
+}
+
+// recordGatewayMetricsError counts a render error for a Gateway.
+func recordGatewayMetricsError(c *RenderContext, gw *gwapiv1.Gateway, err error) {
+	if err == nil || IsNonCriticalError(err, ClusterIPNotFound) {
+		// missing ClusterIP is fine for headless Services
+		return
+	}
+
+	metrics.RecordGatewayRenderError(c.gc.GetName(), store.GetNamespacedName(gw), errorReason(err))
+}
+
+// pruneGatewayMetrics removes the metrics of the deleted Gateways.
+func pruneGatewayMetrics() {
+	metrics.PruneGatewayMetrics(func(gw types.NamespacedName) bool {
+		return store.Gateways.GetObject(gw) != nil
+	})
+}
@@ -0,0 +1,83 @@
+package renderer
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+)
+
+var _ resourceGenerator = &pdbGenerator{}
+
+// The PodDisruptionBudget generator creates a PodDisruptionBudget for the dataplane pods of a
+// managed Gateway, provided that the Dataplane used by the Gateway specifies a disruption
+// budget. The PodDisruptionBudget is named after the Gateway, uses the same selector as the
+// dataplane resource and is owned by the Gateway.
+//
+// Labels:
+//   - stunner.l7mp.io/owned-by=stunner
+//   - stunner.l7mp.io/related-gateway-name=<gateway-name>
+//   - stunner.l7mp.io/related-gateway-namespace=<gateway-namespace>
+//
+// Annotations:
+//   - stunner.l7mp.io/related-gateway-name=<gateway-namespace/gateway-name>
+type pdbGenerator struct {
+	scheme *runtime.Scheme
+}
+
+func newPDBGenerator(scheme *runtime.Scheme) resourceGenerator {
+	return &pdbGenerator{scheme: scheme}
+}
+
+// generate returns the PodDisruptionBudget for the Gateway, or nil if the Dataplane does not
+// specify a disruption budget.
+func (r *pdbGenerator) generate(c *RenderContext) (client.Object, error) {
+	gw := c.gws.GetFirst()
+	if gw == nil {
+		c.log.Info("Internal error: empty Gateway ref in managed mode")
+		return nil, NewCriticalError(RenderingError)
+	}
+
+	dataplane, err := getDataplane(c)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := dataplane.Spec.PodDisruptionBudget
+	if budget == nil {
+		return nil, nil
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        gw.GetName(),
+			Namespace:   gw.GetNamespace(),
+			Labels:      getDataplaneLabels(c),
+			Annotations: getDataplaneAnnotations(c),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: getDataplanePodSelector(c),
+		},
+	}
+
+	if budget.MinAvailable != nil {
+		v := *budget.MinAvailable
+		pdb.Spec.MinAvailable = &v
+	}
+	if budget.MaxUnavailable != nil {
+		v := *budget.MaxUnavailable
+		pdb.Spec.MaxUnavailable = &v
+	}
+
+	// owned by the Gateway
+	if err := controllerutil.SetOwnerReference(gw, pdb, r.scheme); err != nil {
+		c.log.Error(err, "Cannot set owner reference", "owner", store.GetObjectKey(gw),
+			"reference", store.GetObjectKey(pdb))
+		return nil, NewCriticalError(RenderingError)
+	}
+
+	return pdb, nil
+}
@@ -0,0 +1,288 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/lens"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// The pod template patch of a Dataplane is applied on top of the pod template generated by the
+// operator, after which the below fields are reset to the generated value:
+//
+// Pod-level labels and annotations:
+//   - the mandatory pod labels (app=stunner and the related-gateway labels), which are used in
+//     the Deployment selector
+//   - the mandatory pod annotations (the related-gateway annotation)
+//
+// The stunnerd container:
+//   - re-added if the patch removes or renames it
+//   - the image, the command and the arguments, which can be set with the dedicated Dataplane
+//     fields
+//   - the environment variables the stunnerd uses to connect to the config discovery server:
+//     gateway name and namespace, node name and CDS server address
+//
+// The patch may set only the pod template fields the Deployment and DaemonSet lenses reconcile,
+// see lens.ValidatePodTemplateFields, and a patch that sets any other field, e.g., hostPID or the
+// tty of a container, is rejected. The fields set by the patch are recorded in the
+// last-applied-pod-template-patch annotation of the dataplane resource so that the updater can
+// reset them once they are removed from the patch.
+
+// mandatoryStunnerdEnvVars lists the stunnerd environment variables that cannot be overridden
+// by a pod template patch.
+var mandatoryStunnerdEnvVars = map[string]bool{
+	stnrconfv1.DefaultEnvVarName:         true,
+	stnrconfv1.DefaultEnvVarNamespace:    true,
+	stnrconfv1.DefaultEnvVarNodeName:     true,
+	stnrconfv1.DefaultEnvVarConfigOrigin: true,
+}
+
+// patchPodTemplate applies the pod template patch of a Dataplane, if any, to a pod template
+// generated by the operator, enforces the mandatory fields on the result and records the fields
+// set by the patch in the dataplane resource.
+func patchPodTemplate(c *RenderContext, dataplane *stnrgwv1.Dataplane, obj client.Object, template *corev1.PodTemplateSpec) error {
+	p := dataplane.Spec.PodTemplatePatch
+	if p == nil || len(p.Patch.Raw) == 0 {
+		return nil
+	}
+
+	patched, err := applyPodTemplatePatch(template, p)
+	if err != nil {
+		c.log.Error(err, "Cannot apply pod template patch", "dataplane",
+			store.GetObjectKey(dataplane))
+		return NewCriticalError(InvalidDataplane)
+	}
+
+	enforcePodTemplate(c, template, patched)
+
+	fields, err := getPatchedPodTemplateFields(template, patched)
+	if err == nil {
+		err = lens.ValidatePodTemplateFields(fields)
+	}
+	if err != nil {
+		c.log.Error(err, "Invalid pod template patch", "dataplane",
+			store.GetObjectKey(dataplane))
+		return NewCriticalError(InvalidDataplane)
+	}
+
+	*template = *patched
+	if len(fields) > 0 {
+		store.SetLastAppliedPodTemplateFields(obj, fields)
+	}
+
+	return nil
+}
+
+func applyPodTemplatePatch(template *corev1.PodTemplateSpec, p *stnrgwv1.DataplanePodTemplatePatch) (*corev1.PodTemplateSpec, error) {
+	orig, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	patchType := stnrgwv1.DataplanePodTemplatePatchStrategicMerge
+	if p.Type != nil {
+		patchType = *p.Type
+	}
+
+	var res []byte
+	switch patchType {
+	case stnrgwv1.DataplanePodTemplatePatchStrategicMerge:
+		res, err = strategicpatch.StrategicMergePatch(orig, p.Patch.Raw, corev1.PodTemplateSpec{})
+	case stnrgwv1.DataplanePodTemplatePatchJSONPatch:
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(p.Patch.Raw)
+		if err == nil {
+			res, err = patch.Apply(orig)
+		}
+	default:
+		err = fmt.Errorf("unknown pod template patch type %q", patchType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	patched := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(res, &patched); err != nil {
+		return nil, err
+	}
+
+	return &patched, nil
+}
+
+// enforcePodTemplate resets the mandatory fields in a patched pod template to the value
+// generated by the operator.
+func enforcePodTemplate(c *RenderContext, generated, patched *corev1.PodTemplateSpec) {
+	patched.SetLabels(store.MergeMetadata(patched.GetLabels(), getPodLabels(c)))
+	patched.SetAnnotations(store.MergeMetadata(patched.GetAnnotations(), getDataplaneAnnotations(c)))
+
+	gen := findStunnerdContainer(generated.Spec.Containers)
+	if gen == nil {
+		return
+	}
+
+	cont := findStunnerdContainer(patched.Spec.Containers)
+	if cont == nil {
+		patched.Spec.Containers = append([]corev1.Container{*gen.DeepCopy()},
+			patched.Spec.Containers...)
+		cont = &patched.Spec.Containers[0]
+	}
+
+	cont.Image = gen.Image
+	cont.Command = slices.Clone(gen.Command)
+	cont.Args = slices.Clone(gen.Args)
+
+	for _, e := range gen.Env {
+		if mandatoryStunnerdEnvVars[e.Name] {
+			cont.Env = upsertByName(cont.Env, e, func(e corev1.EnvVar) string { return e.Name })
+		}
+	}
+}
+
+// getPatchedPodTemplateFields returns the paths of the pod template fields that differ between
+// the generated and the patched pod template, in the format of the lens package. Containers are
+// compared by name and field by field, and a container added by the patch is reported both as a
+// whole and by each of its fields.
+func getPatchedPodTemplateFields(generated, patched *corev1.PodTemplateSpec) ([]string, error) {
+	fields := []string{}
+
+	gen, err := toJSONMap(generated.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	pat, err := toJSONMap(patched.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, diffJSONMaps("metadata.", gen, pat)...)
+
+	gen, err = toJSONMap(generated.Spec)
+	if err != nil {
+		return nil, err
+	}
+	pat, err = toJSONMap(patched.Spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range []string{"containers", "initContainers"} {
+		delete(gen, list)
+		delete(pat, list)
+	}
+	fields = append(fields, diffJSONMaps("spec.", gen, pat)...)
+
+	cs, err := diffContainers("spec.containers", generated.Spec.Containers, patched.Spec.Containers)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, cs...)
+
+	cs, err = diffContainers("spec.initContainers", generated.Spec.InitContainers,
+		patched.Spec.InitContainers)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, cs...)
+
+	slices.Sort(fields)
+	return fields, nil
+}
+
+func diffContainers(prefix string, generated, patched []corev1.Container) ([]string, error) {
+	fields := []string{}
+	names := map[string]bool{}
+	for _, c := range append(slices.Clone(generated), patched...) {
+		names[c.Name] = true
+	}
+
+	for name := range names {
+		gen, pat := findContainer(generated, name), findContainer(patched, name)
+		path := fmt.Sprintf("%s[%s]", prefix, name)
+		if gen == nil || pat == nil {
+			fields = append(fields, path)
+		}
+		if pat == nil {
+			continue
+		}
+		// the fields of an added container are compared to an empty container
+		if gen == nil {
+			gen = &corev1.Container{Name: name}
+		}
+
+		g, err := toJSONMap(gen)
+		if err != nil {
+			return nil, err
+		}
+		p, err := toJSONMap(pat)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, diffJSONMaps(path+".", g, p)...)
+	}
+
+	return fields, nil
+}
+
+// diffJSONMaps returns the keys with a different value in the two maps, with a prefix.
+func diffJSONMaps(prefix string, a, b map[string]any) []string {
+	ret := []string{}
+	for k, v := range a {
+		if !apiequality.Semantic.DeepEqual(v, b[k]) {
+			ret = append(ret, prefix+k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			ret = append(ret, prefix+k)
+		}
+	}
+	return ret
+}
+
+func toJSONMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]any{}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func findContainer(cs []corev1.Container, name string) *corev1.Container {
+	for i := range cs {
+		if cs[i].Name == name {
+			return &cs[i]
+		}
+	}
+	return nil
+}
+
+func findStunnerdContainer(cs []corev1.Container) *corev1.Container {
+	return findContainer(cs, opdefault.DefaultStunnerdInstanceName)
+}
+
+// upsertByName replaces the item with the same name in a list or appends the item if there is
+// no such item.
+func upsertByName[T any](list []T, item T, name func(T) string) []T {
+	for i := range list {
+		if name(list[i]) == name(item) {
+			list[i] = item
+			return list
+		}
+	}
+	return append(list, item)
+}
@@ -0,0 +1,69 @@
+package renderer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// isReferenceGranted checks whether an object of the given Group and Kind in namespace
+// "fromNamespace" is allowed to refer to the named target of the given Group and Kind. References
+// within the same namespace are always allowed, cross-namespace references must be explicitly
+// permitted by a ReferenceGrant in the namespace of the target.
+func isReferenceGranted(fromGroup, fromKind, fromNamespace, toGroup, toKind string, to types.NamespacedName) bool {
+	if fromNamespace == to.Namespace {
+		return true
+	}
+
+	for _, rg := range store.ReferenceGrants.GetAll() {
+		if rg.GetNamespace() != to.Namespace {
+			continue
+		}
+
+		fromOk := false
+		for _, f := range rg.Spec.From {
+			if string(f.Group) == fromGroup && string(f.Kind) == fromKind &&
+				string(f.Namespace) == fromNamespace {
+				fromOk = true
+				break
+			}
+		}
+		if !fromOk {
+			continue
+		}
+
+		for _, t := range rg.Spec.To {
+			if string(t.Group) == toGroup && string(t.Kind) == toKind &&
+				(t.Name == nil || string(*t.Name) == to.Name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isBackendReferenceGranted checks whether a route is allowed to refer to a backend Service or
+// StaticService in namespace "ns". Backends of an unknown Kind are let through: these are
+// reported separately.
+func isBackendReferenceGranted(ro *stnrgwv1.UDPRoute, b *stnrgwv1.BackendRef, ns string) bool {
+	// routes converted from Gateway API objects are granted access as per their original group
+	// and kind
+	fromGroup, fromKind := getRouteGroupKind(ro)
+
+	var toGroup, toKind string
+	switch {
+	case store.IsReferenceService(b):
+		toGroup, toKind = corev1.GroupName, "Service"
+	case store.IsReferenceStaticService(b):
+		toGroup, toKind = stnrgwv1.GroupVersion.Group, "StaticService"
+	default:
+		return true
+	}
+
+	return isReferenceGranted(fromGroup, fromKind, ro.GetNamespace(), toGroup, toKind,
+		types.NamespacedName{Namespace: ns, Name: string(b.Name)})
+}
@@ -7,11 +7,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
@@ -184,3 +186,64 @@ func BenchmarkRenderPipeline(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkRenderIncremental compares a full render with an incremental render triggered by a
+// single EndpointSlice change, with varying numbers of gateways.
+func BenchmarkRenderIncremental(b *testing.B) {
+	sizes := []int{1, 8, 64, 512}
+
+	for _, full := range []bool{true, false} {
+		for _, n := range sizes {
+			mode := "incremental"
+			if full {
+				mode = "full"
+			}
+
+			b.Run(fmt.Sprintf("%s/N=%d", mode, n), func(b *testing.B) {
+				benchmarkSetup(n)
+
+				config.DataplaneMode = config.DataplaneModeManaged
+				config.EnableEndpointDiscovery = true
+				config.EnableRelayToClusterIP = true
+				config.EndpointSliceAvailable = true
+
+				r := NewDefaultRenderer(RendererConfig{
+					Scheme:         scheme,
+					LicenseManager: licensemgr.NewStubManager("", log),
+					Logger:         log.WithName("benchmark-renderer"),
+				}).(*renderer)
+
+				ch := make(chan event.Event, 1)
+				r.SetOperatorChannel(event.NewEventChannel(ch))
+
+				// the first render is always a full render
+				r.Render(event.NewEventRender(0))
+				<-ch
+
+				changes := []event.ObjectKey{}
+				if !full {
+					changes = append(changes, event.ObjectKey{Kind: "EndpointSlice",
+						NamespacedName: types.NamespacedName{
+							Namespace: "testnamespace-0",
+							Name:      "testendpointslice-0",
+						}})
+				}
+
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					e := event.NewEventRender(i + 1)
+					e.Changes = changes
+					r.Render(e)
+					<-ch
+				}
+
+				b.StopTimer()
+
+				config.EnableEndpointDiscovery = opdefault.DefaultEnableEndpointDiscovery
+				config.EnableRelayToClusterIP = opdefault.DefaultEnableRelayToClusterIP
+				config.DataplaneMode = config.NewDataplaneMode(opdefault.DefaultDataplaneMode)
+			})
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package renderer
+
+import (
+	"sort"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// gatewayCacheEntry is the result of the last render for a Gateway.
+type gatewayCacheEntry struct {
+	// config is the last rendered config, nil if the render failed.
+	config *stnrconfv1.StunnerConfig
+	// dataplane is the name of the Dataplane the Gateway uses, empty if the render failed or
+	// the managed dataplane is disabled for the Gateway.
+	dataplane string
+}
+
+// renderCache keeps the per-Gateway render results and a dependency index between Gateways and
+// the objects they were rendered from, in order to re-render only the Gateways affected by a
+// change in managed mode. Gateways not affected by the change are not rendered at all: the CDS
+// server still receives the config for all Gateways from the cache.
+type renderCache struct {
+	gateways map[string]*gatewayCacheEntry
+	// deps maps a dependency to the keys of the Gateways that depend on it
+	deps map[event.ObjectKey]map[string]bool
+	// gwDeps maps a Gateway key to its dependencies
+	gwDeps map[string][]event.ObjectKey
+	// valid is false until the first full render
+	valid bool
+}
+
+func newRenderCache() *renderCache {
+	c := &renderCache{}
+	c.reset()
+	return c
+}
+
+// reset invalidates the cache: the next render will be a full render.
+func (c *renderCache) reset() {
+	c.gateways = map[string]*gatewayCacheEntry{}
+	c.deps = map[event.ObjectKey]map[string]bool{}
+	c.gwDeps = map[string][]event.ObjectKey{}
+	c.valid = false
+}
+
+// set stores the render result and the dependencies for a Gateway.
+func (c *renderCache) set(gw string, entry *gatewayCacheEntry, deps []event.ObjectKey) {
+	c.remove(gw)
+
+	c.gateways[gw] = entry
+	c.gwDeps[gw] = deps
+	for _, k := range deps {
+		if _, ok := c.deps[k]; !ok {
+			c.deps[k] = map[string]bool{}
+		}
+		c.deps[k][gw] = true
+	}
+}
+
+// remove removes a Gateway from the cache.
+func (c *renderCache) remove(gw string) {
+	for _, k := range c.gwDeps[gw] {
+		delete(c.deps[k], gw)
+		if len(c.deps[k]) == 0 {
+			delete(c.deps, k)
+		}
+	}
+	delete(c.gwDeps, gw)
+	delete(c.gateways, gw)
+}
+
+// prune removes the Gateways that were not seen during the last render.
+func (c *renderCache) prune(seen map[string]bool) {
+	for gw := range c.gateways {
+		if !seen[gw] {
+			c.remove(gw)
+		}
+	}
+}
+
+// lookup returns the keys of the Gateways that depend on an object.
+func (c *renderCache) lookup(k event.ObjectKey) []string {
+	ret := []string{}
+	for gw := range c.deps[k] {
+		ret = append(ret, gw)
+	}
+	return ret
+}
+
+// configs returns the cached configs, sorted by the Gateway key.
+func (c *renderCache) configs() []*stnrconfv1.StunnerConfig {
+	keys := []string{}
+	for gw, e := range c.gateways {
+		if e.config != nil {
+			keys = append(keys, gw)
+		}
+	}
+	sort.Strings(keys)
+
+	ret := make([]*stnrconfv1.StunnerConfig, 0, len(keys))
+	for _, gw := range keys {
+		ret = append(ret, c.gateways[gw].config)
+	}
+	return ret
+}
+
+// getAffectedGateways returns the keys of the Gateways that must be re-rendered due to a set of
+// changes. Returns nil if a full render is needed: the change set is empty (unknown), the cache is
+// invalid, or there is a change in an object that may affect any Gateway (e.g., a GatewayClass, a
+// GatewayConfig or a ReferenceGrant).
+func (r *renderer) getAffectedGateways(changes []event.ObjectKey) map[string]bool {
+	if len(changes) == 0 || !r.cache.valid {
+		return nil
+	}
+
+	ret := map[string]bool{}
+	for _, k := range changes {
+		switch k.Kind {
+		case "Gateway", "Deployment", "DaemonSet", "PodDisruptionBudget", "HorizontalPodAutoscaler":
+			// dataplane resources are named after the Gateway
+			ret[k.NamespacedName.String()] = true
+		case "Service":
+			// the LoadBalancer Service is named after the Gateway, but any Service
+			// annotated with the Gateway name may also be used
+			ret[k.NamespacedName.String()] = true
+			if svc := store.Services.GetObject(k.NamespacedName); svc != nil {
+				if gw, ok := svc.GetAnnotations()[opdefault.RelatedGatewayKey]; ok {
+					ret[gw] = true
+				}
+			}
+		case "UDPRoute", "TCPRoute", "TLSRoute":
+			// the parents of the route may have changed
+			for _, gw := range getRouteParents(k.Kind, k.NamespacedName) {
+				ret[gw] = true
+			}
+		case "EndpointSlice":
+			// new EndpointSlices are not among the dependencies recorded at the last
+			// render: map the slice to the Service it belongs to
+			if esl := store.EndpointSlices.GetObject(k.NamespacedName); esl != nil {
+				if svc, ok := esl.GetLabels()[discoveryv1.LabelServiceName]; ok {
+					for _, gw := range r.cache.lookup(event.ObjectKey{Kind: "Service",
+						NamespacedName: types.NamespacedName{Namespace: k.Namespace, Name: svc}}) {
+						ret[gw] = true
+					}
+				}
+			}
+		case "Secret", "Endpoints", "StaticService", "Dataplane":
+		default:
+			return nil
+		}
+
+		for _, gw := range r.cache.lookup(k) {
+			ret[gw] = true
+		}
+	}
+
+	return ret
+}
+
+// getRouteParents returns the keys of the parent Gateways of a route of the given kind in the
+// current state.
+func getRouteParents(kind string, key types.NamespacedName) []string {
+	var stores []*store.UDPRouteStore
+	switch kind {
+	case "TCPRoute":
+		stores = []*store.UDPRouteStore{store.TCPRoutes}
+	case "TLSRoute":
+		stores = []*store.UDPRouteStore{store.TLSRoutes}
+	default:
+		stores = []*store.UDPRouteStore{store.UDPRoutes, store.UDPRoutesV1A2}
+	}
+
+	ret := []string{}
+	for _, s := range stores {
+		if ro := s.GetObject(key); ro != nil {
+			ret = append(ret, getParentGatewayKeys(ro)...)
+		}
+	}
+	return ret
+}
+
+// getParentGatewayKeys returns the keys of the Gateways referred to by the parent references of a
+// route. The Gateways may not exist.
+func getParentGatewayKeys(ro *stnrgwv1.UDPRoute) []string {
+	ret := []string{}
+	for i := range ro.Spec.ParentRefs {
+		p := &ro.Spec.ParentRefs[i]
+		if (p.Group != nil && string(*p.Group) != gwapiv1.GroupName) ||
+			(p.Kind != nil && string(*p.Kind) != "Gateway") {
+			continue
+		}
+		ns := ro.GetNamespace()
+		if p.Namespace != nil {
+			ns = string(*p.Namespace)
+		}
+		ret = append(ret, types.NamespacedName{Namespace: ns, Name: string(p.Name)}.String())
+	}
+	return ret
+}
+
+// getGatewayDependencies returns the objects the render of a Gateway depends on: the Gateway and
+// its dataplane resources, the Dataplane, the TLS Secrets, the public Service, the routes
+// attached to the Gateway and the backends of these routes.
+func (r *renderer) getGatewayDependencies(gwConf *stnrgwv1.GatewayConfig, gw *gwapiv1.Gateway) []event.ObjectKey {
+	deps := map[event.ObjectKey]bool{}
+	add := func(kind, namespace, name string) {
+		deps[event.ObjectKey{Kind: kind,
+			NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}] = true
+	}
+
+	add("Gateway", gw.GetNamespace(), gw.GetName())
+	add("Deployment", gw.GetNamespace(), gw.GetName())
+	add("DaemonSet", gw.GetNamespace(), gw.GetName())
+	add("PodDisruptionBudget", gw.GetNamespace(), gw.GetName())
+	add("HorizontalPodAutoscaler", gw.GetNamespace(), gw.GetName())
+	add("Service", gw.GetNamespace(), gw.GetName())
+
+	// the Dataplane may not exist yet: the Gateway is re-rendered once it is created
+	if dp, err := getDataplaneName(gwConf, gw); err == nil {
+		add("Dataplane", "", dp)
+	}
+
+	if svc, err := r.getPublicSvc(gw); err == nil {
+		add("Service", svc.GetNamespace(), svc.GetName())
+	}
+
+	for _, l := range gw.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			ns := gw.GetNamespace()
+			if ref.Namespace != nil {
+				ns = string(*ref.Namespace)
+			}
+			add("Secret", ns, string(ref.Name))
+		}
+	}
+
+	key := store.GetObjectKey(gw)
+	for _, ro := range r.allRoutes() {
+		attached := false
+		for _, gwKey := range getParentGatewayKeys(ro) {
+			if gwKey == key {
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			continue
+		}
+
+		_, kind := getRouteGroupKind(ro)
+		add(kind, ro.GetNamespace(), ro.GetName())
+
+		for _, rule := range ro.Spec.Rules {
+			for i := range rule.BackendRefs {
+				b := &rule.BackendRefs[i]
+				ns := ro.GetNamespace()
+				if b.Namespace != nil {
+					ns = string(*b.Namespace)
+				}
+
+				switch {
+				case store.IsReferenceService(b):
+					add("Service", ns, string(b.Name))
+					add("Endpoints", ns, string(b.Name))
+					for _, esl := range getEndpointSlices4Service(ns, string(b.Name)) {
+						add("EndpointSlice", esl.GetNamespace(), esl.GetName())
+					}
+				case store.IsReferenceStaticService(b):
+					add("StaticService", ns, string(b.Name))
+				}
+			}
+		}
+	}
+
+	ret := make([]event.ObjectKey, 0, len(deps))
+	for k := range deps {
+		ret = append(ret, k)
+	}
+
+	return ret
+}
+
+// getEndpointSlices4Service returns the EndpointSlices of a Service.
+func getEndpointSlices4Service(namespace, name string) []*discoveryv1.EndpointSlice {
+	ret := []*discoveryv1.EndpointSlice{}
+	for _, esl := range store.EndpointSlices.GetAll() {
+		if esl.GetNamespace() != namespace {
+			continue
+		}
+		if esl.GetLabels()[discoveryv1.LabelServiceName] == name {
+			ret = append(ret, esl)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,314 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+func TestRenderCache(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "incremental render",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				for i := 0; i < 2; i++ {
+					c.gws = append(c.gws, generateGateway(i))
+					c.rs = append(c.rs, generateUDPRoute(i))
+					c.svcs = append(c.svcs, generateService(i))
+					c.esls = append(c.esls, generateEndpointSlice(i))
+				}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				config.DataplaneMode = config.DataplaneModeManaged
+				defer func() {
+					config.DataplaneMode = config.NewDataplaneMode(opdefault.DefaultDataplaneMode)
+				}()
+
+				r.licmgr = licensemgr.NewStubManager("", log)
+				ch := make(chan event.Event, 10)
+				r.SetOperatorChannel(event.NewEventChannel(ch))
+
+				render := func(gen int, changes ...event.ObjectKey) *event.EventUpdate {
+					e := event.NewEventRender(gen)
+					e.Changes = changes
+					r.Render(e)
+					u, ok := (<-ch).(*event.EventUpdate)
+					assert.True(t, ok, "update event")
+					return u
+				}
+				key := func(kind, namespace, name string) event.ObjectKey {
+					return event.ObjectKey{Kind: kind, NamespacedName: types.NamespacedName{
+						Namespace: namespace, Name: name}}
+				}
+				configNames := func(u *event.EventUpdate) []string {
+					ret := []string{}
+					for _, c := range u.ConfigQueue {
+						ret = append(ret, c.Admin.Name)
+					}
+					return ret
+				}
+				allConfigs := []string{"testnamespace-0/gateway-0", "testnamespace-1/gateway-1"}
+
+				// an incremental render before the first full render renders everything
+				eslKey := key("EndpointSlice", "testnamespace-0", "testendpointslice-0")
+				u := render(1, eslKey)
+				assert.Equal(t, allConfigs, configNames(u), "config queue")
+				assert.Equal(t, 2, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.Equal(t, 2, u.UpsertQueue.Deployments.Len(), "deployments")
+
+				// the cache knows the dependencies
+				assert.ElementsMatch(t, []string{"testnamespace-0/gateway-0"},
+					r.cache.lookup(eslKey), "esl dependency")
+				assert.ElementsMatch(t, []string{"testnamespace-1/gateway-1"},
+					r.cache.lookup(key("UDPRoute", "testnamespace-1", "udproute-1")),
+					"route dependency")
+				assert.ElementsMatch(t, []string{"testnamespace-1/gateway-1"},
+					r.cache.lookup(key("Service", "testnamespace-1", "testservice-1")),
+					"backend dependency")
+
+				// an EndpointSlice change re-renders only the Gateway of the backend
+				u = render(2, eslKey)
+				assert.Equal(t, allConfigs, configNames(u), "config queue")
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.NotNil(t, u.UpsertQueue.Gateways.Get(types.NamespacedName{
+					Namespace: "testnamespace-0", Name: "gateway-0"}), "gateway-0 rendered")
+				assert.Equal(t, 1, u.UpsertQueue.Deployments.Len(), "deployments")
+
+				// the Dataplane status still lists all Gateways
+				dp := u.UpsertQueue.Dataplanes.Get(types.NamespacedName{
+					Name: testutils.TestDataplane.GetName()})
+				assert.NotNil(t, dp, "dataplane status")
+
+				// a change that affects no Gateway renders none
+				u = render(3, key("EndpointSlice", "testnamespace-2", "dummy"))
+				assert.Equal(t, allConfigs, configNames(u), "config queue")
+				assert.Equal(t, 0, u.UpsertQueue.Gateways.Len(), "gateways")
+
+				// a route moving to another Gateway re-renders both the old and the new parent
+				ro := store.UDPRoutes.GetObject(types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "udproute-1"}).DeepCopy()
+				ro.Spec.ParentRefs[0].Namespace = (*gwapiv1.Namespace)(&[]string{"testnamespace-0"}[0])
+				ro.Spec.ParentRefs[0].Name = gwapiv1.ObjectName("gateway-0")
+				store.UDPRoutes.Upsert(ro)
+				u = render(4, key("UDPRoute", "testnamespace-1", "udproute-1"))
+				assert.Equal(t, allConfigs, configNames(u), "config queue")
+				assert.Equal(t, 2, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.ElementsMatch(t, []string{"testnamespace-0/gateway-0"},
+					r.cache.lookup(key("UDPRoute", "testnamespace-1", "udproute-1")),
+					"route dependency")
+
+				// a change of an unknown kind triggers a full render
+				u = render(5, key("Node", "", "testnode"))
+				assert.Equal(t, allConfigs, configNames(u), "config queue")
+				assert.Equal(t, 2, u.UpsertQueue.Gateways.Len(), "gateways")
+
+				// a removed Gateway is removed from the config queue
+				store.Gateways.Remove(types.NamespacedName{Namespace: "testnamespace-1",
+					Name: "gateway-1"})
+				u = render(6, key("Gateway", "testnamespace-1", "gateway-1"))
+				assert.Equal(t, []string{"testnamespace-0/gateway-0"}, configNames(u),
+					"config queue")
+				assert.Equal(t, 0, u.UpsertQueue.Gateways.Len(), "gateways")
+
+				// an empty change set triggers a full render
+				u = render(7)
+				assert.Equal(t, []string{"testnamespace-0/gateway-0"}, configNames(u),
+					"config queue")
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+			},
+		},
+		{
+			name: "new EndpointSlice",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				for i := 0; i < 2; i++ {
+					c.gws = append(c.gws, generateGateway(i))
+					c.rs = append(c.rs, generateUDPRoute(i))
+					c.svcs = append(c.svcs, generateService(i))
+				}
+				// only the first Service has an EndpointSlice
+				c.esls = append(c.esls, generateEndpointSlice(0))
+			},
+			tester: func(t *testing.T, r *renderer) {
+				config.DataplaneMode = config.DataplaneModeManaged
+				defer func() {
+					config.DataplaneMode = config.NewDataplaneMode(opdefault.DefaultDataplaneMode)
+				}()
+
+				r.licmgr = licensemgr.NewStubManager("", log)
+				ch := make(chan event.Event, 10)
+				r.SetOperatorChannel(event.NewEventChannel(ch))
+
+				render := func(gen int, changes ...event.ObjectKey) *event.EventUpdate {
+					e := event.NewEventRender(gen)
+					e.Changes = changes
+					r.Render(e)
+					u, ok := (<-ch).(*event.EventUpdate)
+					assert.True(t, ok, "update event")
+					return u
+				}
+				endpoints := func(u *event.EventUpdate, gw string) []string {
+					for _, c := range u.ConfigQueue {
+						if c.Admin.Name == gw && len(c.Clusters) == 1 {
+							return c.Clusters[0].Endpoints
+						}
+					}
+					return nil
+				}
+
+				u := render(1)
+				assert.Len(t, u.ConfigQueue, 2, "config queue")
+				assert.Equal(t, 2, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.NotContains(t, endpoints(u, "testnamespace-1/gateway-1"), "1.2.3.4",
+					"no endpoints")
+
+				// an EndpointSlice created after the render re-renders the Gateway of the
+				// Service it belongs to
+				esl := generateEndpointSlice(1)
+				esl.SetName("testservice-1-abcde")
+				store.EndpointSlices.Upsert(&esl)
+				eslKey := event.ObjectKey{Kind: "EndpointSlice", NamespacedName: types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "testservice-1-abcde"}}
+				u = render(2, eslKey)
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.NotNil(t, u.UpsertQueue.Gateways.Get(types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "gateway-1"}), "gateway-1 rendered")
+				assert.Contains(t, endpoints(u, "testnamespace-1/gateway-1"), "1.2.3.4", "endpoints")
+
+				// the new slice is now a recorded dependency, so it is found after deletion
+				assert.ElementsMatch(t, []string{"testnamespace-1/gateway-1"}, r.cache.lookup(eslKey),
+					"esl dependency")
+				store.EndpointSlices.Remove(eslKey.NamespacedName)
+				u = render(3, eslKey)
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.NotContains(t, endpoints(u, "testnamespace-1/gateway-1"), "1.2.3.4",
+					"no endpoints")
+			},
+		},
+		{
+			name: "per-gateway dataplane",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			prep: func(c *renderTestConfig) {
+				dp := testutils.TestDataplane.DeepCopy()
+				dp.SetName("dataplane-1")
+				dp.Spec.Image = "testimage-2"
+				c.dps = []stnrgwv1.Dataplane{testutils.TestDataplane, *dp}
+				for i := 0; i < 2; i++ {
+					c.gws = append(c.gws, generateGateway(i))
+				}
+				c.gws[1].SetAnnotations(map[string]string{
+					opdefault.DataplaneAnnotationKey: "dataplane-1",
+				})
+			},
+			tester: func(t *testing.T, r *renderer) {
+				config.DataplaneMode = config.DataplaneModeManaged
+				defer func() {
+					config.DataplaneMode = config.NewDataplaneMode(opdefault.DefaultDataplaneMode)
+				}()
+
+				r.licmgr = licensemgr.NewStubManager("", log)
+				ch := make(chan event.Event, 10)
+				r.SetOperatorChannel(event.NewEventChannel(ch))
+
+				render := func(gen int, changes ...event.ObjectKey) *event.EventUpdate {
+					e := event.NewEventRender(gen)
+					e.Changes = changes
+					r.Render(e)
+					u, ok := (<-ch).(*event.EventUpdate)
+					assert.True(t, ok, "update event")
+					return u
+				}
+				image := func(u *event.EventUpdate, namespace, name string) string {
+					o := u.UpsertQueue.Deployments.Get(types.NamespacedName{
+						Namespace: namespace, Name: name})
+					if o == nil {
+						return ""
+					}
+					return o.(*appv1.Deployment).Spec.Template.Spec.Containers[0].Image
+				}
+				dpGateways := func(u *event.EventUpdate, name string) []string {
+					ret := []string{}
+					o := u.UpsertQueue.Dataplanes.Get(types.NamespacedName{Name: name})
+					if o == nil {
+						return ret
+					}
+					for _, s := range o.(*stnrgwv1.Dataplane).Status.Gateways {
+						ret = append(ret, s.Namespace+"/"+s.Name)
+					}
+					return ret
+				}
+				dpKey := func(name string) event.ObjectKey {
+					return event.ObjectKey{Kind: "Dataplane",
+						NamespacedName: types.NamespacedName{Name: name}}
+				}
+
+				// each Gateway uses its own Dataplane
+				u := render(1)
+				assert.Equal(t, "testimage-1", image(u, "testnamespace-0", "gateway-0"), "image")
+				assert.Equal(t, "testimage-2", image(u, "testnamespace-1", "gateway-1"), "image")
+				assert.Equal(t, []string{"testnamespace-0/gateway-0"},
+					dpGateways(u, testutils.TestDataplane.GetName()), "default dataplane gateways")
+				assert.Equal(t, []string{"testnamespace-1/gateway-1"},
+					dpGateways(u, "dataplane-1"), "dataplane-1 gateways")
+
+				// a Dataplane change re-renders only the Gateways that use it
+				u = render(2, dpKey("dataplane-1"))
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.Equal(t, "testimage-2", image(u, "testnamespace-1", "gateway-1"), "image")
+				assert.Equal(t, []string{"testnamespace-1/gateway-1"},
+					dpGateways(u, "dataplane-1"), "dataplane-1 gateways")
+
+				// the infrastructure parameters take precedence over the annotation: a
+				// Gateway with a missing Dataplane is invalidated
+				gw := store.Gateways.GetObject(types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "gateway-1"}).DeepCopy()
+				gw.Spec.Infrastructure = &gwapiv1.GatewayInfrastructure{
+					ParametersRef: &gwapiv1.LocalParametersReference{
+						Group: gwapiv1.Group(stnrgwv1.GroupVersion.Group),
+						Kind:  "Dataplane",
+						Name:  "dataplane-2",
+					},
+				}
+				store.Gateways.Upsert(gw)
+				u = render(3, event.ObjectKey{Kind: "Gateway", NamespacedName: types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "gateway-1"}})
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.Equal(t, 0, u.UpsertQueue.Deployments.Len(), "deployments")
+				assert.NotNil(t, u.DeleteQueue.Deployments.Get(types.NamespacedName{
+					Namespace: "testnamespace-1", Name: "gateway-1"}), "deployment deleted")
+				assert.Empty(t, dpGateways(u, "dataplane-1"), "dataplane-1 gateways")
+
+				// creating the Dataplane re-renders the Gateway
+				dp := testutils.TestDataplane.DeepCopy()
+				dp.SetName("dataplane-2")
+				dp.Spec.Image = "testimage-3"
+				store.Dataplanes.Upsert(dp)
+				u = render(4, dpKey("dataplane-2"))
+				assert.Equal(t, 1, u.UpsertQueue.Gateways.Len(), "gateways")
+				assert.Equal(t, "testimage-3", image(u, "testnamespace-1", "gateway-1"), "image")
+				assert.Equal(t, []string{"testnamespace-1/gateway-1"},
+					dpGateways(u, "dataplane-2"), "dataplane-2 gateways")
+			},
+		},
+	})
+}
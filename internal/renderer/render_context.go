@@ -1,6 +1,8 @@
 package renderer
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -9,17 +11,24 @@ import (
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
 )
 
 // RenderContext contains the GatewayClass and the GatewayConfig for the current rendering task,
 // plus additional metadata
 type RenderContext struct {
+	// ctx carries the trace context of the render
+	ctx    context.Context
 	update *event.EventUpdate
 	gc     *gwapiv1.GatewayClass
 	gwConf *stnrgwv1.GatewayConfig
 	dp     *stnrgwv1.Dataplane
 	gws    *store.GatewayStore
 	log    logr.Logger
+	// auth and authErr cache the auth config rendered from the GatewayConfig, see getAuth
+	auth    *stnrconfv1.AuthConfig
+	authErr error
 }
 
 func NewRenderContext(r *renderer, gc *gwapiv1.GatewayClass) *RenderContext {
@@ -29,8 +38,12 @@ func NewRenderContext(r *renderer, gc *gwapiv1.GatewayClass) *RenderContext {
 	}
 	update := event.NewEventUpdate(r.gen)
 	update.LicenseStatus = r.licmgr.Status()
+	if r.traceCtx != nil {
+		update.SetTraceContext(r.traceCtx)
+	}
 
 	return &RenderContext{
+		ctx:    r.traceCtx,
 		update: update,
 		gc:     gc,
 		gws:    store.NewGatewayStore(),
@@ -46,26 +59,46 @@ func (r *RenderContext) Merge(mergeable *RenderContext) {
 	upsertQueue1 := &r.update.UpsertQueue
 	upsertQueue2 := mergeable.update.UpsertQueue
 	store.Merge(upsertQueue1.GatewayClasses, upsertQueue2.GatewayClasses)
+	store.Merge(upsertQueue1.GatewayConfigs, upsertQueue2.GatewayConfigs)
+	store.Merge(upsertQueue1.Dataplanes, upsertQueue2.Dataplanes)
 	store.Merge(upsertQueue1.Gateways, upsertQueue2.Gateways)
 	store.Merge(upsertQueue1.UDPRoutes, upsertQueue2.UDPRoutes)
 	store.Merge(upsertQueue1.UDPRoutesV1A2, upsertQueue2.UDPRoutesV1A2)
+	store.Merge(upsertQueue1.TCPRoutes, upsertQueue2.TCPRoutes)
+	store.Merge(upsertQueue1.TLSRoutes, upsertQueue2.TLSRoutes)
 	store.Merge(upsertQueue1.Services, upsertQueue2.Services)
 	store.Merge(upsertQueue1.ConfigMaps, upsertQueue2.ConfigMaps)
 	store.Merge(upsertQueue1.Deployments, upsertQueue2.Deployments)
 	store.Merge(upsertQueue1.DaemonSets, upsertQueue2.DaemonSets)
+	store.Merge(upsertQueue1.PodDisruptionBudgets, upsertQueue2.PodDisruptionBudgets)
+	store.Merge(upsertQueue1.HorizontalPodAutoscalers, upsertQueue2.HorizontalPodAutoscalers)
 
 	// merge delete queues
 	deleteQueue1 := &r.update.DeleteQueue
 	deleteQueue2 := mergeable.update.DeleteQueue
 	store.Merge(deleteQueue1.GatewayClasses, deleteQueue2.GatewayClasses)
+	store.Merge(deleteQueue1.GatewayConfigs, deleteQueue2.GatewayConfigs)
+	store.Merge(deleteQueue1.Dataplanes, deleteQueue2.Dataplanes)
 	store.Merge(deleteQueue1.Gateways, deleteQueue2.Gateways)
 	store.Merge(deleteQueue1.UDPRoutes, deleteQueue2.UDPRoutes)
 	store.Merge(deleteQueue1.UDPRoutesV1A2, deleteQueue2.UDPRoutesV1A2)
+	store.Merge(deleteQueue1.TCPRoutes, deleteQueue2.TCPRoutes)
+	store.Merge(deleteQueue1.TLSRoutes, deleteQueue2.TLSRoutes)
 	store.Merge(deleteQueue1.Services, deleteQueue2.Services)
 	store.Merge(deleteQueue1.ConfigMaps, deleteQueue2.ConfigMaps)
 	store.Merge(deleteQueue1.Deployments, deleteQueue2.Deployments)
 	store.Merge(deleteQueue1.DaemonSets, deleteQueue2.DaemonSets)
+	store.Merge(deleteQueue1.PodDisruptionBudgets, deleteQueue2.PodDisruptionBudgets)
+	store.Merge(deleteQueue1.HorizontalPodAutoscalers, deleteQueue2.HorizontalPodAutoscalers)
 
 	// merge the CDS server's config-queue
 	r.update.ConfigQueue = append(r.update.ConfigQueue, mergeable.update.ConfigQueue...)
 }
+
+// traceContext returns the trace context of the render.
+func (c *RenderContext) traceContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
@@ -1,12 +1,18 @@
 package renderer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
@@ -14,7 +20,9 @@ import (
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
@@ -23,6 +31,21 @@ func (r *renderer) Render(e *event.EventRender) {
 	r.gen = e.Generation
 	r.log.Info("Rendering configuration", "generation", r.gen, "event", e.String())
 
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Start(e.GetTraceContext(ctx), "Render", trace.WithAttributes(
+		attribute.Int("generation", r.gen),
+		attribute.String("dataplane-mode", config.DataplaneMode.String()),
+		attribute.Int("changes", len(e.Changes)),
+	))
+	r.traceCtx = ctx
+	defer func() {
+		r.traceCtx = nil
+		span.End()
+	}()
+
 	switch config.DataplaneMode {
 	case config.DataplaneModeLegacy:
 		r.renderGatewayClass(e)
@@ -32,6 +55,8 @@ func (r *renderer) Render(e *event.EventRender) {
 		r.log.Info(`Unknown dataplane mode (must be either "managed" or "legacy")`)
 		return
 	}
+
+	pruneGatewayMetrics()
 }
 
 // Finalize performs the finalization sequence:
@@ -90,9 +115,12 @@ func (r *renderer) renderGatewayClass(e *event.EventRender) {
 			r.log.Error(err, "Error obtaining gateway-config",
 				"gateway-class", gc.GetName())
 			r.invalidateGatewayClass(c, err)
+			r.recordGatewayErrors(c, err)
 			continue
 		}
 
+		c.update.UpsertQueue.GatewayConfigs.Upsert(r.setGatewayConfigStatus(c))
+
 		r.log.V(1).Info("Finding gateways", "gateway-class", store.GetObjectKey(gc))
 		gws := r.getGateways4Class(c)
 		c.gws.ResetGateways(gws)
@@ -103,6 +131,7 @@ func (r *renderer) renderGatewayClass(e *event.EventRender) {
 			// object statuses to signal the error
 			r.log.Error(err, "Rendering error", "gateway-class", store.GetObjectKey(gc))
 			r.invalidateGatewayClass(c, err)
+			r.recordGatewayErrors(c, err)
 		}
 
 		setGatewayClassStatusAccepted(gc, nil)
@@ -123,9 +152,24 @@ func (r *renderer) renderManagedGateways(e *event.EventRender) {
 	gcs := r.getGatewayClasses()
 	if len(gcs) == 0 {
 		r.log.Info("No gateway-class objects found", "event", e.String())
+		r.cache.reset()
 		return
 	}
 
+	// the Gateways to re-render, nil means all
+	affected := r.getAffectedGateways(e.Changes)
+	if affected == nil {
+		r.log.V(1).Info("Full render")
+		r.cache.reset()
+	} else {
+		r.log.V(1).Info("Incremental render", "changes", len(e.Changes), "affected-gateways",
+			len(affected))
+	}
+	seen := map[string]bool{}
+
+	// the Gateways using each Dataplane, for setting the Dataplane status
+	dpGateways := map[string][]*gwapiv1.Gateway{}
+
 	for _, gc := range gcs {
 		r.log.Info("Rendering configuration", "gateway-class", store.GetObjectKey(gc))
 
@@ -136,26 +180,27 @@ func (r *renderer) renderManagedGateways(e *event.EventRender) {
 		if err != nil {
 			r.log.Error(err, "Error obtaining gateway-config", "gateway-class", gc.GetName())
 			r.invalidateGatewayClass(gcCtx, err)
+			r.recordGatewayErrors(gcCtx, err)
 			pipelineCtx.Merge(gcCtx)
 			continue
 		}
 		gcCtx.gwConf = gwConf
 
-		// don't even start rendering if Dataplane is not available
-		dp, err := getDataplane(gcCtx)
-		if err != nil {
-			r.log.Error(err, "Error obtaining Dataplane",
-				"gateway-class", store.GetObjectKey(gc),
-				"gateway-config", store.GetObjectKey(gwConf),
-			)
-			r.invalidateGatewayClass(gcCtx, err)
-			pipelineCtx.Merge(gcCtx)
-			continue
-		}
-		gcCtx.dp = dp
+		gcCtx.update.UpsertQueue.GatewayConfigs.Upsert(r.setGatewayConfigStatus(gcCtx))
 
 		for _, gw := range r.getGateways4Class(gcCtx) {
 			gw := gw
+			key := store.GetObjectKey(gw)
+			seen[key] = true
+
+			// the Gateway is not affected by the changes: reuse the last render
+			if entry, ok := r.cache.gateways[key]; ok && affected != nil && !affected[key] {
+				r.log.V(2).Info("Skipping unaffected gateway", "gateway", key)
+				if entry.dataplane != "" {
+					dpGateways[entry.dataplane] = append(dpGateways[entry.dataplane], gw)
+				}
+				continue
+			}
 
 			r.log.V(1).Info("Rendering for gateway",
 				"gateway-class", store.GetObjectKey(gc),
@@ -164,19 +209,48 @@ func (r *renderer) renderManagedGateways(e *event.EventRender) {
 
 			gwCtx := NewRenderContext(r, gc)
 			gwCtx.gwConf = gcCtx.gwConf
-			gwCtx.dp = gcCtx.dp
+			gwCtx.auth, gwCtx.authErr = gcCtx.auth, gcCtx.authErr
 			gwCtx.gws.ResetGateways([]*gwapiv1.Gateway{gw})
 
+			// don't even start rendering if the Dataplane of the Gateway is not available
+			dp, err := getDataplane(gwCtx)
+			if err != nil {
+				r.log.Error(err, "Error obtaining Dataplane",
+					"gateway-class", store.GetObjectKey(gc),
+					"gateway-config", store.GetObjectKey(gwConf),
+					"gateway", store.GetObjectKey(gw),
+				)
+				r.invalidateGateways(gwCtx, err)
+				r.recordError(gwConf, err)
+				r.recordGatewayErrors(gwCtx, err)
+				gcCtx.Merge(gwCtx)
+				r.cache.set(key, &gatewayCacheEntry{}, r.getGatewayDependencies(gwConf, gw))
+				continue
+			}
+			gwCtx.dp = dp
+
 			// render for this gateway
 			if err := r.renderForGateways(gwCtx); err != nil {
 				r.log.Error(err, "Rendering", "gateway-class", store.GetObjectKey(gc),
 					"gateway", store.GetObjectKey(gw),
 				)
 				r.invalidateGateways(gwCtx, err)
+				r.recordGatewayErrors(gwCtx, err)
 				gcCtx.Merge(gwCtx)
+				r.cache.set(key, &gatewayCacheEntry{}, r.getGatewayDependencies(gwConf, gw))
 				continue
 			}
 			gcCtx.Merge(gwCtx)
+
+			entry := &gatewayCacheEntry{}
+			if len(gwCtx.update.ConfigQueue) > 0 {
+				entry.config = gwCtx.update.ConfigQueue[0]
+			}
+			if !isManagedDataplaneDisabled(gw) {
+				dpGateways[dp.GetName()] = append(dpGateways[dp.GetName()], gw)
+				entry.dataplane = dp.GetName()
+			}
+			r.cache.set(key, entry, r.getGatewayDependencies(gwConf, gw))
 		}
 
 		setGatewayClassStatusAccepted(gc, nil)
@@ -185,6 +259,18 @@ func (r *renderer) renderManagedGateways(e *event.EventRender) {
 		pipelineCtx.Merge(gcCtx)
 	}
 
+	// the CDS server expects the configs for all Gateways: take them from the cache
+	r.cache.prune(seen)
+	r.cache.valid = true
+	pipelineCtx.update.ConfigQueue = r.cache.configs()
+
+	// set the status for all Dataplanes, including the ones not used by any Gateway
+	for _, dp := range store.Dataplanes.GetAll() {
+		dp = dp.DeepCopy()
+		setDataplaneStatus(dp, dpGateways[dp.GetName()])
+		pipelineCtx.update.UpsertQueue.Dataplanes.Upsert(dp)
+	}
+
 	u := pipelineCtx.update.DeepCopy()
 
 	// updates must be acknowledged to the operator by the updater
@@ -197,6 +283,8 @@ func (r *renderer) renderManagedGateways(e *event.EventRender) {
 func (r *renderer) finalizeManagedGateways(e *event.EventFinalize) {
 	r.log.Info("Stating finalization", "mode", "managed")
 
+	r.cache.reset()
+
 	pipelineCtx := NewRenderContext(r, nil)
 
 	r.log.V(1).Info("Obtaining gateway-class objects")
@@ -226,7 +314,17 @@ func (r *renderer) finalizeManagedGateways(e *event.EventFinalize) {
 }
 
 // renderForGateways renders a configuration for a set of Gateways (c.gws)
-func (r *renderer) renderForGateways(c *RenderContext) error {
+func (r *renderer) renderForGateways(c *RenderContext) (err error) {
+	gws := []string{}
+	for _, gw := range c.gws.GetAll() {
+		gws = append(gws, store.GetObjectKey(gw))
+	}
+	_, span := tracing.Start(c.traceContext(), "renderForGateways", trace.WithAttributes(
+		attribute.String("gateway-class", c.gc.GetName()),
+		attribute.StringSlice("gateways", gws),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	log := r.log
 	gc := c.gc
 
@@ -244,13 +342,16 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 	conf.Admin = *admin
 
 	log.V(1).Info("Rendering auth config")
-	auth, err := r.renderAuth(c)
+	auth, err := r.getAuth(c)
 	if err != nil {
 		return err
 	}
+	// the auth config is shared by the Gateways of the class: copy the credentials
 	conf.Auth = *auth
+	conf.Auth.Credentials = maps.Clone(auth.Credentials)
 
 	conf.Listeners = []stnrconfv1.ListenerConfig{}
+	stats := map[string]*gatewayStats{}
 	for _, gw := range c.gws.GetAll() {
 		log.V(2).Info("Considering", "gateway", store.GetObjectKey(gw), "listener-num",
 			len(gw.Spec.Listeners))
@@ -262,6 +363,8 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 		gwCtx.gws.ResetGateways([]*gwapiv1.Gateway{gw})
 
 		initGatewayStatus(gw, nil)
+		s := newGatewayStats()
+		stats[store.GetObjectKey(gw)] = s
 
 		log.V(3).Info("Obtaining public address", "gateway", store.GetObjectKey(gw))
 		pubGwAddrs, err := r.getPublicAddr(gw)
@@ -290,12 +393,16 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 
 			log.V(3).Info("Obtaining routes", "gateway", store.GetObjectKey(gw), "listener",
 				l.Name)
-			rs := r.getUDPRoutes4Listener(gw, &l)
+			rs := r.getRoutes4Listener(gw, &l)
 
 			if isListenerConflicted(&l, udpPorts, tcpPorts) {
 				log.Info("Listener protocol/port conflict", "gateway", store.GetObjectKey(gw),
 					"listener", l.Name)
-				setListenerStatus(gw, &l, NewNonCriticalError(PortUnavailable), true, len(rs))
+				err := NewNonCriticalError(PortUnavailable)
+				setListenerStatus(gw, &l, err, true, len(rs))
+				r.recordError(gw, fmt.Errorf("listener %q: %w", l.Name, err))
+				recordGatewayMetricsError(c, gw, err)
+				s.ListenersConflicted++
 				continue
 			}
 
@@ -309,23 +416,34 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 					store.GetObjectKey(gw), "listener", l.Name, "error", err.Error())
 
 				setListenerStatus(gw, &l, err, false, 0)
+				r.recordError(gw, fmt.Errorf("listener %q: %w", l.Name, err))
+				recordGatewayMetricsError(c, gw, err)
+				s.ListenersInvalid++
 				continue
 			}
 
 			conf.Listeners = append(conf.Listeners, *lc)
 			setListenerStatus(gw, &l, nil, false, len(rs))
+			s.ListenersRendered++
+			for _, ro := range rs {
+				s.routes[store.GetObjectKey(ro)] = true
+			}
 		}
 
 		setGatewayStatusProgrammed(gw, nil, pubGwAddrs)
+		if config.DataplaneMode == config.DataplaneModeManaged && r.syncStatus != nil {
+			s, ok := r.syncStatus.GetSyncStatus(store.GetObjectKey(gw))
+			setGatewayStatusDataplaneConfigSynced(gw, s, ok)
+		}
 		gw = pruneGatewayStatusConds(gw)
 
 		// schedule for update
 		c.update.UpsertQueue.Gateways.Upsert(gw.DeepCopy())
 	}
 
-	log.V(1).Info("Processing UDPRoutes")
+	log.V(1).Info("Processing routes")
 	conf.Clusters = []stnrconfv1.ClusterConfig{}
-	for _, ro := range r.allUDPRoutes() {
+	for _, ro := range r.allRoutes() {
 		log.V(2).Info("Considering", "route", ro.GetName())
 
 		if !r.isRouteControlled(ro) {
@@ -346,6 +464,14 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 		}
 
 		rc, err := r.renderCluster(ro)
+		if renderRoute {
+			r.recordError(eventTargetRoute(ro), err)
+		}
+		for _, gw := range c.gws.GetAll() {
+			if stats[store.GetObjectKey(gw)].routes[store.GetObjectKey(ro)] {
+				recordGatewayMetricsError(c, gw, err)
+			}
+		}
 		criticalErr := err
 		if err != nil {
 			if IsNonCritical(err) {
@@ -373,18 +499,18 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 			setRouteConditionStatus(ro, &p, config.ControllerName, parentExists, parentAccept, err)
 		}
 
-		// schedule for update: note that we may process the same UDPRoute several times,
+		// schedule for update: note that we may process the same route several times,
 		// in the context of different Gateways: Upsert makes sure the last render will be
 		// updated
-		if isRouteV1A2(ro) {
-			c.update.UpsertQueue.UDPRoutesV1A2.Upsert(statusTargetV1A2UDPRoute(ro))
-		} else {
-			c.update.UpsertQueue.UDPRoutes.Upsert(ro.DeepCopy())
-		}
+		upsertRouteStatus(c, ro)
 	}
 	r.invalidateMaskedRoutes(c)
 	r.log.Info("Update queue ready", "queue", c.update.String())
 
+	for _, gw := range c.gws.GetAll() {
+		setGatewayMetrics(c, gw, stats[store.GetObjectKey(gw)], conf.Clusters)
+	}
+
 	if config.DataplaneMode == config.DataplaneModeManaged {
 		// config name is the name of the gateway
 		gw := c.gws.GetFirst()
@@ -397,14 +523,46 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 			// create deployment
 			dp, err := r.generateDataplane(c)
 			if err != nil {
+				if c.dp != nil {
+					r.recordError(c.dp, err)
+				}
+				return err
+			}
+
+			// create pod disruption budget
+			pdb, err := r.generatePodDisruptionBudget(c)
+			if err != nil {
+				if c.dp != nil {
+					r.recordError(c.dp, err)
+				}
+				return err
+			}
+
+			// create autoscaler
+			hpa, err := r.generateHorizontalPodAutoscaler(c)
+			if err != nil {
+				if c.dp != nil {
+					r.recordError(c.dp, err)
+				}
 				return err
 			}
+
 			if isManagedDataplaneDisabled(gw) {
 				if dep, ok := dp.(*appv1.Deployment); ok {
 					c.update.DeleteQueue.Deployments.Upsert(dep.DeepCopy())
 				} else if ds, ok := dp.(*appv1.DaemonSet); ok {
 					c.update.DeleteQueue.DaemonSets.Upsert(ds.DeepCopy())
 				}
+				c.update.DeleteQueue.PodDisruptionBudgets.Upsert(&policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.GetNamespace(),
+						Name:      gw.GetName(),
+					}})
+				c.update.DeleteQueue.HorizontalPodAutoscalers.Upsert(&autoscalingv2.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: gw.GetNamespace(),
+						Name:      gw.GetName(),
+					}})
 				log.V(1).Info("Removing STUNner dataplane resource for Gateway",
 					"gateway", store.DumpObject(gw),
 					"disable-dataplane-annotation", true)
@@ -428,6 +586,32 @@ func (r *renderer) renderForGateways(c *RenderContext) error {
 				}
 				log.Info("STUNner dataplane resource rendering ready",
 					"generation", r.gen, "resource", store.DumpObject(dp))
+
+				if pdb != nil {
+					c.update.UpsertQueue.PodDisruptionBudgets.Upsert(pdb)
+					log.Info("STUNner dataplane PodDisruptionBudget rendering ready",
+						"generation", r.gen, "resource", store.DumpObject(pdb))
+				} else if store.PodDisruptionBudgets.GetObject(store.GetNamespacedName(gw)) != nil {
+					// delete lingering PodDisruptionBudgets
+					c.update.DeleteQueue.PodDisruptionBudgets.Upsert(&policyv1.PodDisruptionBudget{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gw.GetNamespace(),
+							Name:      gw.GetName(),
+						}})
+				}
+
+				if hpa != nil {
+					c.update.UpsertQueue.HorizontalPodAutoscalers.Upsert(hpa)
+					log.Info("STUNner dataplane HorizontalPodAutoscaler rendering ready",
+						"generation", r.gen, "resource", store.DumpObject(hpa))
+				} else if store.HorizontalPodAutoscalers.GetObject(store.GetNamespacedName(gw)) != nil {
+					// delete lingering HorizontalPodAutoscalers
+					c.update.DeleteQueue.HorizontalPodAutoscalers.Upsert(&autoscalingv2.HorizontalPodAutoscaler{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gw.GetNamespace(),
+							Name:      gw.GetName(),
+						}})
+				}
 			}
 		}
 	} else {
@@ -483,6 +667,15 @@ func (r *renderer) invalidateGatewayClass(c *RenderContext, reason error) {
 	r.invalidateGateways(c, reason)
 }
 
+// recordGatewayErrors emits a Kubernetes Event for a render error on all the Gateways of a render
+// context.
+func (r *renderer) recordGatewayErrors(c *RenderContext, err error) {
+	for _, gw := range c.gws.GetAll() {
+		r.recordError(gw, err)
+		recordGatewayMetricsError(c, gw, err)
+	}
+}
+
 // invalidateGateways invalidates a set of Gateways
 func (r *renderer) invalidateGateways(c *RenderContext, reason error) {
 	log := r.log
@@ -502,6 +695,8 @@ func (r *renderer) invalidateGateways(c *RenderContext, reason error) {
 
 		setGatewayStatusProgrammed(gw, reason, nil)
 		gw = pruneGatewayStatusConds(gw)
+		metrics.SetGatewayStats(gc.GetName(), store.GetNamespacedName(gw),
+			metrics.GatewayStats{ListenersInvalid: len(gw.Spec.Listeners)})
 
 		// schedule for update
 		c.update.UpsertQueue.Gateways.Upsert(gw.DeepCopy())
@@ -538,11 +733,31 @@ func (r *renderer) invalidateGateways(c *RenderContext, reason error) {
 			c.update.DeleteQueue.Deployments.Upsert(dp)
 			log.V(2).Info("Deleting dataplane Deployment", "generation", r.gen,
 				"deployment", store.DumpObject(dp))
+
+			pdb := &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      gw.GetName(),
+					Namespace: gw.GetNamespace(),
+				},
+			}
+			c.update.DeleteQueue.PodDisruptionBudgets.Upsert(pdb)
+			log.V(2).Info("Deleting dataplane PodDisruptionBudget", "generation", r.gen,
+				"pod-disruption-budget", store.DumpObject(pdb))
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      gw.GetName(),
+					Namespace: gw.GetNamespace(),
+				},
+			}
+			c.update.DeleteQueue.HorizontalPodAutoscalers.Upsert(hpa)
+			log.V(2).Info("Deleting dataplane HorizontalPodAutoscaler", "generation", r.gen,
+				"horizontal-pod-autoscaler", store.DumpObject(hpa))
 		}
 	}
 
-	log.V(1).Info("Processing UDPRoutes")
-	for _, ro := range r.allUDPRoutes() {
+	log.V(1).Info("Processing routes")
+	for _, ro := range r.allRoutes() {
 		log.V(2).Info("Considering", "route", ro.GetName())
 
 		initRouteStatus(ro)
@@ -563,11 +778,7 @@ func (r *renderer) invalidateGateways(c *RenderContext, reason error) {
 			setRouteConditionStatus(ro, &p, config.ControllerName, parentExists, parendAccepted, err)
 		}
 
-		if isRouteV1A2(ro) {
-			c.update.UpsertQueue.UDPRoutesV1A2.Upsert(statusTargetV1A2UDPRoute(ro))
-		} else {
-			c.update.UpsertQueue.UDPRoutes.Upsert(ro.DeepCopy())
-		}
+		upsertRouteStatus(c, ro)
 	}
 }
 
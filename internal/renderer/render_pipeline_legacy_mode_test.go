@@ -17,6 +17,7 @@ import (
 	// "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
 
@@ -897,6 +898,12 @@ func TestRenderPipelineLegacyMode(t *testing.T) {
 					&testutils.TestNsName
 				c.rs = []stnrgwv1.UDPRoute{*dummyUdp, testutils.TestUDPRoute}
 
+				// allow the cross-namespace backend reference
+				rg := testutils.TestReferenceGrant.DeepCopy()
+				rg.SetNamespace(string(testutils.TestNsName))
+				rg.Spec.From[0].Namespace = dummyNs
+				c.rgs = []gwapiv1b1.ReferenceGrant{*rg}
+
 				s := testutils.TestSvc.DeepCopy()
 				s.Spec.ClusterIP = "4.3.2.1"
 				// update owner ref so that we accept the public IP
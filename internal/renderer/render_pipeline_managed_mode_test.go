@@ -1,25 +1,38 @@
 package renderer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	// "k8s.io/apimachinery/pkg/types"
 	// "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
@@ -2072,3 +2085,587 @@ func TestRenderPipelineManagedMode(t *testing.T) {
 		},
 	})
 }
+
+// testRecorder collects the events emitted by the renderer.
+type testRecorder struct {
+	events []string
+}
+
+func (r *testRecorder) Warning(o client.Object, reason, _, message string) {
+	r.events = append(r.events, fmt.Sprintf("%s %s %s: %s", store.GetObjectKey(o), "Warning",
+		reason, message))
+}
+
+func (r *testRecorder) Normal(o client.Object, reason, _, message string) {
+	r.events = append(r.events, fmt.Sprintf("%s %s %s: %s", store.GetObjectKey(o), "Normal",
+		reason, message))
+}
+
+func TestRenderPipelineManagedModeEvents(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "missing Dataplane",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				rec := &testRecorder{}
+				r.recorder = rec
+				renderManaged(t, r)
+
+				msg := NewCriticalError(InvalidDataplane).Error()
+				assert.ElementsMatch(t, []string{
+					"testnamespace/gatewayconfig-ok Warning RenderFailed: " + msg,
+					"testnamespace/gateway-1 Warning RenderFailed: " + msg,
+				}, rec.events, "events")
+			},
+		},
+		{
+			name: "backend not found",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				ro := testutils.TestUDPRoute.DeepCopy()
+				ro.Spec.Rules[0].BackendRefs[0].Name = "dummy"
+				c.rs = []stnrgwv1.UDPRoute{*ro}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				rec := &testRecorder{}
+				r.recorder = rec
+				renderManaged(t, r)
+
+				found := false
+				for _, e := range rec.events {
+					if strings.HasPrefix(e, "testnamespace/udproute-ok Warning RenderWarning:") {
+						found = true
+					}
+					assert.NotContains(t, e, "RenderFailed", "no critical errors")
+				}
+				assert.True(t, found, "route event: %v", rec.events)
+			},
+		},
+	})
+}
+
+// testSyncStatus is a fixed dataplane sync status.
+type testSyncStatus map[string]config.SyncStatus
+
+func (s testSyncStatus) GetSyncStatus(id string) (config.SyncStatus, bool) {
+	st, ok := s[id]
+	return st, ok
+}
+
+func TestRenderPipelineManagedModeDataplaneConfigSynced(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "dataplane config sync status",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				render := func(s testSyncStatus) *metav1.Condition {
+					r.syncStatus = s
+					u := renderManaged(t, r)
+					o := u.UpsertQueue.Gateways.Get(store.GetNamespacedName(&testutils.TestGw))
+					if !assert.NotNil(t, o, "gateway status updated") {
+						return nil
+					}
+					return meta.FindStatusCondition(o.(*gwapiv1.Gateway).Status.Conditions,
+						opdefault.GatewayConditionDataplaneConfigSynced)
+				}
+
+				d := render(testSyncStatus{})
+				assert.NotNil(t, d, "condition found")
+				assert.Equal(t, metav1.ConditionUnknown, d.Status, "status")
+				assert.Equal(t, opdefault.GatewayReasonDataplaneConfigNoClients, d.Reason, "reason")
+
+				d = render(testSyncStatus{"testnamespace/gateway-1": {Version: 3, Clients: 3,
+					Synced: 1, OldestVersion: 1}})
+				assert.NotNil(t, d, "condition found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, opdefault.GatewayReasonDataplaneConfigPending, d.Reason, "reason")
+				assert.Equal(t, "1/3 dataplane pod(s) on the latest config version 3, "+
+					"oldest version in use: 1", d.Message, "message")
+
+				d = render(testSyncStatus{"testnamespace/gateway-1": {Version: 3, Clients: 3,
+					Synced: 3, OldestVersion: 3}})
+				assert.NotNil(t, d, "condition found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+				assert.Equal(t, opdefault.GatewayReasonDataplaneConfigSynced, d.Reason, "reason")
+				assert.Equal(t, "3 dataplane pod(s) on the latest config version 3", d.Message,
+					"message")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModeTracing(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "trace propagation",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				exp := tracetest.NewInMemoryExporter()
+				tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+				prev := otel.GetTracerProvider()
+				otel.SetTracerProvider(tp)
+				defer otel.SetTracerProvider(prev)
+
+				ctx, root := tp.Tracer("test").Start(context.Background(), "root")
+				e := event.NewEventRender(1)
+				e.SetTraceContext(ctx)
+				u := renderManagedEvent(t, r, e)
+				root.End()
+
+				traceID := root.SpanContext().TraceID()
+				assert.Equal(t, traceID, u.SpanContext().TraceID(), "update trace id")
+
+				spans := map[string]tracetest.SpanStub{}
+				for _, s := range exp.GetSpans() {
+					assert.Equal(t, traceID, s.SpanContext.TraceID(), "trace id")
+					spans[s.Name] = s
+				}
+				assert.Contains(t, spans, "Render", "render span")
+				assert.Contains(t, spans, "renderForGateways", "renderForGateways span")
+				assert.Equal(t, root.SpanContext().SpanID(), spans["Render"].Parent.SpanID(),
+					"render span parent")
+				assert.Equal(t, spans["Render"].SpanContext.SpanID(),
+					spans["renderForGateways"].Parent.SpanID(), "renderForGateways span parent")
+				assert.Equal(t, spans["Render"].SpanContext.SpanID(), u.SpanContext().SpanID(),
+					"update parent")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModeGatewayMetrics(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "per-gateway metrics",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			esls: []discoveryv1.EndpointSlice{testutils.TestEndpointSlice},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				// a listener conflicting with the first one
+				gw := testutils.TestGw.DeepCopy()
+				l := gw.Spec.Listeners[0]
+				l.Name = "conflicting"
+				gw.Spec.Listeners = append(gw.Spec.Listeners, l)
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				config.EnableEndpointDiscovery = true
+				defer func() {
+					config.EnableEndpointDiscovery = opdefault.DefaultEnableEndpointDiscovery
+				}()
+
+				// reset the metrics left behind by other tests
+				metrics.PruneGatewayMetrics(func(types.NamespacedName) bool { return false })
+
+				renderManaged(t, r)
+
+				class, ns, name := testutils.TestGwClass.GetName(), "testnamespace", "gateway-1"
+				listeners := func(status string) float64 {
+					return promtestutil.ToFloat64(metrics.GatewayListeners.WithLabelValues(class, ns, name, status))
+				}
+				assert.Equal(t, 2.0, listeners("rendered"), "rendered listeners")
+				assert.Equal(t, 1.0, listeners("conflicted"), "conflicted listeners")
+				assert.Equal(t, 0.0, listeners("invalid"), "invalid listeners")
+				assert.Equal(t, 1.0, promtestutil.ToFloat64(metrics.GatewayAttachedRoutes.WithLabelValues(class, ns, name)),
+					"attached routes")
+				assert.Equal(t, 1.0, promtestutil.ToFloat64(metrics.GatewayClusters.WithLabelValues(class, ns, name)),
+					"clusters")
+				assert.Equal(t, 4.0, promtestutil.ToFloat64(metrics.GatewayEndpoints.WithLabelValues(class, ns, name)),
+					"endpoints")
+				assert.Equal(t, 1.0, promtestutil.ToFloat64(metrics.GatewayRenderErrorsTotal.WithLabelValues(class, ns, name,
+					"PortUnavailable")), "port conflict errors")
+
+				// the metrics of deleted Gateways are removed
+				store.Gateways.Flush()
+				renderManagedEvent(t, r, event.NewEventRender(2))
+				assert.Equal(t, 0, promtestutil.CollectAndCount(metrics.GatewayListeners), "listener metrics removed")
+				assert.Equal(t, 0, promtestutil.CollectAndCount(metrics.GatewayRenderErrorsTotal), "error metrics removed")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModePodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromInt32(1)
+	lingering := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "testnamespace", Name: "gateway-1",
+	}}
+
+	renderTester(t, []renderTestConfig{
+		{
+			name: "pod disruption budget generated",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := testutils.TestDataplane.DeepCopy()
+				dp.Spec.PodDisruptionBudget = &stnrgwv1.DataplanePodDisruptionBudget{
+					MinAvailable: &minAvailable,
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Equal(t, 0, u.DeleteQueue.PodDisruptionBudgets.Len(), "pdb delete queue")
+				pdbs := u.UpsertQueue.PodDisruptionBudgets.Objects()
+				assert.Len(t, pdbs, 1, "pdb upsert queue")
+				pdb, ok := pdbs[0].(*policyv1.PodDisruptionBudget)
+				assert.True(t, ok, "pdb cast")
+
+				assert.Equal(t, "gateway-1", pdb.GetName(), "name")
+				assert.Equal(t, "testnamespace", pdb.GetNamespace(), "namespace")
+				assert.Equal(t, opdefault.OwnedByLabelValue, pdb.GetLabels()[opdefault.OwnedByLabelKey],
+					"owned-by label")
+				assert.Equal(t, "testnamespace/gateway-1",
+					pdb.GetAnnotations()[opdefault.RelatedGatewayKey], "related-gateway annotation")
+
+				assert.Len(t, pdb.GetOwnerReferences(), 1, "ownerref num")
+				assert.Equal(t, "Gateway", pdb.GetOwnerReferences()[0].Kind, "ownerref kind")
+				assert.Equal(t, "gateway-1", pdb.GetOwnerReferences()[0].Name, "ownerref name")
+
+				assert.Equal(t, &minAvailable, pdb.Spec.MinAvailable, "min available")
+				assert.Nil(t, pdb.Spec.MaxUnavailable, "max unavailable")
+
+				// the selector matches the dataplane pods
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				assert.NoError(t, err, "label selector convert")
+				deps := u.UpsertQueue.Deployments.Objects()
+				assert.Len(t, deps, 1, "deployment num")
+				dep := asDeployment(deps[0])
+				assert.True(t, selector.Matches(labels.Set(dep.Spec.Template.GetLabels())),
+					"selector matches pods")
+			},
+		},
+		{
+			name: "lingering pod disruption budget removed",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			pdbs: []policyv1.PodDisruptionBudget{lingering},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Equal(t, 0, u.UpsertQueue.PodDisruptionBudgets.Len(), "pdb upsert queue")
+				pdbs := u.DeleteQueue.PodDisruptionBudgets.Objects()
+				assert.Len(t, pdbs, 1, "pdb delete queue")
+				assert.Equal(t, "testnamespace/gateway-1", store.GetObjectKey(pdbs[0]), "deleted pdb")
+			},
+		},
+		{
+			name: "no pod disruption budget",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Equal(t, 0, u.UpsertQueue.PodDisruptionBudgets.Len(), "pdb upsert queue")
+				assert.Equal(t, 0, u.DeleteQueue.PodDisruptionBudgets.Len(), "pdb delete queue")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModeHorizontalPodAutoscaler(t *testing.T) {
+	lingering := autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "testnamespace", Name: "gateway-1",
+	}}
+
+	renderTester(t, []renderTestConfig{
+		{
+			name: "autoscaler generated",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := testutils.TestDataplane.DeepCopy()
+				cpu, allocs := int32(70), int32(100)
+				dp.Spec.Autoscaling = &stnrgwv1.DataplaneAutoscaling{
+					MaxReplicas:                    5,
+					TargetCPUUtilizationPercentage: &cpu,
+					TargetActiveAllocations:        &allocs,
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Equal(t, 0, u.DeleteQueue.HorizontalPodAutoscalers.Len(), "hpa delete queue")
+				hpas := u.UpsertQueue.HorizontalPodAutoscalers.Objects()
+				assert.Len(t, hpas, 1, "hpa upsert queue")
+				hpa, ok := hpas[0].(*autoscalingv2.HorizontalPodAutoscaler)
+				assert.True(t, ok, "hpa cast")
+
+				assert.Equal(t, "testnamespace/gateway-1", store.GetObjectKey(hpa), "name")
+				assert.Equal(t, opdefault.OwnedByLabelValue, hpa.GetLabels()[opdefault.OwnedByLabelKey],
+					"owned-by label")
+				assert.Equal(t, "testnamespace/gateway-1",
+					hpa.GetAnnotations()[opdefault.RelatedGatewayKey], "related-gateway annotation")
+				assert.Len(t, hpa.GetOwnerReferences(), 1, "ownerref num")
+				assert.Equal(t, "Gateway", hpa.GetOwnerReferences()[0].Kind, "ownerref kind")
+
+				assert.Equal(t, autoscalingv2.CrossVersionObjectReference{
+					APIVersion: "apps/v1", Kind: "Deployment", Name: "gateway-1",
+				}, hpa.Spec.ScaleTargetRef, "scale target")
+				assert.NotNil(t, hpa.Spec.MinReplicas, "min replicas")
+				assert.Equal(t, int32(1), *hpa.Spec.MinReplicas, "min replicas default")
+				assert.Equal(t, int32(5), hpa.Spec.MaxReplicas, "max replicas")
+
+				assert.Len(t, hpa.Spec.Metrics, 2, "metrics")
+				m := hpa.Spec.Metrics[0]
+				assert.Equal(t, autoscalingv2.ResourceMetricSourceType, m.Type, "cpu metric type")
+				assert.Equal(t, corev1.ResourceCPU, m.Resource.Name, "cpu metric name")
+				assert.Equal(t, int32(70), *m.Resource.Target.AverageUtilization, "cpu target")
+				m = hpa.Spec.Metrics[1]
+				assert.Equal(t, autoscalingv2.PodsMetricSourceType, m.Type, "allocation metric type")
+				assert.Equal(t, "stunner_allocations_active", m.Pods.Metric.Name, "allocation metric name")
+				assert.Equal(t, int64(100), m.Pods.Target.AverageValue.Value(), "allocation target")
+
+				// replicas are left to the autoscaler
+				deps := u.UpsertQueue.Deployments.Objects()
+				assert.Len(t, deps, 1, "deployment num")
+				assert.Nil(t, asDeployment(deps[0]).Spec.Replicas, "replicas")
+			},
+		},
+		{
+			name: "autoscaler defaults to CPU target",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				dp := testutils.TestDataplane.DeepCopy()
+				minReplicas := int32(2)
+				dp.Spec.Autoscaling = &stnrgwv1.DataplaneAutoscaling{
+					MinReplicas: &minReplicas,
+					MaxReplicas: 4,
+				}
+				c.dps = []stnrgwv1.Dataplane{*dp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				hpas := u.UpsertQueue.HorizontalPodAutoscalers.Objects()
+				assert.Len(t, hpas, 1, "hpa upsert queue")
+				hpa, ok := hpas[0].(*autoscalingv2.HorizontalPodAutoscaler)
+				assert.True(t, ok, "hpa cast")
+				assert.Equal(t, int32(2), *hpa.Spec.MinReplicas, "min replicas")
+				assert.Len(t, hpa.Spec.Metrics, 1, "metrics")
+				assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name, "cpu metric name")
+				assert.Equal(t, opdefault.DefaultAutoscalingTargetCPUUtilization,
+					*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization, "cpu target")
+			},
+		},
+		{
+			name: "lingering autoscaler removed",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			hpas: []autoscalingv2.HorizontalPodAutoscaler{lingering},
+			prep: func(c *renderTestConfig) {},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Equal(t, 0, u.UpsertQueue.HorizontalPodAutoscalers.Len(), "hpa upsert queue")
+				hpas := u.DeleteQueue.HorizontalPodAutoscalers.Objects()
+				assert.Len(t, hpas, 1, "hpa delete queue")
+				assert.Equal(t, "testnamespace/gateway-1", store.GetObjectKey(hpas[0]), "deleted hpa")
+
+				// replicas are copied from the Dataplane
+				deps := u.UpsertQueue.Deployments.Objects()
+				assert.Len(t, deps, 1, "deployment num")
+				assert.NotNil(t, asDeployment(deps[0]).Spec.Replicas, "replicas")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModeTCPRoute(t *testing.T) {
+	tcpRoute := func(sectionName string) stnrgwv1.UDPRoute {
+		ro := testutils.TestUDPRoute.DeepCopy()
+		ro.SetName("tcproute-ok")
+		sn := gwapiv1.SectionName(sectionName)
+		ro.Spec.ParentRefs[0].SectionName = &sn
+		return *ro
+	}
+
+	routeAccepted := func(t *testing.T, u *event.EventUpdate) *metav1.Condition {
+		ros := u.UpsertQueue.TCPRoutes.Objects()
+		assert.Len(t, ros, 1, "tcproute status upsert queue")
+		ro, ok := ros[0].(*gwapiv1a2.TCPRoute)
+		assert.True(t, ok, "tcproute status target type")
+		assert.Equal(t, "testnamespace/tcproute-ok", store.GetObjectKey(ro), "tcproute name")
+		assert.Len(t, ro.Status.Parents, 1, "parent status len")
+		return meta.FindStatusCondition(ro.Status.Parents[0].Conditions,
+			string(gwapiv1.RouteConditionAccepted))
+	}
+
+	renderTester(t, []renderTestConfig{
+		{
+			name: "TCPRoute attached to TCP listener",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				c.rsTCP = []stnrgwv1.UDPRoute{tcpRoute("gateway-1-listener-tcp")}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Len(t, u.ConfigQueue, 1, "config num")
+				conf := u.ConfigQueue[0]
+
+				assert.Len(t, conf.Listeners, 2, "listener num")
+				assert.Equal(t, []string{"testnamespace/udproute-ok"}, conf.Listeners[0].Routes,
+					"udp listener routes")
+				assert.Equal(t, []string{"testnamespace/tcproute-ok"}, conf.Listeners[1].Routes,
+					"tcp listener routes")
+
+				assert.Len(t, conf.Clusters, 2, "cluster num")
+				names := []string{conf.Clusters[0].Name, conf.Clusters[1].Name}
+				assert.Contains(t, names, "testnamespace/tcproute-ok", "tcproute cluster")
+
+				assert.Equal(t, 1, u.UpsertQueue.UDPRoutes.Len(), "udproute status upsert queue")
+				d := routeAccepted(t, u)
+				assert.NotNil(t, d, "accepted found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.RouteReasonAccepted), d.Reason, "reason")
+			},
+		},
+		{
+			name: "TCPRoute rejected by UDP listener",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				c.rsTCP = []stnrgwv1.UDPRoute{tcpRoute("gateway-1-listener-udp")}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+				assert.Len(t, u.ConfigQueue, 1, "config num")
+				conf := u.ConfigQueue[0]
+
+				assert.Len(t, conf.Listeners, 2, "listener num")
+				assert.Equal(t, []string{"testnamespace/udproute-ok"}, conf.Listeners[0].Routes,
+					"udp listener routes")
+				assert.Len(t, conf.Listeners[1].Routes, 0, "tcp listener routes")
+
+				assert.Len(t, conf.Clusters, 1, "cluster num")
+				assert.Equal(t, "testnamespace/udproute-ok", conf.Clusters[0].Name, "cluster name")
+
+				d := routeAccepted(t, u)
+				assert.NotNil(t, d, "accepted found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.RouteReasonNotAllowedByListeners), d.Reason, "reason")
+			},
+		},
+	})
+}
+
+func TestRenderPipelineManagedModeAllowedRouteKinds(t *testing.T) {
+	renderTester(t, []renderTestConfig{
+		{
+			name: "UDPRoute rejected by listener restricted to other kinds",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			dps:  []stnrgwv1.Dataplane{testutils.TestDataplane},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.Spec.Listeners[0].AllowedRoutes = &gwapiv1.AllowedRoutes{
+					Kinds: []gwapiv1.RouteGroupKind{{Kind: "TCPRoute"}},
+				}
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				u := renderManaged(t, r)
+
+				assert.Len(t, u.ConfigQueue, 1, "config num")
+				conf := u.ConfigQueue[0]
+				assert.Len(t, conf.Listeners, 2, "listener num")
+				assert.Len(t, conf.Listeners[0].Routes, 0, "udp listener routes")
+				assert.Len(t, conf.Clusters, 0, "cluster num")
+
+				// listener status
+				gws := u.UpsertQueue.Gateways.Objects()
+				assert.Len(t, gws, 1, "gateway status upsert queue")
+				gw, ok := gws[0].(*gwapiv1.Gateway)
+				assert.True(t, ok, "gateway status target type")
+				assert.Len(t, gw.Status.Listeners, 2, "listener status num")
+
+				s := gw.Status.Listeners[0]
+				assert.Len(t, s.SupportedKinds, 0, "supported kinds")
+				assert.Equal(t, int32(0), s.AttachedRoutes, "attached routes")
+				d := meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, d, "resolvedrefs found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.ListenerReasonInvalidRouteKinds), d.Reason, "reason")
+
+				s = gw.Status.Listeners[1]
+				assert.Len(t, s.SupportedKinds, 3, "supported kinds")
+				d = meta.FindStatusCondition(s.Conditions, string(gwapiv1.ListenerConditionResolvedRefs))
+				assert.NotNil(t, d, "resolvedrefs found")
+				assert.Equal(t, metav1.ConditionTrue, d.Status, "status")
+
+				// route status
+				ros := u.UpsertQueue.UDPRoutes.Objects()
+				assert.Len(t, ros, 1, "udproute status upsert queue")
+				ro, ok := ros[0].(*stnrgwv1.UDPRoute)
+				assert.True(t, ok, "udproute status target type")
+				assert.Len(t, ro.Status.Parents, 1, "parent status len")
+				d = meta.FindStatusCondition(ro.Status.Parents[0].Conditions,
+					string(gwapiv1.RouteConditionAccepted))
+				assert.NotNil(t, d, "accepted found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.RouteReasonNotAllowedByListeners), d.Reason, "reason")
+			},
+		},
+	})
+}
@@ -7,8 +7,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -19,7 +22,11 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
@@ -37,6 +44,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme)) //nolint:staticcheck
 	utilruntime.Must(gwapiv1.AddToScheme(scheme))        //nolint:staticcheck
+	utilruntime.Must(gwapiv1b1.AddToScheme(scheme))      //nolint:staticcheck
 	utilruntime.Must(stnrgwv1.AddToScheme(scheme))       //nolint:staticcheck
 }
 
@@ -47,6 +55,8 @@ type renderTestConfig struct {
 	gws    []gwapiv1.Gateway
 	rs     []stnrgwv1.UDPRoute
 	rsV1A2 []stnrgwv1.UDPRoute // internal format is always ours, not v1a2
+	rsTCP  []stnrgwv1.UDPRoute // TCPRoutes, in our internal format
+	rsTLS  []stnrgwv1.UDPRoute // TLSRoutes, in our internal format
 	svcs   []corev1.Service
 	nodes  []corev1.Node
 	eps    []corev1.Endpoints
@@ -56,6 +66,11 @@ type renderTestConfig struct {
 	nss    []corev1.Namespace
 	ssvcs  []stnrgwv1.StaticService
 	dps    []stnrgwv1.Dataplane
+	deps   []appv1.Deployment
+	dss    []appv1.DaemonSet
+	pdbs   []policyv1.PodDisruptionBudget
+	hpas   []autoscalingv2.HorizontalPodAutoscaler
+	rgs    []gwapiv1b1.ReferenceGrant
 	prep   func(c *renderTestConfig)
 	tester func(t *testing.T, r *renderer)
 }
@@ -108,6 +123,16 @@ func renderTester(t *testing.T, testConf []renderTestConfig) {
 				store.UDPRoutesV1A2.Upsert(&c.rsV1A2[i])
 			}
 
+			store.TCPRoutes.Flush()
+			for i := range c.rsTCP {
+				store.TCPRoutes.Upsert(&c.rsTCP[i])
+			}
+
+			store.TLSRoutes.Flush()
+			for i := range c.rsTLS {
+				store.TLSRoutes.Upsert(&c.rsTLS[i])
+			}
+
 			store.Services.Flush()
 			for i := range c.svcs {
 				store.Services.Upsert(&c.svcs[i])
@@ -153,6 +178,31 @@ func renderTester(t *testing.T, testConf []renderTestConfig) {
 				store.Dataplanes.Upsert(&c.dps[i])
 			}
 
+			store.Deployments.Flush()
+			for i := range c.deps {
+				store.Deployments.Upsert(&c.deps[i])
+			}
+
+			store.DaemonSets.Flush()
+			for i := range c.dss {
+				store.DaemonSets.Upsert(&c.dss[i])
+			}
+
+			store.PodDisruptionBudgets.Flush()
+			for i := range c.pdbs {
+				store.PodDisruptionBudgets.Upsert(&c.pdbs[i])
+			}
+
+			store.HorizontalPodAutoscalers.Flush()
+			for i := range c.hpas {
+				store.HorizontalPodAutoscalers.Upsert(&c.hpas[i])
+			}
+
+			store.ReferenceGrants.Flush()
+			for i := range c.rgs {
+				store.ReferenceGrants.Upsert(&c.rgs[i])
+			}
+
 			log.V(1).Info("starting renderer thread")
 			ctx, cancel := context.WithCancel(context.Background())
 			err := r.Start(ctx)
@@ -164,3 +214,24 @@ func renderTester(t *testing.T, testConf []renderTestConfig) {
 		})
 	}
 }
+
+// renderManaged renders the store in managed dataplane mode and returns the resulting update.
+func renderManaged(t *testing.T, r *renderer) *event.EventUpdate {
+	return renderManagedEvent(t, r, event.NewEventRender(1))
+}
+
+// renderManagedEvent is renderManaged with a custom render event.
+func renderManagedEvent(t *testing.T, r *renderer, e *event.EventRender) *event.EventUpdate {
+	mode := config.DataplaneMode
+	config.DataplaneMode = config.DataplaneModeManaged
+	defer func() { config.DataplaneMode = mode }()
+
+	r.licmgr = licensemgr.NewStubManager("", log)
+	ch := make(chan event.Event, 10)
+	r.SetOperatorChannel(event.NewEventChannel(ch))
+
+	r.Render(e)
+	u, ok := (<-ch).(*event.EventUpdate)
+	assert.True(t, ok, "update event")
+	return u
+}
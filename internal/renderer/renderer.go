@@ -16,6 +16,7 @@ import (
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
 	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
 	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
+	"github.com/l7mp/stunner-gateway-operator/internal/recorder"
 )
 
 var NewRenderer = NewDefaultRenderer
@@ -25,6 +26,9 @@ type Renderer interface {
 	Start(ctx context.Context) error
 	GetRenderChannel() chan event.Event
 	SetOperatorChannel(ch event.EventChannel)
+	// Render runs a single render pass synchronously and sends the updates to the operator
+	// channel.
+	Render(e *event.EventRender)
 }
 
 // configRenderer is a generic interface for the rendering components that can generate components
@@ -42,18 +46,27 @@ type resourceGenerator interface {
 type RendererConfig struct {
 	Scheme         *runtime.Scheme
 	LicenseManager licensemgr.Manager
-	Logger         logr.Logger
+	// Recorder emits Kubernetes Events for render errors, optional.
+	Recorder recorder.Recorder
+	// SyncStatus reports whether the dataplane has picked up the configs, optional.
+	SyncStatus config.SyncStatusReporter
+	Logger     logr.Logger
 }
 
 type renderer struct {
 	ctx                                           context.Context
+	traceCtx                                      context.Context
 	scheme                                        *runtime.Scheme
 	licmgr                                        licensemgr.Manager
 	adminRenderer, authRenderer, listenerRenderer configRenderer
 	dataplaneGenerator                            resourceGenerator
+	pdbGenerator, hpaGenerator                    resourceGenerator
 	gen                                           int
 	renderCh                                      chan event.Event
 	operatorCh                                    event.EventChannel
+	cache                                         *renderCache
+	recorder                                      recorder.Recorder
+	syncStatus                                    config.SyncStatusReporter
 	*config.ProgressTracker
 	log logr.Logger
 }
@@ -67,11 +80,19 @@ func NewDefaultRenderer(cfg RendererConfig) Renderer {
 		authRenderer:       newAuthRenderer(),
 		listenerRenderer:   newListenerRenderer(cfg.Logger.WithName("listener-renderer")),
 		dataplaneGenerator: newDataplaneGenerator(cfg.Scheme),
+		pdbGenerator:       newPDBGenerator(cfg.Scheme),
+		hpaGenerator:       newHPAGenerator(cfg.Scheme),
 		renderCh:           make(chan event.Event, 10),
 		gen:                0,
+		cache:              newRenderCache(),
+		recorder:           cfg.Recorder,
+		syncStatus:         cfg.SyncStatus,
 		ProgressTracker:    config.NewProgressTracker(),
 		log:                cfg.Logger.WithName("renderer"),
 	}
+	if r.recorder == nil {
+		r.recorder = recorder.NewNoopRecorder()
+	}
 	r.log.V(4).Info("Renderer thread created (**default** renderer)")
 	return r
 }
@@ -154,6 +175,15 @@ func (r *renderer) renderAdmin(c *RenderContext) (*stnrconfv1.AdminConfig, error
 	return conf.(*stnrconfv1.AdminConfig), nil
 }
 
+// getAuth returns the auth config for the GatewayConfig of the render context, rendering it only
+// once per render context.
+func (r *renderer) getAuth(c *RenderContext) (*stnrconfv1.AuthConfig, error) {
+	if c.auth == nil && c.authErr == nil {
+		c.auth, c.authErr = r.renderAuth(c)
+	}
+	return c.auth, c.authErr
+}
+
 // renderAuth is a wrapper for authRenderer.render()
 func (r *renderer) renderAuth(c *RenderContext) (*stnrconfv1.AuthConfig, error) {
 	conf, err := r.authRenderer.render(c)
@@ -180,3 +210,23 @@ func (r *renderer) generateDataplane(c *RenderContext) (client.Object, error) {
 	}
 	return obj, nil
 }
+
+// generateHorizontalPodAutoscaler is a wrapper for hpaGenerator.generate(), returns nil if no
+// HorizontalPodAutoscaler is to be generated for the Gateway
+func (r *renderer) generateHorizontalPodAutoscaler(c *RenderContext) (client.Object, error) {
+	obj, err := r.hpaGenerator.generate(c)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// generatePodDisruptionBudget is a wrapper for pdbGenerator.generate(), returns nil if no
+// PodDisruptionBudget is to be generated for the Gateway
+func (r *renderer) generatePodDisruptionBudget(c *RenderContext) (client.Object, error) {
+	obj, err := r.pdbGenerator.generate(c)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"slices"
 	"strconv"
@@ -32,6 +33,9 @@ type gwAddrPort struct {
 	aType gwapiv1.AddressType
 	addr  string
 	port  int
+	// alts are the further public addresses found for the listener, e.g., the address in the
+	// other IP family for dual-stack LB services
+	alts []gwAddrPort
 }
 
 func (ap gwAddrPort) isEmpty() bool {
@@ -48,6 +52,21 @@ func (ap gwAddrPort) String() string {
 	return fmt.Sprintf("%s(type:%s):%d", ap.addr, string(ap.aType), ap.port)
 }
 
+// family returns the IP family of the address, or an empty string for hostnames.
+func (ap gwAddrPort) family() corev1.IPFamily {
+	if ap.aType != gwapiv1.IPAddressType {
+		return ""
+	}
+	ip := net.ParseIP(ap.addr)
+	if ip == nil {
+		return ""
+	}
+	if ip.To4() != nil {
+		return corev1.IPv4Protocol
+	}
+	return corev1.IPv6Protocol
+}
+
 // returns the preferred address/port exposition for all listeners of the gateway
 // preference order: loadbalancer svc > nodeport svc
 func (r *renderer) getPublicAddr(gw *gwapiv1.Gateway) ([]gwAddrPort, error) {
@@ -59,12 +78,25 @@ func (r *renderer) getPublicAddr(gw *gwapiv1.Gateway) ([]gwAddrPort, error) {
 		return aps, err
 	}
 
+	// requested public address family per listener
+	var families map[string]corev1.IPFamily
+	if v, ok := gw.GetAnnotations()[opdefault.PublicAddressFamilyAnnotationKey]; ok {
+		if kvs, err := getAddressFamiliesFromAnn(v); err != nil {
+			r.log.Error(err, "Invalid Gateway public address family annotation (required: JSON "+
+				"formatted listener-family key-value pairs), ignoring", "gateway",
+				store.GetObjectKey(gw), "key", opdefault.PublicAddressFamilyAnnotationKey,
+				"annotation", v)
+		} else {
+			families = kvs
+		}
+	}
+
 	// find the addr-port per each listener
 	status := make([]string, len(gw.Spec.Listeners))
 	var retErr error
 	for i, l := range gw.Spec.Listeners {
 		status[i] = "<nil>"
-		ap, err := r.getPublicListenerAddr(svc, gw, &gw.Spec.Listeners[i])
+		addrs, err := r.getPublicListenerAddr(svc, gw, &gw.Spec.Listeners[i])
 		if err != nil {
 			r.log.Info("Could not find public adddress for listener",
 				"gateway", store.GetObjectKey(gw), "listener", l.Name,
@@ -72,6 +104,15 @@ func (r *renderer) getPublicAddr(gw *gwapiv1.Gateway) ([]gwAddrPort, error) {
 			retErr = NewNonCriticalError(PublicListenerAddressNotFound)
 			continue
 		}
+
+		family := families[string(l.Name)]
+		ap, ok := selectPublicAddr(addrs, family)
+		if !ok {
+			r.log.Info("Could not find public address of the requested IP family for "+
+				"listener, falling back to the first address", "gateway",
+				store.GetObjectKey(gw), "listener", l.Name, "family", family,
+				"address", ap.String())
+		}
 		aps[i] = ap
 		status[i] = ap.String()
 	}
@@ -145,10 +186,12 @@ func isSvcPreferred(a, b *corev1.Service) bool {
 	return false
 }
 
-func (r *renderer) getPublicListenerAddr(svc *corev1.Service, gw *gwapiv1.Gateway, l *gwapiv1.Listener) (gwAddrPort, error) {
+// getPublicListenerAddr returns the public addresses found for a listener, in order of
+// preference.
+func (r *renderer) getPublicListenerAddr(svc *corev1.Service, gw *gwapiv1.Gateway, l *gwapiv1.Listener) ([]gwAddrPort, error) {
 	serviceProto, err := r.getServiceProtocol(l.Protocol)
 	if err != nil {
-		return gwAddrPort{}, err
+		return nil, err
 	}
 
 	// find the right service-port
@@ -163,39 +206,44 @@ func (r *renderer) getPublicListenerAddr(svc *corev1.Service, gw *gwapiv1.Gatewa
 	}
 
 	if sp == nil {
-		return gwAddrPort{}, errors.New("Cannot find matching service-port for listener" +
+		return nil, errors.New("Cannot find matching service-port for listener" +
 			"(hint: enable mixed-protocol-LB support)")
 	}
 
 	// Public IPs weighed in the following order: (see
 	// https://github.com/l7mp/stunner-gateway-operator/issues/3)
 	//
-	// 1. Gateway.Spec.Addresses[0] + Gateway.Spec.Listeners[0].Port
-	if len(gw.Spec.Addresses) > 0 && gw.Spec.Addresses[0].Value != "" {
+	// 1. Gateway.Spec.Addresses + Gateway.Spec.Listeners[0].Port
+	aps := []gwAddrPort{}
+	for _, a := range gw.Spec.Addresses {
+		if a.Value == "" {
+			continue
+		}
 		t := gwapiv1.IPAddressType
-		if gw.Spec.Addresses[0].Type != nil {
-			t = *gw.Spec.Addresses[0].Type
+		if a.Type != nil {
+			t = *a.Type
 		}
-		ap := gwAddrPort{
+		aps = append(aps, gwAddrPort{
 			aType: t,
-			addr:  gw.Spec.Addresses[0].Value,
+			addr:  a.Value,
 			port:  int(sp.Port),
-		}
-
+		})
+	}
+	if len(aps) > 0 {
 		r.log.V(4).Info("Using requested address from Gateway spec for listener",
 			"service", store.GetObjectKey(svc), "gateway", store.GetObjectKey(gw),
-			"listener", l.Name, "address", ap.String())
+			"listener", l.Name, "address", aps[0].String())
 
-		return ap, nil
+		return aps, nil
 	}
 
-	// 2. If Address is not set, we use the LoadBalancer IP and the above listener port
+	// 2. If Address is not set, we use the LoadBalancer IPs and the above listener port
 	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
-		if ap := getLBAddr(svc, spIndex); ap != nil {
+		if aps := getLBAddr(svc, spIndex); len(aps) > 0 {
 			r.log.V(4).Info("Using LoadBalancer address for listener",
 				"service", store.GetObjectKey(svc), "gateway", store.GetObjectKey(gw),
-				"listener", l.Name, "address", ap.String())
-			return *ap, nil
+				"listener", l.Name, "address", aps[0].String())
+			return aps, nil
 		}
 	}
 
@@ -212,14 +260,46 @@ func (r *renderer) getPublicListenerAddr(svc *corev1.Service, gw *gwapiv1.Gatewa
 			"service", store.GetObjectKey(svc), "gateway", store.GetObjectKey(gw),
 			"listener", l.Name, "address", ap.String())
 
-		return ap, nil
+		return []gwAddrPort{ap}, nil
+	}
+
+	return nil, errors.New("Could not find usable public address for listener")
+}
+
+// selectPublicAddr chooses the first address of the requested IP family, or the first address
+// if no family is requested, and returns the rest of the addresses as alternatives. Returns
+// false if no address of the requested family is found, in which case the first address is
+// returned.
+func selectPublicAddr(aps []gwAddrPort, family corev1.IPFamily) (gwAddrPort, bool) {
+	if len(aps) == 0 {
+		return gwAddrPort{}, family == ""
+	}
+
+	idx, found := 0, family == ""
+	if !found {
+		for i := range aps {
+			if aps[i].family() == family {
+				idx, found = i, true
+				break
+			}
+		}
+	}
+
+	ap := aps[idx]
+	ap.alts = []gwAddrPort{}
+	for i := range aps {
+		if i != idx {
+			ap.alts = append(ap.alts, aps[i])
+		}
 	}
 
-	return gwAddrPort{}, errors.New("Could not find usable public address for listener")
+	return ap, found
 }
 
-// first matching service-port and load-balancer service status
-func getLBAddr(svc *corev1.Service, spIndex int) *gwAddrPort {
+// all load-balancer addresses from the service status for the service-port, in the order they
+// appear in the status: dual-stack load-balancers usually report one address per IP family
+func getLBAddr(svc *corev1.Service, spIndex int) []gwAddrPort {
+	aps := []gwAddrPort{}
 	for _, ingressStatus := range svc.Status.LoadBalancer.Ingress {
 		// if status contains per-service-port status
 		if len(ingressStatus.Ports) > 0 && spIndex < len(ingressStatus.Ports) {
@@ -230,54 +310,60 @@ func getLBAddr(svc *corev1.Service, spIndex int) *gwAddrPort {
 				continue
 			}
 
-			// if IP address is available, use it
-			if ingressStatus.IP != "" {
-				return &gwAddrPort{
-					aType: gwapiv1.IPAddressType,
-					addr:  ingressStatus.IP,
-					port:  int(spStatus.Port),
-				}
-			}
-
-			// fallback to Hostname (typically for AWS)
-			if ingressStatus.Hostname != "" {
-				return &gwAddrPort{
-					aType: gwapiv1.HostnameAddressType,
-					addr:  ingressStatus.Hostname,
-					port:  int(spStatus.Port),
-				}
+			if ap := getLBIngressAddr(ingressStatus, int(spStatus.Port)); ap != nil {
+				aps = appendAddrPort(aps, *ap)
 			}
 		}
 	}
 
+	if len(aps) > 0 {
+		return aps
+	}
+
 	// some load-balancer controllers do not include a status.Ingress[x].Ports substatus: we
-	// fall back to the first load-balancer IP we find and use the port from the service-port
-	// as a port
-	if len(svc.Status.LoadBalancer.Ingress) > 0 {
-		ingressStatus := svc.Status.LoadBalancer.Ingress[0]
-
-		// if IP address is available, use it
-		if ingressStatus.IP != "" {
-			return &gwAddrPort{
-				aType: gwapiv1.IPAddressType,
-				addr:  ingressStatus.IP,
-				port:  int(svc.Spec.Ports[spIndex].Port),
-			}
+	// fall back to the load-balancer IPs we find and use the port from the service-port as a
+	// port
+	for _, ingressStatus := range svc.Status.LoadBalancer.Ingress {
+		if ap := getLBIngressAddr(ingressStatus, int(svc.Spec.Ports[spIndex].Port)); ap != nil {
+			aps = appendAddrPort(aps, *ap)
 		}
+	}
 
-		// fallback to Hostname (typically for AWS)
-		if ingressStatus.Hostname != "" {
-			return &gwAddrPort{
-				aType: gwapiv1.HostnameAddressType,
-				addr:  ingressStatus.Hostname,
-				port:  int(svc.Spec.Ports[spIndex].Port),
-			}
+	return aps
+}
+
+func getLBIngressAddr(ingressStatus corev1.LoadBalancerIngress, port int) *gwAddrPort {
+	// if IP address is available, use it
+	if ingressStatus.IP != "" {
+		return &gwAddrPort{
+			aType: gwapiv1.IPAddressType,
+			addr:  ingressStatus.IP,
+			port:  port,
+		}
+	}
+
+	// fallback to Hostname (typically for AWS)
+	if ingressStatus.Hostname != "" {
+		return &gwAddrPort{
+			aType: gwapiv1.HostnameAddressType,
+			addr:  ingressStatus.Hostname,
+			port:  port,
 		}
 	}
 
 	return nil
 }
 
+// appendAddrPort appends an address to a list unless the list already contains it.
+func appendAddrPort(aps []gwAddrPort, ap gwAddrPort) []gwAddrPort {
+	for _, a := range aps {
+		if a.aType == ap.aType && a.addr == ap.addr && a.port == ap.port {
+			return aps
+		}
+	}
+	return append(aps, ap)
+}
+
 func (r *renderer) createLbService4Gateway(c *RenderContext, gw *gwapiv1.Gateway) (*corev1.Service, map[string]int) {
 	if len(gw.Spec.Listeners) == 0 {
 		// should never happen
@@ -317,12 +403,19 @@ func (r *renderer) createLbService4Gateway(c *RenderContext, gw *gwapiv1.Gateway
 			},
 		}
 	} else {
-		// mandatory labels and annotations must always be there
-		svc.SetLabels(mergeMaps(svc.GetLabels(), mandatoryLabels))
-		svc.SetAnnotations(mergeAnnotations(svc.GetAnnotations(),
-			mergeMaps(gw.GetAnnotations(), requestedAnnotations)))
+		// mandatory labels and annotations must always be there, and the labels and
+		// annotations we applied earlier but are no longer requested are removed
+		last := store.GetLastAppliedMetadata(svc)
+		svc.SetLabels(store.PruneMetadata(mergeMaps(svc.GetLabels(), mandatoryLabels),
+			mandatoryLabels, last.Labels))
+		svc.SetAnnotations(store.PruneMetadata(mergeAnnotations(svc.GetAnnotations(),
+			mergeMaps(gw.GetAnnotations(), requestedAnnotations)),
+			requestedAnnotations, last.Annotations))
 	}
 
+	// record the labels and annotations we apply
+	store.SetLastAppliedMetadata(svc, mandatoryLabels, requestedAnnotations)
+
 	// set selectors
 	switch config.DataplaneMode {
 	case config.DataplaneModeLegacy:
@@ -381,6 +474,29 @@ func (r *renderer) createLbService4Gateway(c *RenderContext, gw *gwapiv1.Gateway
 		svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicy("")
 	}
 
+	// IPFamilyPolicy
+	if v, ok := annotations[opdefault.IPFamilyPolicyAnnotationKey]; ok {
+		if policy, err := getIPFamilyPolicyFromAnn(v); err != nil {
+			r.log.Error(err, "Invalid Gateway IP family policy annotation (required: "+
+				"SingleStack, PreferDualStack or RequireDualStack), ignoring", "gateway",
+				store.GetObjectKey(gw), "key", opdefault.IPFamilyPolicyAnnotationKey,
+				"annotation", v)
+		} else {
+			svc.Spec.IPFamilyPolicy = &policy
+		}
+	}
+
+	// IPFamilies
+	if v, ok := annotations[opdefault.IPFamiliesAnnotationKey]; ok {
+		if families, err := getIPFamiliesFromAnn(v); err != nil {
+			r.log.Error(err, "Invalid Gateway IP families annotation (required: comma-separated "+
+				"list of IPv4 and IPv6), ignoring", "gateway", store.GetObjectKey(gw), "key",
+				opdefault.IPFamiliesAnnotationKey, "annotation", v)
+		} else {
+			svc.Spec.IPFamilies = families
+		}
+	}
+
 	// NodePort
 	listenerNodeports := make(map[string]int)
 	if v, ok := annotations[opdefault.NodePortAnnotationKey]; ok {
@@ -625,6 +741,69 @@ func getServicePortsFromAnn(v string) (map[string]int, error) {
 	return kvs, nil
 }
 
+func getIPFamilyPolicyFromAnn(v string) (corev1.IPFamilyPolicy, error) {
+	for _, p := range []corev1.IPFamilyPolicy{
+		corev1.IPFamilyPolicySingleStack,
+		corev1.IPFamilyPolicyPreferDualStack,
+		corev1.IPFamilyPolicyRequireDualStack,
+	} {
+		if strings.EqualFold(strings.TrimSpace(v), string(p)) {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unknown IP family policy %q", v)
+}
+
+func getIPFamily(v string) (corev1.IPFamily, error) {
+	for _, f := range []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol} {
+		if strings.EqualFold(strings.TrimSpace(v), string(f)) {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unknown IP family %q", v)
+}
+
+func getIPFamiliesFromAnn(v string) ([]corev1.IPFamily, error) {
+	families := []corev1.IPFamily{}
+	for _, s := range strings.Split(v, ",") {
+		f, err := getIPFamily(s)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(families, f) {
+			return nil, fmt.Errorf("Duplicate IP family %q", f)
+		}
+		families = append(families, f)
+	}
+
+	return families, nil
+}
+
+func getAddressFamiliesFromAnn(v string) (map[string]corev1.IPFamily, error) {
+	// parse as JSON
+	kvs := make(map[string]string)
+	if err := json.Unmarshal([]byte(v), &kvs); err != nil {
+		// try our best to parse: add missing curlies
+		if err2 := json.Unmarshal([]byte("{"+v+"}"), &kvs); err2 != nil {
+			return nil, fmt.Errorf("Could not parse address family annotation as a "+
+				"formatted list of key-value pairs: %w", err)
+		}
+	}
+
+	ret := make(map[string]corev1.IPFamily, len(kvs))
+	for l, f := range kvs {
+		family, err := getIPFamily(f)
+		if err != nil {
+			return nil, err
+		}
+		ret[l] = family
+	}
+
+	return ret, nil
+}
+
 // // fallback
 // func getServiceNodePortForSingleListener(v string, gw *gwapiv1.Gateway) (map[string]int, error) {
 // 	// parse as int
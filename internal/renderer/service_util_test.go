@@ -509,7 +509,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 1, "annotations len")
+				assert.Len(t, as, 2, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -573,7 +573,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 1, "annotations len")
+				assert.Len(t, as, 2, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -663,7 +663,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 1, "annotations len")
+				assert.Len(t, as, 2, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -724,7 +724,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 1, "annotations len")
+				assert.Len(t, as, 2, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -785,7 +785,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 2, "annotations len")
+				assert.Len(t, as, 3, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -852,7 +852,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 2, "annotations len")
+				assert.Len(t, as, 3, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -915,7 +915,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 2, "annotations len")
+				assert.Len(t, as, 3, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -986,7 +986,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 2, "annotations len")
+				assert.Len(t, as, 3, "annotations len")
 				gwa, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation found")
 				assert.Equal(t, store.GetObjectKey(gw), gwa, "annotation ok")
@@ -1048,7 +1048,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, gw.GetNamespace(), lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1111,7 +1111,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, opdefault.OwnedByLabelValue, lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1216,7 +1216,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, opdefault.OwnedByLabelValue, lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1282,7 +1282,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, opdefault.OwnedByLabelValue, lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1345,7 +1345,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, opdefault.OwnedByLabelValue, lab, "label ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1409,7 +1409,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, c.gwConf.GetNamespace(), s.GetNamespace(), "namespace ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 4, "annotations len")
+				assert.Len(t, as, 5, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -1475,7 +1475,7 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.Equal(t, c.gwConf.GetNamespace(), s.GetNamespace(), "namespace ok")
 
 				as := s.GetAnnotations()
-				assert.Len(t, as, 3, "annotations len")
+				assert.Len(t, as, 4, "annotations len")
 
 				a, found := as[opdefault.RelatedGatewayKey]
 				assert.True(t, found, "annotation 1 found")
@@ -2480,5 +2480,300 @@ func TestRenderServiceUtil(t *testing.T) {
 				assert.False(t, ok, "ann valid in both - ok")
 			},
 		},
+		{
+			name: "lb service - annotation removed from gwConf is removed from svc",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				w := testutils.TestGwConfig.DeepCopy()
+				w.Spec.LoadBalancerServiceAnnotations = map[string]string{"keep": "keepval"}
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+
+				s1 := testutils.TestSvc.DeepCopy()
+				s1.SetNamespace(testutils.TestGw.GetNamespace())
+				s1.SetName(testutils.TestGw.GetName())
+				s1.SetAnnotations(map[string]string{
+					"keep":                      "keepval",
+					"removed":                   "removedval",
+					"cloud-provider-annotation": "cloudval",
+				})
+				store.SetLastAppliedMetadata(s1, nil, map[string]string{
+					"keep":    "",
+					"removed": "",
+				})
+				s1.SetOwnerReferences([]metav1.OwnerReference{{
+					APIVersion: gwapiv1.GroupVersion.String(),
+					Kind:       "Gateway",
+					UID:        testutils.TestGw.GetUID(),
+					Name:       testutils.TestGw.GetName(),
+				}})
+				c.svcs = []corev1.Service{*s1}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				s, _ := r.createLbService4Gateway(c, gw)
+				assert.NotNil(t, s, "svc create")
+
+				as := s.GetAnnotations()
+				assert.Equal(t, "keepval", as["keep"], "requested annotation kept")
+				assert.Equal(t, "cloudval", as["cloud-provider-annotation"],
+					"cloud-provider annotation kept")
+				assert.NotContains(t, as, "removed", "annotation no longer requested removed")
+
+				last := store.GetLastAppliedMetadata(s)
+				assert.Equal(t, []string{"keep", opdefault.RelatedGatewayKey}, last.Annotations,
+					"applied annotations recorded")
+				assert.Equal(t, []string{opdefault.OwnedByLabelKey, opdefault.RelatedGatewayKey,
+					opdefault.RelatedGatewayNamespace}, last.Labels, "applied labels recorded")
+			},
+		},
+		{
+			name: "dual-stack lb addresses ok",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				s := dualStackTestSvc()
+				c.svcs = []corev1.Service{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				addrs, err := r.getPublicAddr(gw)
+				assert.NoError(t, err, "public addr found")
+				assert.Len(t, addrs, 2, "public addr-port len")
+				for i, ap := range addrs {
+					assert.Equal(t, gwapiv1.IPAddressType, ap.aType, "public addr type ok")
+					assert.Equal(t, "1.2.3.4", ap.addr, "public addr defaults to first")
+					assert.Equal(t, i+1, ap.port, "public port ok")
+					assert.Len(t, ap.alts, 1, "alternative addrs")
+					assert.Equal(t, "2001:db8::1", ap.alts[0].addr, "alternative addr ok")
+					assert.Equal(t, corev1.IPv6Protocol, ap.alts[0].family(), "alternative family ok")
+				}
+
+				setGatewayStatusProgrammed(gw, nil, addrs)
+				assert.Len(t, gw.Status.Addresses, 2, "status addresses")
+				assert.Equal(t, "1.2.3.4", gw.Status.Addresses[0].Value, "status address 1")
+				assert.Equal(t, "2001:db8::1", gw.Status.Addresses[1].Value, "status address 2")
+			},
+		},
+		{
+			name: "dual-stack lb - public address family selected per listener",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.SetAnnotations(map[string]string{
+					opdefault.PublicAddressFamilyAnnotationKey: `{"gateway-1-listener-udp":"IPv6"}`,
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+				s := dualStackTestSvc()
+				c.svcs = []corev1.Service{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				addrs, err := r.getPublicAddr(gw)
+				assert.NoError(t, err, "public addr found")
+				assert.Len(t, addrs, 2, "public addr-port len")
+				assert.Equal(t, "2001:db8::1", addrs[0].addr, "public addr 1: IPv6")
+				assert.Equal(t, 1, addrs[0].port, "public port 1 ok")
+				assert.Equal(t, "1.2.3.4", addrs[1].addr, "public addr 2: default")
+				assert.Equal(t, 2, addrs[1].port, "public port 2 ok")
+
+				setGatewayStatusProgrammed(gw, nil, addrs)
+				assert.Len(t, gw.Status.Addresses, 2, "status addresses")
+				assert.Equal(t, "2001:db8::1", gw.Status.Addresses[0].Value, "status address 1")
+				assert.Equal(t, "1.2.3.4", gw.Status.Addresses[1].Value, "status address 2")
+			},
+		},
+		{
+			name: "dual-stack lb - unavailable public address family falls back",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.SetAnnotations(map[string]string{
+					opdefault.PublicAddressFamilyAnnotationKey: `{"gateway-1-listener-tcp":"IPv6"}`,
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+				s := testutils.TestSvc.DeepCopy()
+				s.SetOwnerReferences([]metav1.OwnerReference{{
+					APIVersion: gwapiv1.GroupVersion.String(),
+					Kind:       "Gateway",
+					UID:        testutils.TestGw.GetUID(),
+					Name:       testutils.TestGw.GetName(),
+				}})
+				c.svcs = []corev1.Service{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				addrs, err := r.getPublicAddr(gw)
+				assert.NoError(t, err, "public addr found")
+				assert.Len(t, addrs, 2, "public addr-port len")
+				assert.Equal(t, "1.2.3.4", addrs[1].addr, "public addr 2: fallback")
+				assert.Equal(t, 2, addrs[1].port, "public port 2 ok")
+				assert.Len(t, addrs[1].alts, 0, "no alternative addrs")
+			},
+		},
+		{
+			name: "dual-stack lb - invalid public address family annotation ignored",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.SetAnnotations(map[string]string{
+					opdefault.PublicAddressFamilyAnnotationKey: `{"gateway-1-listener-udp":"IPv5"}`,
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+				s := dualStackTestSvc()
+				c.svcs = []corev1.Service{*s}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				addrs, err := r.getPublicAddr(gw)
+				assert.NoError(t, err, "public addr found")
+				assert.Len(t, addrs, 2, "public addr-port len")
+				assert.Equal(t, "1.2.3.4", addrs[0].addr, "public addr 1: default")
+				assert.Equal(t, "1.2.3.4", addrs[1].addr, "public addr 2: default")
+			},
+		},
+		{
+			name: "lb service - IP family policy and IP families from gwConf and gw annotations",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{},
+			prep: func(c *renderTestConfig) {
+				w := testutils.TestGwConfig.DeepCopy()
+				w.Spec.LoadBalancerServiceAnnotations = map[string]string{
+					opdefault.IPFamilyPolicyAnnotationKey: "PreferDualStack",
+					opdefault.IPFamiliesAnnotationKey:     "IPv4,IPv6",
+				}
+				c.cfs = []stnrgwv1.GatewayConfig{*w}
+
+				gw := testutils.TestGw.DeepCopy()
+				gw.SetAnnotations(map[string]string{
+					opdefault.IPFamilyPolicyAnnotationKey: "requiredualstack",
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				s, _ := r.createLbService4Gateway(c, gw)
+				assert.NotNil(t, s, "svc create")
+				assert.NotNil(t, s.Spec.IPFamilyPolicy, "ip family policy")
+				assert.Equal(t, corev1.IPFamilyPolicyRequireDualStack, *s.Spec.IPFamilyPolicy,
+					"ip family policy: gw overrides gwConf")
+				assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+					s.Spec.IPFamilies, "ip families")
+			},
+		},
+		{
+			name: "lb service - invalid IP family policy and IP families ignored",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{},
+			svcs: []corev1.Service{},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.SetAnnotations(map[string]string{
+					opdefault.IPFamilyPolicyAnnotationKey: "TripleStack",
+					opdefault.IPFamiliesAnnotationKey:     "IPv6,IPv6",
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+				c.gwConf, err = r.getGatewayConfig4Class(c)
+				assert.NoError(t, err, "gw-conf found")
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gateways for class")
+				gw := gws[0]
+
+				s, _ := r.createLbService4Gateway(c, gw)
+				assert.NotNil(t, s, "svc create")
+				assert.Nil(t, s.Spec.IPFamilyPolicy, "ip family policy unset")
+				assert.Nil(t, s.Spec.IPFamilies, "ip families unset")
+			},
+		},
 	})
 }
+
+// dualStackTestSvc returns the test service with an IPv4 and an IPv6 load-balancer address.
+func dualStackTestSvc() *corev1.Service {
+	s := testutils.TestSvc.DeepCopy()
+	s.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: gwapiv1.GroupVersion.String(),
+		Kind:       "Gateway",
+		UID:        testutils.TestGw.GetUID(),
+		Name:       testutils.TestGw.GetName(),
+	}})
+	v6 := *s.Status.LoadBalancer.Ingress[0].DeepCopy()
+	v6.IP = "2001:db8::1"
+	s.Status.LoadBalancer.Ingress = append(s.Status.LoadBalancer.Ingress, v6)
+	return s
+}
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"errors"
 	"fmt"
 	// "github.com/go-logr/logr"
 	// apiv1 "k8s.io/api/core/v1"
@@ -36,11 +37,29 @@ func (r *renderer) allUDPRoutes() []*stnrgwv1.UDPRoute {
 	return rs
 }
 
-func (r *renderer) getUDPRoutes4Listener(gw *gwapiv1.Gateway, l *gwapiv1.Listener) []*stnrgwv1.UDPRoute {
-	r.log.V(4).Info("getUDPRoutes4Listener", "gateway", store.GetObjectKey(gw), "listener", l.Name)
+// allRoutes returns all the routes the renderer considers: UDPRoutes, plus TCPRoutes and
+// TLSRoutes converted to the canonical UDPRoute representation. Masked routes are skipped.
+func (r *renderer) allRoutes() []*stnrgwv1.UDPRoute {
+	rs := r.allUDPRoutes()
+
+	for _, ro := range append(store.TCPRoutes.GetAll(), store.TLSRoutes.GetAll()...) {
+		if isRouteMasked(ro) {
+			_, kind := getRouteGroupKind(ro)
+			r.log.Info("Ignoring route masked by a route of the same name:", "kind", kind,
+				"name", ro.GetName(), "namespace", ro.GetNamespace())
+			continue
+		}
+		rs = append(rs, ro)
+	}
+
+	return rs
+}
+
+func (r *renderer) getRoutes4Listener(gw *gwapiv1.Gateway, l *gwapiv1.Listener) []*stnrgwv1.UDPRoute {
+	r.log.V(4).Info("getRoutes4Listener", "gateway", store.GetObjectKey(gw), "listener", l.Name)
 
 	ret := make([]*stnrgwv1.UDPRoute, 0)
-	rs := r.allUDPRoutes()
+	rs := r.allRoutes()
 	for i := range rs {
 		ro := rs[i]
 		r.log.V(4).Info("Considering route for listener", "gateway",
@@ -52,7 +71,7 @@ func (r *renderer) getUDPRoutes4Listener(gw *gwapiv1.Gateway, l *gwapiv1.Listene
 
 			found, reason := resolveParentRef(ro, &p, gw, l)
 			if !found {
-				r.log.V(4).Info("UDPRoute parent rejected for listener",
+				r.log.V(4).Info("Route parent rejected for listener",
 					"gateway", store.GetObjectKey(gw), "listener", l.Name,
 					"route", store.GetObjectKey(ro), "parent", store.DumpParentRef(&p),
 					"reason", reason)
@@ -106,6 +125,31 @@ func resolveParentRef(ro *stnrgwv1.UDPRoute, p *gwapiv1.ParentReference, gw *gwa
 			string(*p.SectionName), l.Name)
 	}
 
+	allowed, msg = listenerAllowsRouteKind(ro, l)
+	if !allowed {
+		return false, msg
+	}
+
+	return true, ""
+}
+
+// listenerAllowsRouteKind checks whether the kind of a route allows it to attach to a listener:
+// TCPRoutes attach only to TURN-TCP listeners and TLSRoutes only to TURN-TLS listeners, while
+// UDPRoutes may attach to any listener. If the listener restricts the allowed route kinds then the
+// kind of the route must also be listed there.
+func listenerAllowsRouteKind(ro *stnrgwv1.UDPRoute, l *gwapiv1.Listener) (bool, string) {
+	group, kind := getRouteGroupKind(ro)
+
+	if !routeKindInList(group, kind, getSupportedRouteKinds(l)) {
+		return false, fmt.Sprintf("route kind %s cannot attach to listener %q of protocol %s",
+			kind, l.Name, string(l.Protocol))
+	}
+
+	if kinds, _ := getListenerRouteKinds(l); !routeKindInList(group, kind, kinds) {
+		return false, fmt.Sprintf("route kind %s/%s is not among the allowed route kinds of listener %q",
+			group, kind, l.Name)
+	}
+
 	return true, ""
 }
 
@@ -276,9 +320,12 @@ func (r *renderer) getParentGateway(ro *stnrgwv1.UDPRoute, p *gwapiv1.ParentRefe
 	return store.Gateways.GetObject(namespacedName)
 }
 
-// invalidateMaskedRoutes invalidates a masked GWAPIV1A2 UDPROute
+// invalidateMaskedRoutes invalidates the masked GWAPIV1A2 UDPRoutes, TCPRoutes and TLSRoutes
 func (r *renderer) invalidateMaskedRoutes(c *RenderContext) {
-	for _, ro := range store.UDPRoutesV1A2.GetAll() {
+	rs := store.UDPRoutesV1A2.GetAll()
+	rs = append(rs, store.TCPRoutes.GetAll()...)
+	rs = append(rs, store.TLSRoutes.GetAll()...)
+	for _, ro := range rs {
 		if !isRouteMasked(ro) || !r.isRouteControlled(ro) {
 			continue
 		}
@@ -291,7 +338,22 @@ func (r *renderer) invalidateMaskedRoutes(c *RenderContext) {
 			setRouteConditionStatus(ro, &p, config.ControllerName, parentExists, parentAccept, nil)
 		}
 
+		upsertRouteStatus(c, ro)
+	}
+}
+
+// upsertRouteStatus schedules the status of a route for update in the queue corresponding to the
+// API object the route was converted from.
+func upsertRouteStatus(c *RenderContext, ro *stnrgwv1.UDPRoute) {
+	switch {
+	case isRouteV1A2(ro):
 		c.update.UpsertQueue.UDPRoutesV1A2.Upsert(statusTargetV1A2UDPRoute(ro))
+	case isRouteTCP(ro):
+		c.update.UpsertQueue.TCPRoutes.Upsert(statusTargetV1A2Route(ro, &gwapiv1a2.TCPRoute{}))
+	case isRouteTLS(ro):
+		c.update.UpsertQueue.TLSRoutes.Upsert(statusTargetV1A2Route(ro, &gwapiv1a2.TLSRoute{}))
+	default:
+		c.update.UpsertQueue.UDPRoutes.Upsert(ro.DeepCopy())
 	}
 }
 
@@ -305,10 +367,35 @@ func statusTargetV1A2UDPRoute(ro *stnrgwv1.UDPRoute) *gwapiv1a2.UDPRoute {
 	ret := &gwapiv1a2.UDPRoute{}
 	ret.SetName(ro.GetName())
 	ret.SetNamespace(ro.GetNamespace())
+	ret.SetUID(ro.GetUID())
 	ro.Status.DeepCopyInto(&ret.Status)
 	return ret
 }
 
+// statusTargetV1A2Route fills an empty Gateway API route, e.g., a TCPRoute or a TLSRoute, as a
+// bearer object for status updates, see statusTargetV1A2UDPRoute.
+func statusTargetV1A2Route[R stnrgwv1.V1A2Route](ro *stnrgwv1.UDPRoute, ret R) R {
+	ret.SetName(ro.GetName())
+	ret.SetNamespace(ro.GetNamespace())
+	ret.SetUID(ro.GetUID())
+	ro.Status.RouteStatus.DeepCopyInto(stnrgwv1.V1A2RouteStatus(ret))
+	return ret
+}
+
+// eventTargetRoute returns the object to emit the Kubernetes Events for a route on: same as
+// for status updates, events must refer to the API object type that exists in the cluster.
+func eventTargetRoute(ro *stnrgwv1.UDPRoute) client.Object {
+	switch {
+	case isRouteV1A2(ro):
+		return statusTargetV1A2UDPRoute(ro)
+	case isRouteTCP(ro):
+		return statusTargetV1A2Route(ro, &gwapiv1a2.TCPRoute{})
+	case isRouteTLS(ro):
+		return statusTargetV1A2Route(ro, &gwapiv1a2.TLSRoute{})
+	}
+	return ro
+}
+
 func setRouteConditionStatus(ro *stnrgwv1.UDPRoute, p *gwapiv1.ParentReference, controllerName string, exists, accepted bool, backendErr error) {
 	// ns := gwapiv1.Namespace(ro.GetNamespace())
 	// gr := gwapiv1.Group(gwapiv1.GroupVersion.Group)
@@ -341,8 +428,12 @@ func setRouteConditionStatus(ro *stnrgwv1.UDPRoute, p *gwapiv1.ParentReference,
 	}
 
 	if isRouteMasked(ro) {
+		msg := "GwAPI.v1 UDPRoute masked by a STUNnerV1 UDPRoute"
+		if _, kind := getRouteGroupKind(ro); kind != "UDPRoute" {
+			msg = fmt.Sprintf("Gateway API %s masked by a route of the same name", kind)
+		}
 		setRouteAcceptedCondition(ro, &s.Conditions, gwapiv1.RouteReasonPending,
-			metav1.ConditionFalse, "GwAPI.v1 UDPRoute masked by a STUNnerV1 UDPRoute")
+			metav1.ConditionFalse, msg)
 	} else {
 		namespace := ro.GetNamespace()
 		if p.Namespace != nil {
@@ -372,16 +463,28 @@ func setRouteConditionStatus(ro *stnrgwv1.UDPRoute, p *gwapiv1.ParentReference,
 			// one of the Route's rules has a reference to an unknown or unsupported
 			// Group and/or Kind.
 			reason = gwapiv1.RouteReasonInvalidKind
+		case IsNonCriticalError(backendErr, RefNotPermitted):
+			// "RouteReasonRefNotPermitted" is used with the "ResolvedRefs" condition
+			// when one of the Route's rules has a BackendRef to an object in another
+			// namespace, where the object in the other namespace does not have a
+			// ReferenceGrant explicitly allowing the reference.
+			reason = gwapiv1.RouteReasonRefNotPermitted
 		default:
 			reason = gwapiv1.RouteReasonBackendNotFound
 		}
+		msg := "at least one backend reference failed to be successfully resolved"
+		var ruleErr *RuleError
+		if errors.As(backendErr, &ruleErr) {
+			msg = fmt.Sprintf("at least one backend reference in rule %d failed to be "+
+				"successfully resolved: %s", ruleErr.Rule, ruleErr.Unwrap().Error())
+		}
 		resolvedCond = metav1.Condition{
 			Type:               string(gwapiv1.RouteConditionResolvedRefs),
 			Status:             metav1.ConditionFalse,
 			ObservedGeneration: ro.Generation,
 			LastTransitionTime: metav1.Now(),
 			Reason:             string(reason),
-			Message:            "at least one backend reference failed to be successfully resolved",
+			Message:            msg,
 		}
 	} else {
 		resolvedCond = metav1.Condition{
@@ -422,6 +525,42 @@ func isRouteV1A2(ro client.Object) bool {
 	return store.UDPRoutesV1A2.Get(store.GetNamespacedName(ro)) == ro
 }
 
+// check by pointer: namespacedname is not unique across route kinds
+func isRouteTCP(ro client.Object) bool {
+	return store.TCPRoutes.Get(store.GetNamespacedName(ro)) == ro
+}
+
+// check by pointer: namespacedname is not unique across route kinds
+func isRouteTLS(ro client.Object) bool {
+	return store.TLSRoutes.Get(store.GetNamespacedName(ro)) == ro
+}
+
+// isRouteMasked returns true if there is a route with the same name and a higher precedence: the
+// dataplane identifies clusters by the route name so only one of these can be rendered. The
+// precedence is STUNner UDPRoute > Gateway API UDPRoute > TCPRoute > TLSRoute.
 func isRouteMasked(ro client.Object) bool {
-	return isRouteV1A2(ro) && store.UDPRoutes.Get(store.GetNamespacedName(ro)) != nil
+	key := store.GetNamespacedName(ro)
+	switch {
+	case isRouteV1A2(ro):
+		return store.UDPRoutes.Get(key) != nil
+	case isRouteTCP(ro):
+		return store.UDPRoutes.Get(key) != nil || store.UDPRoutesV1A2.Get(key) != nil
+	case isRouteTLS(ro):
+		return store.UDPRoutes.Get(key) != nil || store.UDPRoutesV1A2.Get(key) != nil ||
+			store.TCPRoutes.Get(key) != nil
+	}
+	return false
+}
+
+// getRouteGroupKind returns the API group and the kind of the object a route was converted from.
+func getRouteGroupKind(ro client.Object) (string, string) {
+	switch {
+	case isRouteV1A2(ro):
+		return gwapiv1.GroupName, "UDPRoute"
+	case isRouteTCP(ro):
+		return gwapiv1.GroupName, "TCPRoute"
+	case isRouteTLS(ro):
+		return gwapiv1.GroupName, "TLSRoute"
+	}
+	return stnrgwv1.GroupVersion.Group, "UDPRoute"
 }
@@ -43,7 +43,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				ro := rs[0]
 				assert.Equal(t, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-ok"),
@@ -102,7 +102,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 2, "route found")
 				keys := []string{store.GetObjectKey(rs[0]), store.GetObjectKey(rs[1])}
 				assert.Contains(t, keys, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-ok"),
@@ -139,7 +139,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 2, "route found")
 				keys := []string{store.GetObjectKey(rs[0]), store.GetObjectKey(rs[1])}
 				assert.Contains(t, keys, fmt.Sprintf("%s/%s", testutils.TestNsName,
@@ -175,7 +175,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-ok"),
 					store.GetObjectKey(rs[0]), "route name found")
@@ -229,7 +229,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 
 				l := ls[0]
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 
 				// listener accepts both routes: attachment policy is All
 				assert.Len(t, rs, 2, "route found")
@@ -240,7 +240,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 					"route name found")
 
 				l = ls[1]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				// listener rejects route from different namespace as attachment policy is Same
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, "testnamespace/udproute-testnamespace", store.GetObjectKey(rs[0]),
@@ -331,7 +331,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 
 				l := ls[0]
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 
 				// listener accepts only one route: attachment policy is Selector
 				assert.Len(t, rs, 1, "route found")
@@ -339,14 +339,14 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 					"route name found")
 
 				l = ls[1]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				// listener rejects route from different namespace as attachment policy is Same
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, "testnamespace/udproute-testnamespace", store.GetObjectKey(rs[0]),
 					"route name found")
 
 				l = ls[2]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				// listener accepts only one route: attachment policy is Selector
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, "dummy-namespace/udproute-dummy-namespace", store.GetObjectKey(rs[0]),
@@ -382,7 +382,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-correct-listener-name"),
 					store.GetObjectKey(rs[0]), "route name found")
@@ -417,7 +417,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 0, "route not found")
 			},
 		},
@@ -463,7 +463,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-correct-listener-name"),
 					store.GetObjectKey(rs[0]), "route name found")
@@ -505,7 +505,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 
 				l := ls[0]
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 
 				assert.Len(t, rs, 2, "route found")
 				keys := []string{store.GetObjectKey(rs[0]), store.GetObjectKey(rs[1])}
@@ -516,7 +516,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 					"route name found")
 
 				l = ls[1]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, fmt.Sprintf("%s/%s", testutils.TestNsName, "udproute-namespace-correct-name-2"),
 					store.GetObjectKey(rs[0]), "route name found")
@@ -579,7 +579,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 
 				l := ls[0]
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 
 				// gw accepts route from other namespace as attachment policy is All
 				assert.Len(t, rs, 1, "route found")
@@ -588,7 +588,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 					"route found")
 
 				l = ls[1]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				// gw rejects route from other namespace as attachment policy is Same
 				assert.Len(t, rs, 0, "route found")
 			},
@@ -687,7 +687,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 
 				l := ls[0]
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 
 				// gw accepts route from other namespace as attachment policy is All
 				assert.Len(t, rs, 1, "route found")
@@ -695,12 +695,12 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 					store.GetObjectKey(rs[0]), "route found")
 
 				l = ls[1]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				// does not match sectionname
 				assert.Len(t, rs, 0, "route found")
 
 				l = ls[2]
-				rs = r.getUDPRoutes4Listener(gw, &l)
+				rs = r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				assert.Equal(t, "testnamespace/udproute-testnamespace",
 					store.GetObjectKey(rs[0]), "route name found")
@@ -726,7 +726,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				ro := rs[0]
 
@@ -802,7 +802,7 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				ls := gw.Spec.Listeners
 				l := ls[0]
 
-				rs := r.getUDPRoutes4Listener(gw, &l)
+				rs := r.getRoutes4Listener(gw, &l)
 				assert.Len(t, rs, 1, "route found")
 				ro := rs[0]
 
@@ -1239,5 +1239,170 @@ func TestRenderUDPRouteUtil(t *testing.T) {
 				assert.Equal(t, "BackendNotFound", d.Reason, "reason")
 			},
 		},
+		{
+			name: "TCPRoute and TLSRoute attach to listeners of the matching protocol",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				gw.Spec.Listeners = append(gw.Spec.Listeners, gwapiv1.Listener{
+					Name:     gwapiv1.SectionName("gateway-1-listener-tls"),
+					Port:     gwapiv1.PortNumber(3),
+					Protocol: gwapiv1.ProtocolType("TURN-TLS"),
+				})
+				c.gws = []gwapiv1.Gateway{*gw}
+
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.ParentRefs[0].SectionName = nil
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+
+				tcp := udp.DeepCopy()
+				tcp.SetName("tcproute-ok")
+				c.rsTCP = []stnrgwv1.UDPRoute{*tcp}
+
+				tls := udp.DeepCopy()
+				tls.SetName("tlsroute-ok")
+				c.rsTLS = []stnrgwv1.UDPRoute{*tls}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+
+				ls := gw.Spec.Listeners
+				assert.Len(t, ls, 3, "listeners")
+
+				keys := func(rs []*stnrgwv1.UDPRoute) []string {
+					ret := []string{}
+					for _, ro := range rs {
+						ret = append(ret, store.GetObjectKey(ro))
+					}
+					return ret
+				}
+
+				rs := r.getRoutes4Listener(gw, &ls[0])
+				assert.Equal(t, []string{"testnamespace/udproute-ok"}, keys(rs), "udp listener")
+
+				rs = r.getRoutes4Listener(gw, &ls[1])
+				assert.ElementsMatch(t, []string{"testnamespace/udproute-ok",
+					"testnamespace/tcproute-ok"}, keys(rs), "tcp listener")
+
+				rs = r.getRoutes4Listener(gw, &ls[2])
+				assert.ElementsMatch(t, []string{"testnamespace/udproute-ok",
+					"testnamespace/tlsroute-ok"}, keys(rs), "tls listener")
+
+				group, kind := getRouteGroupKind(store.TCPRoutes.GetAll()[0])
+				assert.Equal(t, gwapiv1.GroupName, group, "tcproute group")
+				assert.Equal(t, "TCPRoute", kind, "tcproute kind")
+				group, kind = getRouteGroupKind(store.TLSRoutes.GetAll()[0])
+				assert.Equal(t, gwapiv1.GroupName, group, "tlsroute group")
+				assert.Equal(t, "TLSRoute", kind, "tlsroute kind")
+				group, kind = getRouteGroupKind(store.UDPRoutes.GetAll()[0])
+				assert.Equal(t, stnrgwv1.GroupVersion.Group, group, "udproute group")
+				assert.Equal(t, "UDPRoute", kind, "udproute kind")
+			},
+		},
+		{
+			name: "listener allowed route kinds",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				gw := testutils.TestGw.DeepCopy()
+				stnrGroup := gwapiv1.Group(stnrgwv1.GroupVersion.Group)
+				gw.Spec.Listeners[0].AllowedRoutes = &gwapiv1.AllowedRoutes{
+					Kinds: []gwapiv1.RouteGroupKind{{Group: &stnrGroup, Kind: "UDPRoute"}},
+				}
+				gw.Spec.Listeners[1].AllowedRoutes = &gwapiv1.AllowedRoutes{
+					Kinds: []gwapiv1.RouteGroupKind{{Kind: "TCPRoute"}},
+				}
+				c.gws = []gwapiv1.Gateway{*gw}
+
+				udp := testutils.TestUDPRoute.DeepCopy()
+				udp.Spec.ParentRefs[0].SectionName = nil
+				c.rs = []stnrgwv1.UDPRoute{*udp}
+
+				udpV1A2 := udp.DeepCopy()
+				udpV1A2.SetName("udproute-v1a2")
+				c.rsV1A2 = []stnrgwv1.UDPRoute{*udpV1A2}
+
+				tcp := udp.DeepCopy()
+				tcp.SetName("tcproute-ok")
+				c.rsTCP = []stnrgwv1.UDPRoute{*tcp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+				ls := gw.Spec.Listeners
+
+				// the Gateway API UDPRoute is not allowed on the UDP listener
+				rs := r.getRoutes4Listener(gw, &ls[0])
+				assert.Len(t, rs, 1, "udp listener")
+				assert.Equal(t, "testnamespace/udproute-ok", store.GetObjectKey(rs[0]), "udp listener")
+
+				// UDPRoutes are not allowed on the TCP listener
+				rs = r.getRoutes4Listener(gw, &ls[1])
+				assert.Len(t, rs, 1, "tcp listener")
+				assert.Equal(t, "testnamespace/tcproute-ok", store.GetObjectKey(rs[0]), "tcp listener")
+			},
+		},
+		{
+			name: "TCPRoute masked by UDPRoute - status",
+			cls:  []gwapiv1.GatewayClass{testutils.TestGwClass},
+			cfs:  []stnrgwv1.GatewayConfig{testutils.TestGwConfig},
+			gws:  []gwapiv1.Gateway{testutils.TestGw},
+			rs:   []stnrgwv1.UDPRoute{testutils.TestUDPRoute},
+			svcs: []corev1.Service{testutils.TestSvc},
+			prep: func(c *renderTestConfig) {
+				tcp := testutils.TestUDPRoute.DeepCopy()
+				tcp.Spec.ParentRefs[0].SectionName = nil
+				c.rsTCP = []stnrgwv1.UDPRoute{*tcp}
+			},
+			tester: func(t *testing.T, r *renderer) {
+				gc, err := r.getGatewayClass()
+				assert.NoError(t, err, "gw-class found")
+				c := &RenderContext{gc: gc, log: log}
+
+				gws := r.getGateways4Class(c)
+				assert.Len(t, gws, 1, "gw found")
+				gw := gws[0]
+				ls := gw.Spec.Listeners
+
+				// the TCPRoute is masked, the UDPRoute attaches to the UDP listener only
+				rs := r.getRoutes4Listener(gw, &ls[1])
+				assert.Len(t, rs, 0, "tcp listener")
+				assert.Len(t, r.allRoutes(), 1, "all routes")
+
+				ro := store.TCPRoutes.GetAll()[0]
+				assert.True(t, isRouteMasked(ro), "masked")
+
+				initRouteStatus(ro)
+				p := ro.Spec.ParentRefs[0]
+				exists, accepted := r.isParentAcceptingRoute(ro, &p, "")
+				setRouteConditionStatus(ro, &p, config.ControllerName, exists, accepted, nil)
+
+				assert.Len(t, ro.Status.Parents, 1, "parent status len")
+				d := meta.FindStatusCondition(ro.Status.Parents[0].Conditions,
+					string(gwapiv1.RouteConditionAccepted))
+				assert.NotNil(t, d, "accepted found")
+				assert.Equal(t, metav1.ConditionFalse, d.Status, "status")
+				assert.Equal(t, string(gwapiv1.RouteReasonPending), d.Reason, "reason")
+				assert.Contains(t, d.Message, "TCPRoute masked", "message")
+			},
+		},
 	})
 }
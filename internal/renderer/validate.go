@@ -0,0 +1,147 @@
+package renderer
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	stnrconfv1 "github.com/l7mp/stunner/pkg/apis/v1"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// The validators below run the same checks the render pipeline performs, so that invalid objects
+// can be rejected at admission time instead of failing later during rendering. Checks that need
+// other objects (e.g., the Secret referenced by an authRef) are left to the renderer.
+
+// maxInterfaceNameLen is the maximum length of a Linux network interface name (IFNAMSIZ-1).
+const maxInterfaceNameLen = 15
+
+// ValidateGatewayConfig checks the authentication settings of a GatewayConfig.
+func ValidateGatewayConfig(gwConf *stnrgwv1.GatewayConfig) field.ErrorList {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	// external auth ref overrides inline refs
+	if ref := gwConf.Spec.AuthRef; ref != nil {
+		if _, err := getSecretNameFromRef(ref, gwConf.GetNamespace()); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("authRef"), dumpSecretRef(ref,
+				gwConf.GetNamespace()), "authRef must point to a Secret"))
+		}
+		return errs
+	}
+
+	if _, err := getInlineAuthConfig(gwConf); err != nil {
+		switch {
+		case IsCriticalError(err, InvalidAuthType):
+			errs = append(errs, field.Invalid(spec.Child("authType"), *gwConf.Spec.AuthType,
+				err.Error()))
+		case IsCriticalError(err, InvalidUsernamePassword):
+			if gwConf.Spec.Username == nil {
+				errs = append(errs, field.Required(spec.Child("username"), err.Error()))
+			}
+			if gwConf.Spec.Password == nil {
+				errs = append(errs, field.Required(spec.Child("password"), err.Error()))
+			}
+		case IsCriticalError(err, InvalidSharedSecret):
+			errs = append(errs, field.Required(spec.Child("sharedSecret"), err.Error()))
+		default:
+			errs = append(errs, field.Invalid(spec, "", err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// ValidateDataplane checks the offload settings of a Dataplane.
+func ValidateDataplane(dp *stnrgwv1.Dataplane) field.ErrorList {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	for i, intf := range dp.Spec.OffloadInterfaces {
+		if err := validateInterfaceName(intf); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("offloadInterfaces").Index(i), intf,
+				err.Error()))
+		}
+	}
+
+	admin := stnrconfv1.AdminConfig{
+		OffloadEngine:     dp.Spec.OffloadEngine,
+		OffloadInterfaces: dp.Spec.OffloadInterfaces,
+	}
+	if err := admin.Validate(); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("offloadEngine"), dp.Spec.OffloadEngine,
+			err.Error()))
+	}
+
+	return errs
+}
+
+// ValidateUDPRoute checks the port ranges of the backends of a UDPRoute.
+func ValidateUDPRoute(ro *stnrgwv1.UDPRoute) field.ErrorList {
+	errs := field.ErrorList{}
+	rules := field.NewPath("spec", "rules")
+
+	for i := range ro.Spec.Rules {
+		for j := range ro.Spec.Rules[i].BackendRefs {
+			b := &ro.Spec.Rules[i].BackendRefs[j]
+			if err := validatePortRange(b); err != nil {
+				errs = append(errs, field.Invalid(rules.Index(i).Child("backendRefs").Index(j),
+					dumpPortRange(b), err.Error()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateStaticService checks that the prefixes of a StaticService are valid IP addresses or
+// CIDR prefixes.
+func ValidateStaticService(ssvc *stnrgwv1.StaticService) field.ErrorList {
+	errs := field.ErrorList{}
+	prefixes := field.NewPath("spec", "prefixes")
+
+	for i, p := range ssvc.Spec.Prefixes {
+		if net.ParseIP(p) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(p); err != nil {
+			errs = append(errs, field.Invalid(prefixes.Index(i), p,
+				"must be a valid IP address or CIDR prefix"))
+		}
+	}
+
+	return errs
+}
+
+func validateInterfaceName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid interface name %q", name)
+	}
+
+	if len(name) > maxInterfaceNameLen {
+		return fmt.Errorf("interface name longer than %d characters", maxInterfaceNameLen)
+	}
+
+	for _, c := range name {
+		if c == '/' || c == ':' || c <= ' ' || c > '~' {
+			return fmt.Errorf("invalid character %q in interface name", c)
+		}
+	}
+
+	return nil
+}
+
+// dumpPortRange is a helper to create a human-readable dump from the port range of a backend.
+func dumpPortRange(b *stnrgwv1.BackendRef) string {
+	port, endPort := "<nil>", "<nil>"
+	if b.Port != nil {
+		port = fmt.Sprintf("%d", *b.Port)
+	}
+	if b.EndPort != nil {
+		endPort = fmt.Sprintf("%d", *b.EndPort)
+	}
+
+	return fmt.Sprintf("{Port: %s, EndPort: %s}", port, endPort)
+}
@@ -0,0 +1,145 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+func TestValidateGatewayConfig(t *testing.T) {
+	authType := func(s string) *string { return &s }
+	cases := []struct {
+		name  string
+		prep  func(*stnrgwv1.GatewayConfig)
+		field string
+	}{
+		{name: "plaintext ok", prep: func(*stnrgwv1.GatewayConfig) {}},
+		{name: "plaintext no password", field: "spec.password",
+			prep: func(w *stnrgwv1.GatewayConfig) { w.Spec.Password = nil }},
+		{name: "plaintext no username", field: "spec.username",
+			prep: func(w *stnrgwv1.GatewayConfig) { w.Spec.Username = nil }},
+		{name: "longterm ok", prep: func(w *stnrgwv1.GatewayConfig) {
+			w.Spec.AuthType = authType("ephemeral")
+			w.Spec.SharedSecret = authType("secret")
+		}},
+		{name: "longterm no shared secret", field: "spec.sharedSecret",
+			prep: func(w *stnrgwv1.GatewayConfig) { w.Spec.AuthType = authType("longterm") }},
+		{name: "invalid auth type", field: "spec.authType",
+			prep: func(w *stnrgwv1.GatewayConfig) { w.Spec.AuthType = authType("dummy") }},
+		{name: "authref ok", prep: func(w *stnrgwv1.GatewayConfig) {
+			w.Spec.Password = nil
+			w.Spec.AuthRef = &gwapiv1.SecretObjectReference{Name: "dummy"}
+		}},
+		{name: "authref invalid kind", field: "spec.authRef", prep: func(w *stnrgwv1.GatewayConfig) {
+			kind := gwapiv1.Kind("ConfigMap")
+			w.Spec.AuthRef = &gwapiv1.SecretObjectReference{Name: "dummy", Kind: &kind}
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := testutils.TestGwConfig.DeepCopy()
+			tc.prep(w)
+			errs := ValidateGatewayConfig(w)
+			if tc.field == "" {
+				assert.Len(t, errs, 0, "no errors")
+				return
+			}
+			assert.Len(t, errs, 1, "error num")
+			assert.Equal(t, tc.field, errs[0].Field, "field")
+		})
+	}
+}
+
+func TestValidateDataplane(t *testing.T) {
+	cases := []struct {
+		name  string
+		intfs []string
+		field string
+	}{
+		{name: "no interfaces ok"},
+		{name: "interfaces ok", intfs: []string{"eth0", "ens5", "veth-1.100"}},
+		{name: "empty name", intfs: []string{"eth0", ""}, field: "spec.offloadInterfaces[1]"},
+		{name: "slash in name", intfs: []string{"eth/0"}, field: "spec.offloadInterfaces[0]"},
+		{name: "whitespace in name", intfs: []string{"eth 0"}, field: "spec.offloadInterfaces[0]"},
+		{name: "name too long", intfs: []string{"a-very-long-interface"}, field: "spec.offloadInterfaces[0]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := testutils.TestDataplane.DeepCopy()
+			dp.Spec.OffloadEngine = "XDP"
+			dp.Spec.OffloadInterfaces = tc.intfs
+			errs := ValidateDataplane(dp)
+			if tc.field == "" {
+				assert.Len(t, errs, 0, "no errors")
+				return
+			}
+			assert.Len(t, errs, 1, "error num")
+			assert.Equal(t, tc.field, errs[0].Field, "field")
+		})
+	}
+}
+
+func TestValidateUDPRoute(t *testing.T) {
+	port := func(p int) *gwapiv1.PortNumber { n := gwapiv1.PortNumber(p); return &n }
+	cases := []struct {
+		name          string
+		port, endPort *gwapiv1.PortNumber
+		valid         bool
+	}{
+		{name: "no port ok", valid: true},
+		{name: "port ok", port: port(1), valid: true},
+		{name: "port range ok", port: port(1), endPort: port(10), valid: true},
+		{name: "single port range ok", port: port(10), endPort: port(10), valid: true},
+		{name: "endPort < port", port: port(10), endPort: port(1)},
+		{name: "port out of range", port: port(65536)},
+		{name: "endPort out of range", port: port(1), endPort: port(0)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ro := testutils.TestUDPRoute.DeepCopy()
+			ro.Spec.Rules[0].BackendRefs[0].Port = tc.port
+			ro.Spec.Rules[0].BackendRefs[0].EndPort = tc.endPort
+			errs := ValidateUDPRoute(ro)
+			if tc.valid {
+				assert.Len(t, errs, 0, "no errors")
+				return
+			}
+			assert.Len(t, errs, 1, "error num")
+			assert.Equal(t, "spec.rules[0].backendRefs[0]", errs[0].Field, "field")
+		})
+	}
+}
+
+func TestValidateStaticService(t *testing.T) {
+	cases := []struct {
+		name     string
+		prefixes []string
+		field    string
+	}{
+		{name: "addresses ok", prefixes: []string{"10.11.12.13", "fd00::1"}},
+		{name: "prefixes ok", prefixes: []string{"0.0.0.0/1", "128.0.0.0/1", "fd00::/8"}},
+		{name: "invalid prefix len", prefixes: []string{"10.0.0.0/8", "10.0.0.0/33"},
+			field: "spec.prefixes[1]"},
+		{name: "invalid address", prefixes: []string{"10.0.0.256"}, field: "spec.prefixes[0]"},
+		{name: "hostname", prefixes: []string{"example.com"}, field: "spec.prefixes[0]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ssvc := testutils.TestStaticSvc.DeepCopy()
+			ssvc.Spec.Prefixes = tc.prefixes
+			errs := ValidateStaticService(ssvc)
+			if tc.field == "" {
+				assert.Len(t, errs, 0, "no errors")
+				return
+			}
+			assert.Len(t, errs, 1, "error num")
+			assert.Equal(t, tc.field, errs[0].Field, "field")
+		})
+	}
+}
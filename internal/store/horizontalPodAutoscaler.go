@@ -0,0 +1,61 @@
+package store
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var HorizontalPodAutoscalers = NewHorizontalPodAutoscalerStore()
+
+type HorizontalPodAutoscalerStore struct {
+	Store
+}
+
+func NewHorizontalPodAutoscalerStore() *HorizontalPodAutoscalerStore {
+	return &HorizontalPodAutoscalerStore{
+		Store: NewStore(),
+	}
+}
+
+// GetAll returns all HorizontalPodAutoscaler objects from the global storage
+func (s *HorizontalPodAutoscalerStore) GetAll() []*autoscalingv2.HorizontalPodAutoscaler {
+	ret := make([]*autoscalingv2.HorizontalPodAutoscaler, 0)
+
+	objects := s.Objects()
+	for i := range objects {
+		r, ok := objects[i].(*autoscalingv2.HorizontalPodAutoscaler)
+		if !ok {
+			// this is critical: throw up hands and die
+			panic("access to an invalid object in the global HorizontalPodAutoscalerStore")
+		}
+
+		ret = append(ret, r)
+	}
+
+	return ret
+}
+
+// GetObject returns a named HorizontalPodAutoscaler object from the global storage
+func (s *HorizontalPodAutoscalerStore) GetObject(nsName types.NamespacedName) *autoscalingv2.HorizontalPodAutoscaler {
+	o := s.Get(nsName)
+	if o == nil {
+		return nil
+	}
+
+	r, ok := o.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		// this is critical: throw up hands and die
+		panic("access to an invalid object in the global HorizontalPodAutoscalerStore")
+	}
+
+	return r
+}
+
+func (s *HorizontalPodAutoscalerStore) DeepCopy() *HorizontalPodAutoscalerStore {
+	ret := NewHorizontalPodAutoscalerStore()
+	for _, o := range s.GetAll() {
+		ret.Upsert(o.DeepCopy())
+	}
+	return ret
+}
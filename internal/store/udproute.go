@@ -9,6 +9,11 @@ import (
 var UDPRoutes = NewUDPRouteStore()
 var UDPRoutesV1A2 = NewUDPRouteStore()
 
+// TCPRoutes and TLSRoutes hold Gateway API TCPRoutes and TLSRoutes, converted to the canonical
+// UDPRoute representation
+var TCPRoutes = NewUDPRouteStore()
+var TLSRoutes = NewUDPRouteStore()
+
 type UDPRouteStore struct {
 	Store
 }
@@ -3,6 +3,8 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -97,6 +99,18 @@ func DumpObject(o client.Object) string {
 		} else {
 			output = string(json)
 		}
+	case *gwapiv1a2.TCPRoute:
+		if json, err := json.Marshal(strip(ro)); err != nil {
+			fmt.Printf("---------------ERROR-----------: %s\n", err)
+		} else {
+			output = string(json)
+		}
+	case *gwapiv1a2.TLSRoute:
+		if json, err := json.Marshal(strip(ro)); err != nil {
+			fmt.Printf("---------------ERROR-----------: %s\n", err)
+		} else {
+			output = string(json)
+		}
 	case *corev1.Service:
 		if json, err := json.Marshal(strip(ro)); err != nil {
 			fmt.Printf("---------------ERROR-----------: %s\n", err)
@@ -110,7 +124,7 @@ func DumpObject(o client.Object) string {
 			output = string(json)
 		}
 	case *stnrgwv1.GatewayConfig:
-		if json, err := json.Marshal(strip(ro)); err != nil {
+		if json, err := json.Marshal(strip(stripGwConf(ro))); err != nil {
 			fmt.Printf("---------------ERROR-----------: %s\n", err)
 		} else {
 			output = string(json)
@@ -199,14 +213,57 @@ func stripCM(cm *corev1.ConfigMap) *corev1.ConfigMap {
 		return cm
 	}
 
-	if _, ok := conf.Auth.Credentials["username"]; ok {
-		conf.Auth.Credentials["username"] = "-SECRET-"
+	sc, err := json.Marshal(RedactConfig(&conf))
+	if err != nil {
+		return cm
+	}
+
+	cm.Data = map[string]string{
+		opdefault.DefaultStunnerdConfigfileName: string(sc),
+	}
+
+	return cm
+}
+
+func stripGwConf(gwConf *stnrgwv1.GatewayConfig) *stnrgwv1.GatewayConfig {
+	spec := &gwConf.Spec
+	for _, s := range []*string{spec.Username, spec.Password, spec.SharedSecret} {
+		if s != nil {
+			*s = "-SECRET-"
+		}
+	}
+
+	return gwConf
+}
+
+// RedactObject returns a copy of an object with the managed fields and the last-applied
+// annotation stripped and the credentials in stunnerd ConfigMaps and GatewayConfigs masked, the
+// same way as DumpObject.
+func RedactObject(o client.Object) client.Object {
+	ro, ok := o.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
 	}
-	if _, ok := conf.Auth.Credentials["password"]; ok {
-		conf.Auth.Credentials["password"] = "-SECRET-"
+
+	switch o := ro.(type) {
+	case *corev1.ConfigMap:
+		ro = stripCM(o)
+	case *stnrgwv1.GatewayConfig:
+		ro = stripGwConf(o)
 	}
-	if _, ok := conf.Auth.Credentials["secret"]; ok {
-		conf.Auth.Credentials["secret"] = "-SECRET-"
+
+	return strip(ro)
+}
+
+// RedactConfig returns a copy of a stunnerd config with the credentials and the TLS keys masked.
+func RedactConfig(c *stnrconfv1.StunnerConfig) *stnrconfv1.StunnerConfig {
+	conf := &stnrconfv1.StunnerConfig{}
+	c.DeepCopyInto(conf)
+
+	for _, key := range []string{"username", "password", "secret"} {
+		if _, ok := conf.Auth.Credentials[key]; ok {
+			conf.Auth.Credentials[key] = "-SECRET-"
+		}
 	}
 
 	for i := range conf.Listeners {
@@ -218,16 +275,7 @@ func stripCM(cm *corev1.ConfigMap) *corev1.ConfigMap {
 		}
 	}
 
-	sc, err := json.Marshal(conf)
-	if err != nil {
-		return cm
-	}
-
-	cm.Data = map[string]string{
-		opdefault.DefaultStunnerdConfigfileName: string(sc),
-	}
-
-	return cm
+	return conf
 }
 
 // IsReferenceService returns true of the provided BackendRef points to a Service.
@@ -288,6 +336,100 @@ func MergeMetadata(a, b map[string]string) map[string]string {
 	return ret
 }
 
+// LastAppliedMetadata is the set of label and annotation keys the operator applied to an object
+// during the last update, stored in the last-applied-metadata annotation of the object.
+type LastAppliedMetadata struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// GetLastAppliedMetadata returns the label and annotation keys the operator applied to an object
+// during the last update. Returns an empty set if the object does not record the applied keys.
+func GetLastAppliedMetadata(o client.Object) LastAppliedMetadata {
+	ret := LastAppliedMetadata{}
+	v, ok := o.GetAnnotations()[opdefault.LastAppliedMetadataAnnotationKey]
+	if !ok {
+		return ret
+	}
+	if err := json.Unmarshal([]byte(v), &ret); err != nil {
+		return LastAppliedMetadata{}
+	}
+	return ret
+}
+
+// SetLastAppliedMetadata records the keys of the labels and annotations applied by the operator
+// in the last-applied-metadata annotation of an object.
+func SetLastAppliedMetadata(o client.Object, labels, annotations map[string]string) {
+	last := LastAppliedMetadata{
+		Labels:      slices.Sorted(maps.Keys(labels)),
+		Annotations: slices.Sorted(maps.Keys(annotations)),
+	}
+	last.Annotations = slices.DeleteFunc(last.Annotations, func(k string) bool {
+		return k == opdefault.LastAppliedMetadataAnnotationKey
+	})
+
+	v, err := json.Marshal(last)
+	if err != nil {
+		return
+	}
+
+	as := o.GetAnnotations()
+	if as == nil {
+		as = map[string]string{}
+	}
+	as[opdefault.LastAppliedMetadataAnnotationKey] = string(v)
+	o.SetAnnotations(as)
+}
+
+// GetLastAppliedPodTemplateFields returns the pod template fields set by the pod template patch
+// of a Dataplane during the last update of a dataplane resource. Returns nil if the object does
+// not record the patched fields.
+func GetLastAppliedPodTemplateFields(o client.Object) []string {
+	v, ok := o.GetAnnotations()[opdefault.LastAppliedPodTemplatePatchAnnotationKey]
+	if !ok {
+		return nil
+	}
+	ret := []string{}
+	if err := json.Unmarshal([]byte(v), &ret); err != nil {
+		return nil
+	}
+	return ret
+}
+
+// SetLastAppliedPodTemplateFields records the pod template fields set by the pod template patch
+// of a Dataplane in the last-applied-pod-template-patch annotation of a dataplane resource.
+func SetLastAppliedPodTemplateFields(o client.Object, fields []string) {
+	v, err := json.Marshal(slices.Sorted(slices.Values(fields)))
+	if err != nil {
+		return
+	}
+
+	as := o.GetAnnotations()
+	if as == nil {
+		as = map[string]string{}
+	}
+	as[opdefault.LastAppliedPodTemplatePatchAnnotationKey] = string(v)
+	o.SetAnnotations(as)
+}
+
+// PruneMetadata removes the labels or annotations from current that were applied by the operator
+// earlier, as listed in lastApplied, but are no longer set in desired. Labels and annotations not
+// applied by the operator, e.g., the ones added by the cloud provider, are left alone. Returns a
+// new map to avoid unintentional sharing.
+func PruneMetadata(current, desired map[string]string, lastApplied []string) map[string]string {
+	ret := maps.Clone(current)
+	if ret == nil {
+		ret = map[string]string{}
+	}
+	for _, k := range lastApplied {
+		if _, ok := desired[k]; !ok {
+			delete(ret, k)
+		}
+	}
+
+	return ret
+}
+
 // FilterLabels returns a copy of in with any key in filter removed. Each removed key is logged
 // at V(1) so operators can confirm the filter is firing. The input map is not mutated. If either
 // in or filter is empty, the input is returned unchanged.
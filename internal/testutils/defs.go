@@ -11,6 +11,7 @@ import (
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 
@@ -318,6 +319,25 @@ var TestStaticSvc = stnrgwv1.StaticService{
 	},
 }
 
+// ReferenceGrant allowing UDPRoutes in "testnamespace" to refer to Services in "dummy-ns"
+var TestReferenceGrant = gwapiv1b1.ReferenceGrant{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "dummy-ns",
+		Name:      "testreferencegrant-ok",
+	},
+	Spec: gwapiv1b1.ReferenceGrantSpec{
+		From: []gwapiv1b1.ReferenceGrantFrom{{
+			Group:     gwapiv1.Group(stnrgwv1.GroupVersion.Group),
+			Kind:      "UDPRoute",
+			Namespace: TestNsName,
+		}},
+		To: []gwapiv1b1.ReferenceGrantTo{{
+			Group: gwapiv1.Group(corev1.GroupName),
+			Kind:  "Service",
+		}},
+	},
+}
+
 // Dataplane
 var TestDataplane = stnrgwv1.Dataplane{
 	ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,121 @@
+// Package tracing sets up OpenTelemetry tracing for the reconcile, render, update and config
+// discovery pipeline of the operator.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer used by the operator.
+	TracerName = "github.com/l7mp/stunner-gateway-operator"
+
+	// ServiceName is the service name reported in the traces.
+	ServiceName = "stunner-gateway-operator"
+)
+
+// Config is the OTLP exporter config.
+type Config struct {
+	// Endpoint is the OTLP gRPC collector endpoint, either as a URL (e.g.,
+	// "https://collector:4317", the format of OTEL_EXPORTER_OTLP_ENDPOINT) or as host:port.
+	// Tracing is disabled if empty.
+	Endpoint string
+	// Insecure disables client transport security for the exporter.
+	Insecure bool
+	// SampleRatio is the ratio of the traces to sample, between 0 and 1.
+	SampleRatio float64
+	// Version is the version of the operator reported in the traces.
+	Version string
+	Logger  logr.Logger
+}
+
+// Setup installs a global tracer provider that exports the traces via OTLP. Returns a function
+// that flushes and stops the exporter. If no endpoint is configured then tracing is disabled,
+// i.e., the default no-op tracer provider is used.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	log := cfg.Logger.WithName("tracing")
+
+	if cfg.Endpoint == "" {
+		log.V(1).Info("Tracing disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid trace sample ratio %f (must be between 0 and 1)",
+			cfg.SampleRatio)
+	}
+
+	// the OTel spec defines the endpoint as a URL, an "http" scheme implies an insecure
+	// connection
+	opts := []otlptracegrpc.Option{}
+	if strings.Contains(cfg.Endpoint, "://") {
+		if _, err := url.Parse(cfg.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid OTLP endpoint URL %q: %w", cfg.Endpoint, err)
+		}
+		opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+	))
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, fmt.Errorf("cannot create trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	log.Info("Tracing enabled", "endpoint", cfg.Endpoint, "insecure", cfg.Insecure,
+		"sample-ratio", cfg.SampleRatio)
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of the operator from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start starts a new span using the tracer of the operator.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// EndSpan records an error, if any, on a span and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	// no endpoint: tracing disabled
+	shutdown, err := Setup(context.Background(), Config{Logger: logr.Discard()})
+	require.NoError(t, err, "setup")
+	assert.NoError(t, shutdown(context.Background()), "shutdown")
+	assert.Equal(t, prev, otel.GetTracerProvider(), "tracer provider unchanged")
+
+	// invalid sample ratio
+	_, err = Setup(context.Background(), Config{Endpoint: "localhost:4317", SampleRatio: 2,
+		Logger: logr.Discard()})
+	assert.Error(t, err, "invalid sample ratio")
+
+	// the exporter connects lazily
+	shutdown, err = Setup(context.Background(), Config{Endpoint: "localhost:4317", Insecure: true,
+		SampleRatio: 1, Logger: logr.Discard()})
+	require.NoError(t, err, "setup")
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	assert.True(t, ok, "sdk tracer provider")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = shutdown(ctx)
+
+	// the endpoint may be given as a URL, as in OTEL_EXPORTER_OTLP_ENDPOINT
+	shutdown, err = Setup(context.Background(), Config{Endpoint: "http://localhost:4317",
+		SampleRatio: 1, Logger: logr.Discard()})
+	require.NoError(t, err, "setup with endpoint URL")
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_ = shutdown(ctx)
+
+	// invalid endpoint URL
+	_, err = Setup(context.Background(), Config{Endpoint: "http://local host:4317",
+		SampleRatio: 1, Logger: logr.Discard()})
+	assert.Error(t, err, "invalid endpoint URL")
+}
+
+func TestEndSpan(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, span := Start(ctx, "ok")
+	EndSpan(span, nil)
+	_, span = Start(ctx, "error")
+	EndSpan(span, errors.New("dummy"))
+	parent.End()
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 3, "spans")
+	assert.Equal(t, "ok", spans[0].Name, "name")
+	assert.Equal(t, codes.Unset, spans[0].Status.Code, "status")
+	assert.Equal(t, "error", spans[1].Name, "name")
+	assert.Equal(t, codes.Error, spans[1].Status.Code, "status")
+	assert.Equal(t, "dummy", spans[1].Status.Description, "status description")
+	assert.Len(t, spans[1].Events, 1, "error recorded")
+	for _, s := range spans[:2] {
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent.SpanID(), "parent")
+	}
+}
@@ -1,31 +1,57 @@
 package updater
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/lens"
+	"github.com/l7mp/stunner-gateway-operator/internal/recorder"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
-func (u *Updater) upsertResourceObject(desired client.Object, gen int) (ctrlutil.OperationResult, error) {
+// errApplyConflict is returned when a server-side apply conflicts with another field manager.
+var errApplyConflict = errors.New("server-side apply conflict")
+
+func (u *Updater) upsertResourceObject(ctx context.Context, desired client.Object, gen int) (op ctrlutil.OperationResult, err error) {
+	kind := objectKind(desired)
+	ctx, span := startSpan(ctx, "Upsert", kind, desired)
+	defer func() {
+		span.SetAttributes(attribute.String("result", string(op)))
+		tracing.EndSpan(span, err)
+	}()
+
 	l, err := lens.New(desired)
 	if err != nil {
 		return ctrlutil.OperationResultNone, err
 	}
 
-	kind := objectKind(desired)
 	prefix := "spec." + kind
 	resource := store.GetObjectKey(desired)
 	u.incCounter(prefix + ".attempt")
@@ -37,7 +63,9 @@ func (u *Updater) upsertResourceObject(desired client.Object, gen int) (ctrlutil
 	}
 
 	cli := u.manager.GetClient()
-	if err := cli.Get(u.ctx, client.ObjectKeyFromObject(desired), current); err == nil {
+	found := false
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), current); err == nil {
+		found = true
 		if l.EqualResource(current) {
 			u.incCounter(prefix + ".suppressed")
 			u.log.V(2).Info(fmt.Sprintf("%s unchanged, skipping upsert", kind),
@@ -49,12 +77,30 @@ func (u *Updater) upsertResourceObject(desired client.Object, gen int) (ctrlutil
 		return ctrlutil.OperationResultNone, fmt.Errorf("cannot get %s %q: %w", kind, resource, err)
 	}
 
-	op, err := ctrlutil.CreateOrPatch(u.ctx, cli, current, func() error {
-		return l.ApplyToResource(current)
-	})
+	if al, ok := l.(lens.ApplyLens); ok && config.UpdateMode == config.UpdateModeApply {
+		op = ctrlutil.OperationResultCreated
+		if found {
+			op = ctrlutil.OperationResultUpdated
+		}
+		rv := current.GetResourceVersion()
+		current = al.ApplyObject()
+		var applied string
+		applied, err = u.applyObject(ctx, cli, current, prefix, false)
+		if err == nil && found && applied == rv {
+			// the API server does not bump the resource version on a no-op apply
+			op = ctrlutil.OperationResultNone
+		}
+	} else {
+		op, err = ctrlutil.CreateOrPatch(ctx, cli, current, func() error {
+			return l.ApplyToResource(current)
+		})
+	}
 	if err != nil {
 		u.incCounter(prefix + ".error")
-		return ctrlutil.OperationResultNone, fmt.Errorf("cannot upsert %s %q: %w", kind, resource, err)
+		err = fmt.Errorf("cannot upsert %s %q: %w", kind, resource, err)
+		u.recorder.Warning(eventTarget(desired), recorder.ReasonUpdateFailed,
+			recorder.ActionUpdate, err.Error())
+		return ctrlutil.OperationResultNone, err
 	}
 
 	switch op {
@@ -76,13 +122,16 @@ func (u *Updater) upsertResourceObject(desired client.Object, gen int) (ctrlutil
 	return op, nil
 }
 
-func (u *Updater) updateStatusObject(desired client.Object, gen int) error {
+func (u *Updater) updateStatusObject(ctx context.Context, desired client.Object, gen int) (err error) {
+	kind := objectKind(desired)
+	ctx, span := startSpan(ctx, "UpdateStatus", kind, desired)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	l, err := lens.New(desired)
 	if err != nil {
 		return err
 	}
 
-	kind := objectKind(desired)
 	prefix := "status." + kind
 	resource := store.GetObjectKey(desired)
 	u.incCounter(prefix + ".attempt")
@@ -96,10 +145,13 @@ func (u *Updater) updateStatusObject(desired client.Object, gen int) error {
 	cli := u.manager.GetClient()
 	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		u.incCounter(prefix + ".retryPass")
-		if err := cli.Get(u.ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
 			return err
 		}
 
+		// the parent statuses of a route set by other controllers are left alone
+		foreign := splitRouteParents(current)
+
 		if l.EqualStatus(current) {
 			u.incCounter(prefix + ".suppressed")
 			u.log.V(2).Info(fmt.Sprintf("%s status unchanged, skipping update", kind),
@@ -110,9 +162,21 @@ func (u *Updater) updateStatusObject(desired client.Object, gen int) error {
 		if err := l.ApplyToStatus(current); err != nil {
 			return err
 		}
+		splitRouteParents(current)
 
-		if err := cli.Status().Update(u.ctx, current); err != nil {
-			return err
+		// the route parent status list is atomic, so applying only our own entries would
+		// remove the entries of other controllers: fall back to a read-modify-write update
+		if config.UpdateMode == config.UpdateModeApply && len(foreign) == 0 {
+			if _, err := u.applyObject(ctx, cli, current, prefix, true); err != nil {
+				return err
+			}
+		} else {
+			if st := routeStatus(current); st != nil {
+				st.Parents = append(st.Parents, foreign...)
+			}
+			if err := cli.Status().Update(ctx, current); err != nil {
+				return err
+			}
 		}
 
 		u.incCounter(prefix + ".updated")
@@ -123,14 +187,217 @@ func (u *Updater) updateStatusObject(desired client.Object, gen int) error {
 
 	if err != nil {
 		u.incCounter(prefix + ".error")
+		u.recorder.Warning(desired, recorder.ReasonStatusUpdateFailed, recorder.ActionUpdate,
+			fmt.Sprintf("cannot update %s status: %s", kind, err.Error()))
 	}
 
 	return err
 }
 
-func (u *Updater) deleteObject(o client.Object, gen int) error {
-	u.log.V(2).Info("Delete object", "kind", objectKind(o), "resource", store.GetObjectKey(o), "generation", gen)
-	return u.manager.GetClient().Delete(u.ctx, o)
+// applyObject writes an object, or only its status, with server-side apply using the field manager
+// of the operator and returns the resulting resource version. If the conflicting fields were last
+// written by the operator itself, e.g., before switching from patch mode, the apply is retried
+// forcing the ownership of the fields. A conflict with another field manager is counted and
+// returned as an errApplyConflict, which is not retried. A status apply carries the resource version of the object, so a stale status is rejected with a
+// conflict that is returned to the caller.
+func (u *Updater) applyObject(ctx context.Context, cli client.Client, o client.Object, prefix string, status bool) (string, error) {
+	obj, err := newApplyObject(o, cli.Scheme(), status)
+	if err != nil {
+		return "", err
+	}
+	ac := client.ApplyConfigurationFromUnstructured(obj)
+
+	apply := func(force bool) error {
+		if status {
+			opts := []client.SubResourceApplyOption{client.FieldOwner(opdefault.DefaultFieldManager)}
+			if force {
+				opts = append(opts, client.ForceOwnership)
+			}
+			return cli.Status().Apply(ctx, ac, opts...)
+		}
+
+		opts := []client.ApplyOption{client.FieldOwner(opdefault.DefaultFieldManager)}
+		if force {
+			opts = append(opts, client.ForceOwnership)
+		}
+		return cli.Apply(ctx, ac, opts...)
+	}
+
+	err = apply(false)
+	if apierrors.IsConflict(err) && apierrors.HasStatusCause(err, metav1.CauseTypeFieldManagerConflict) {
+		if !ownFieldConflict(err) {
+			// another controller owns a field the operator wants to set: do not fight it
+			u.incCounter(prefix + ".conflict")
+			u.log.Info("Server-side apply conflict with another field manager, skipping",
+				"kind", objectKind(o), "resource", store.GetObjectKey(o), "error", err.Error())
+			return "", fmt.Errorf("%w: %s", errApplyConflict, err.Error())
+		}
+
+		// the fields were last written by the operator in patch mode
+		err = apply(true)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// the apply configuration is updated from the response of the API server
+	return obj.GetResourceVersion(), nil
+}
+
+// ownFieldConflict reports whether all fields in a server-side apply conflict are managed by the
+// operator, either in server-side apply mode or by the updates of the patch mode.
+func ownFieldConflict(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+
+	own := []string{opdefault.DefaultFieldManager, patchFieldManager()}
+	found := false
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		var manager string
+		if _, err := fmt.Sscanf(cause.Message, "conflict with %q", &manager); err != nil ||
+			!slices.Contains(own, manager) {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
+
+// patchFieldManager returns the field manager the API server assigns to the updates of the
+// operator in patch mode, which does not set a field owner.
+func patchFieldManager() string {
+	return strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+}
+
+// newApplyObject converts an object into an unstructured server-side apply configuration. The
+// metadata set by the API server is removed. If status is set, only the status and the resource
+// version of the object are kept, and only the parent statuses of this controller are kept for
+// routes.
+func newApplyObject(o client.Object, scheme *runtime.Scheme, status bool) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(o, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if status && routeStatus(o) != nil {
+		o, _ = o.DeepCopyObject().(client.Object)
+		splitRouteParents(o)
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: obj}
+	if status {
+		u = &unstructured.Unstructured{Object: map[string]any{}}
+		u.SetName(o.GetName())
+		u.SetNamespace(o.GetNamespace())
+		u.SetResourceVersion(o.GetResourceVersion())
+		if st, ok := obj["status"]; ok {
+			u.Object["status"] = st
+		}
+	} else {
+		unstructured.RemoveNestedField(u.Object, "status")
+		for _, f := range []string{"creationTimestamp", "resourceVersion", "uid", "generation",
+			"managedFields", "selfLink", "deletionTimestamp", "deletionGracePeriodSeconds"} {
+			unstructured.RemoveNestedField(u.Object, "metadata", f)
+		}
+	}
+	u.SetGroupVersionKind(gvk)
+
+	return u, nil
+}
+
+// routeStatus returns the status of a route, or nil if the object is not a route.
+func routeStatus(o client.Object) *gwapiv1.RouteStatus {
+	switch ro := o.(type) {
+	case *stnrgwv1.UDPRoute:
+		return &ro.Status.RouteStatus
+	case *gwapiv1a2.UDPRoute:
+		return &ro.Status.RouteStatus
+	case *gwapiv1a2.TCPRoute:
+		return &ro.Status.RouteStatus
+	case *gwapiv1a2.TLSRoute:
+		return &ro.Status.RouteStatus
+	default:
+		return nil
+	}
+}
+
+// splitRouteParents removes the parent statuses set by other controllers from the status of a
+// route and returns them.
+func splitRouteParents(o client.Object) []gwapiv1.RouteParentStatus {
+	st := routeStatus(o)
+	if st == nil {
+		return nil
+	}
+
+	own, foreign := []gwapiv1.RouteParentStatus{}, []gwapiv1.RouteParentStatus{}
+	for _, p := range st.Parents {
+		if string(p.ControllerName) == config.ControllerName {
+			own = append(own, p)
+		} else {
+			foreign = append(foreign, p)
+		}
+	}
+	if len(foreign) == 0 {
+		return nil
+	}
+	st.Parents = own
+
+	return foreign
+}
+
+func (u *Updater) deleteObject(ctx context.Context, o client.Object, gen int) (err error) {
+	kind := objectKind(o)
+	ctx, span := startSpan(ctx, "Delete", kind, o)
+	defer func() {
+		if apierrors.IsNotFound(err) {
+			tracing.EndSpan(span, nil)
+			return
+		}
+		tracing.EndSpan(span, err)
+	}()
+
+	u.log.V(2).Info("Delete object", "kind", kind, "resource", store.GetObjectKey(o), "generation", gen)
+	return u.manager.GetClient().Delete(ctx, o)
+}
+
+// startSpan starts a span for a Kubernetes API call on an object.
+func startSpan(ctx context.Context, op, kind string, o client.Object) (context.Context, trace.Span) {
+	return tracing.Start(ctx, op+" "+kind, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("kind", kind),
+			attribute.String("resource", store.GetObjectKey(o)),
+		))
+}
+
+// eventTarget returns the object to emit the Kubernetes Events for a resource on: the Gateway or
+// the GatewayConfig that owns the resource if any, otherwise the resource itself.
+func eventTarget(o client.Object) client.Object {
+	for _, ref := range o.GetOwnerReferences() {
+		key := types.NamespacedName{Namespace: o.GetNamespace(), Name: ref.Name}
+		switch ref.Kind {
+		case "Gateway":
+			if gw := store.Gateways.GetObject(key); gw != nil {
+				return gw
+			}
+		case "GatewayConfig":
+			if gwConf := store.GatewayConfigs.GetObject(key); gwConf != nil {
+				return gwConf
+			}
+		}
+	}
+
+	return o
 }
 
 func emptyObjectFor(o client.Object) (client.Object, error) {
@@ -145,14 +412,26 @@ func emptyObjectFor(o client.Object) (client.Object, error) {
 		return &appv1.Deployment{ObjectMeta: meta}, nil
 	case *appv1.DaemonSet:
 		return &appv1.DaemonSet{ObjectMeta: meta}, nil
+	case *policyv1.PodDisruptionBudget:
+		return &policyv1.PodDisruptionBudget{ObjectMeta: meta}, nil
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		return &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: meta}, nil
 	case *gwapiv1.GatewayClass:
 		return &gwapiv1.GatewayClass{ObjectMeta: meta}, nil
 	case *gwapiv1.Gateway:
 		return &gwapiv1.Gateway{ObjectMeta: meta}, nil
+	case *stnrgwv1.GatewayConfig:
+		return &stnrgwv1.GatewayConfig{ObjectMeta: meta}, nil
+	case *stnrgwv1.Dataplane:
+		return &stnrgwv1.Dataplane{ObjectMeta: meta}, nil
 	case *stnrgwv1.UDPRoute:
 		return &stnrgwv1.UDPRoute{ObjectMeta: meta}, nil
 	case *gwapiv1a2.UDPRoute:
 		return &gwapiv1a2.UDPRoute{ObjectMeta: meta}, nil
+	case *gwapiv1a2.TCPRoute:
+		return &gwapiv1a2.TCPRoute{ObjectMeta: meta}, nil
+	case *gwapiv1a2.TLSRoute:
+		return &gwapiv1a2.TLSRoute{ObjectMeta: meta}, nil
 	default:
 		return nil, fmt.Errorf("unsupported object type %T", o)
 	}
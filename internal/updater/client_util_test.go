@@ -1,21 +1,37 @@
 package updater
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/lens"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 )
 
 func TestServiceNoopSuppress(t *testing.T) {
@@ -249,6 +265,336 @@ func TestUDPRouteStatusParentDiff(t *testing.T) {
 		"expected parent ref change to be detected")
 }
 
+func TestServiceServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().Build()
+	u := NewUpdater(UpdaterConfig{Logger: logr.Discard()})
+
+	// another field manager creates the service with its own annotation and session affinity
+	other := testService()
+	other.Labels = nil
+	other.Annotations = map[string]string{"cloud": "lb-id"}
+	other.OwnerReferences = nil
+	other.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	obj, err := newApplyObject(other, cli.Scheme(), false)
+	require.NoError(t, err, "apply configuration")
+	require.NoError(t, cli.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj),
+		client.FieldOwner("cloud-controller")), "apply")
+
+	desired := testService()
+	desired.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	v := lens.NewServiceLens(desired)
+	_, err = u.applyObject(ctx, cli, v.ApplyObject(), "spec.Service", false)
+	require.Error(t, err, "conflicting server-side apply")
+	assert.ErrorIs(t, err, errApplyConflict, "conflict should be reported")
+	assert.False(t, apierrors.IsConflict(err), "reported conflict should not be retried")
+	assert.Equal(t, int64(1), u.SnapshotCounters()["spec.Service.conflict"],
+		"conflict should be counted")
+
+	current := &corev1.Service{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	assert.Equal(t, corev1.ServiceAffinityNone, current.Spec.SessionAffinity,
+		"conflicting field should not be forced")
+
+	// without the conflicting field the apply succeeds
+	desired.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	v = lens.NewServiceLens(desired)
+	rv, err := u.applyObject(ctx, cli, v.ApplyObject(), "spec.Service", false)
+	require.NoError(t, err, "server-side apply")
+	assert.NotEmpty(t, rv, "resource version should be returned")
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	assert.Equal(t, "lb-id", current.Annotations["cloud"],
+		"annotation of the other field manager should be preserved")
+	assert.Equal(t, "edge", current.Annotations["team"], "owned annotation should be applied")
+	assert.Equal(t, "stunner", current.Labels["app"], "owned label should be applied")
+	require.Len(t, current.OwnerReferences, 1, "ownerRef should be applied")
+
+	// re-applying the same object is conflict-free
+	_, err = u.applyObject(ctx, cli, v.ApplyObject(), "spec.Service", false)
+	require.NoError(t, err, "server-side apply")
+	assert.Equal(t, int64(1), u.SnapshotCounters()["spec.Service.conflict"],
+		"no new conflict should be counted")
+}
+
+func TestServiceServerSideApplyForcesOwnFields(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().Build()
+	u := NewUpdater(UpdaterConfig{Logger: logr.Discard()})
+
+	// the operator wrote the service in patch mode, using the default field manager
+	old := testService()
+	old.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	obj, err := newApplyObject(old, cli.Scheme(), false)
+	require.NoError(t, err, "apply configuration")
+	require.NoError(t, cli.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj),
+		client.FieldOwner(patchFieldManager())), "apply")
+
+	desired := testService()
+	desired.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	_, err = u.applyObject(ctx, cli, lens.NewServiceLens(desired).ApplyObject(), "spec.Service", false)
+	require.NoError(t, err, "server-side apply")
+	assert.Equal(t, int64(0), u.SnapshotCounters()["spec.Service.conflict"],
+		"conflict with own fields should not be counted")
+
+	current := &corev1.Service{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	assert.Equal(t, corev1.ServiceAffinityClientIP, current.Spec.SessionAffinity,
+		"own field should be forced")
+}
+
+func TestDeploymentServerSideApplyReplicas(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().Build()
+	u := NewUpdater(UpdaterConfig{Logger: logr.Discard()})
+
+	// the default replica count is not applied
+	desired := testDeployment()
+	desired.Spec.Replicas = ptrInt32(1)
+	_, err := u.applyObject(ctx, cli, lens.NewDeploymentLens(desired).ApplyObject(), "spec.Deployment", false)
+	require.NoError(t, err, "server-side apply")
+
+	// an autoscaler scales the deployment
+	current := &appv1.Deployment{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	patch := client.MergeFrom(current.DeepCopy())
+	current.Spec.Replicas = ptrInt32(3)
+	require.NoError(t, cli.Patch(ctx, current, patch, client.FieldOwner("kube-controller-manager")), "scale")
+
+	// a new render with the default replica count keeps the scaled replicas
+	desired.Spec.Template.Spec.Containers[0].Image = "stunnerd:v2"
+	_, err = u.applyObject(ctx, cli, lens.NewDeploymentLens(desired).ApplyObject(), "spec.Deployment", false)
+	require.NoError(t, err, "server-side apply")
+	assert.Equal(t, int64(0), u.SnapshotCounters()["spec.Deployment.conflict"],
+		"no conflict should be counted")
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	require.NotNil(t, current.Spec.Replicas, "replicas")
+	assert.Equal(t, int32(3), *current.Spec.Replicas, "scaled replicas should be kept")
+	assert.Equal(t, "stunnerd:v2", current.Spec.Template.Spec.Containers[0].Image,
+		"image should be applied")
+
+	// an explicit replica count conflicts with the autoscaler and is not forced
+	desired.Spec.Replicas = ptrInt32(2)
+	_, err = u.applyObject(ctx, cli, lens.NewDeploymentLens(desired).ApplyObject(), "spec.Deployment", false)
+	assert.ErrorIs(t, err, errApplyConflict, "conflict should be reported")
+	assert.Equal(t, int64(1), u.SnapshotCounters()["spec.Deployment.conflict"],
+		"conflict should be counted")
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(desired), current), "get")
+	assert.Equal(t, int32(3), *current.Spec.Replicas, "scaled replicas should not be forced")
+}
+
+func TestGatewayStatusServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme), "scheme")
+	require.NoError(t, gwapiv1.Install(scheme), "scheme")
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "stunner"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).
+		WithStatusSubresource(gw).Build()
+	u := NewUpdater(UpdaterConfig{Logger: logr.Discard()})
+
+	desired := gw.DeepCopy()
+	desired.Status.Conditions = []metav1.Condition{{
+		Type:               string(gwapiv1.GatewayConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gwapiv1.GatewayReasonAccepted),
+		LastTransitionTime: metav1.Now(),
+	}}
+	v, err := lens.New(desired)
+	require.NoError(t, err, "lens")
+
+	current := &gwapiv1.Gateway{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(gw), current), "get")
+	require.NoError(t, v.ApplyToStatus(current), "apply to status")
+	_, err = u.applyObject(ctx, cli, current, "status.Gateway", true)
+	require.NoError(t, err, "server-side apply status")
+
+	current = &gwapiv1.Gateway{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(gw), current), "get")
+	require.Len(t, current.Status.Conditions, 1, "status should be applied")
+	assert.Equal(t, string(gwapiv1.GatewayConditionAccepted), current.Status.Conditions[0].Type,
+		"status should be applied")
+	assert.Equal(t, gwapiv1.ObjectName("stunner"), current.Spec.GatewayClassName,
+		"spec should not be touched")
+}
+
+func TestStatusApplyKeepsResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme), "scheme")
+	require.NoError(t, gwapiv1.Install(scheme), "scheme")
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "default"},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "stunner"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).
+		WithStatusSubresource(gw).Build()
+	u := NewUpdater(UpdaterConfig{Logger: logr.Discard()})
+
+	current := &gwapiv1.Gateway{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(gw), current), "get")
+	obj, err := newApplyObject(current, cli.Scheme(), true)
+	require.NoError(t, err, "apply configuration")
+	assert.Equal(t, current.GetResourceVersion(), obj.GetResourceVersion(),
+		"status apply should keep the resource version")
+
+	// another writer updates the gateway
+	other := current.DeepCopy()
+	other.Labels = map[string]string{"other": "writer"}
+	require.NoError(t, cli.Update(ctx, other), "update")
+
+	current.Status.Conditions = []metav1.Condition{{
+		Type:               string(gwapiv1.GatewayConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gwapiv1.GatewayReasonAccepted),
+		LastTransitionTime: metav1.Now(),
+	}}
+	_, err = u.applyObject(ctx, cli, current, "status.Gateway", true)
+	assert.True(t, apierrors.IsConflict(err), "stale status apply should conflict")
+	assert.Equal(t, int64(0), u.SnapshotCounters()["status.Gateway.conflict"],
+		"stale status should not be forced")
+}
+
+func TestRouteStatusApplyOwnParents(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme), "scheme")
+	require.NoError(t, gwapiv1.Install(scheme), "scheme")
+	require.NoError(t, stnrgwv1.AddToScheme(scheme), "scheme")
+
+	mode := config.UpdateMode
+	config.UpdateMode = config.UpdateModeApply
+	defer func() { config.UpdateMode = mode }()
+
+	foreign := gwapiv1.RouteParentStatus{
+		ParentRef:      gwapiv1.ParentReference{Name: "other-gw"},
+		ControllerName: "example.com/other-controller",
+		Conditions:     []metav1.Condition{},
+	}
+	own := gwapiv1.RouteParentStatus{
+		ParentRef:      gwapiv1.ParentReference{Name: "gw"},
+		ControllerName: gwapiv1.GatewayController(config.ControllerName),
+		Conditions: []metav1.Condition{{
+			Type:               string(gwapiv1.RouteConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gwapiv1.RouteReasonAccepted),
+			LastTransitionTime: metav1.Now(),
+		}},
+	}
+
+	ro := &stnrgwv1.UDPRoute{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "default"}}
+	ro.Status.Parents = []gwapiv1.RouteParentStatus{foreign}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ro).
+		WithStatusSubresource(ro).Build()
+	u := NewUpdater(UpdaterConfig{Manager: &testManager{cli: cli}, Logger: logr.Discard()})
+
+	// only our own parent statuses are applied
+	desired := ro.DeepCopy()
+	desired.Status.Parents = []gwapiv1.RouteParentStatus{foreign, own}
+	obj, err := newApplyObject(desired, cli.Scheme(), true)
+	require.NoError(t, err, "apply configuration")
+	parents, found, err := unstructured.NestedSlice(obj.Object, "status", "parents")
+	require.NoError(t, err, "parents")
+	require.True(t, found, "parents")
+	require.Len(t, parents, 1, "only own parent statuses should be applied")
+	assert.Equal(t, config.ControllerName, parents[0].(map[string]any)["controllerName"],
+		"only own parent statuses should be applied")
+
+	// the parent statuses of other controllers are preserved
+	desired.Status.Parents = []gwapiv1.RouteParentStatus{own}
+	require.NoError(t, u.updateStatusObject(ctx, desired, 0), "update status")
+
+	current := &stnrgwv1.UDPRoute{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(ro), current), "get")
+	require.Len(t, current.Status.Parents, 2, "foreign parent status should be preserved")
+	assert.Equal(t, foreign.ControllerName, current.Status.Parents[1].ControllerName,
+		"foreign parent status should be preserved")
+	assert.Equal(t, own.ControllerName, current.Status.Parents[0].ControllerName,
+		"own parent status should be updated")
+
+	// a repeated update is suppressed
+	require.NoError(t, u.updateStatusObject(ctx, desired, 0), "update status")
+	assert.Equal(t, int64(1), u.SnapshotCounters()["status.UDPRoute.suppressed"],
+		"unchanged status should be suppressed")
+}
+
+func TestUpsertNoopApply(t *testing.T) {
+	ctx := context.Background()
+
+	mode := config.UpdateMode
+	config.UpdateMode = config.UpdateModeApply
+	defer func() { config.UpdateMode = mode }()
+
+	existing := testService()
+	existing.Spec.Ports[0].Port = 3479
+	noop := true
+	cli := fake.NewClientBuilder().WithObjects(existing).WithInterceptorFuncs(interceptor.Funcs{
+		Apply: func(ctx context.Context, cli client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			if !noop {
+				return cli.Apply(ctx, obj, opts...)
+			}
+			// a no-op apply returns the object unchanged
+			current := &unstructured.Unstructured{}
+			current.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(existing), current); err != nil {
+				return err
+			}
+			data, err := json.Marshal(current)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, obj)
+		},
+	}).Build()
+	u := NewUpdater(UpdaterConfig{Manager: &testManager{cli: cli}, Logger: logr.Discard()})
+
+	op, err := u.upsertResourceObject(ctx, testService(), 0)
+	require.NoError(t, err, "upsert")
+	assert.Equal(t, ctrlutil.OperationResultNone, op, "no-op apply should not be reported as an update")
+
+	noop = false
+	op, err = u.upsertResourceObject(ctx, testService(), 0)
+	require.NoError(t, err, "upsert")
+	assert.Equal(t, ctrlutil.OperationResultUpdated, op, "apply should be reported as an update")
+}
+
+func TestServiceApplyObjectDropsUnownedFields(t *testing.T) {
+	desired := testService()
+	desired.Annotations["cloud"] = "lb-id"
+	store.SetLastAppliedMetadata(desired, desired.Labels, map[string]string{"team": ""})
+	desired.Spec.ClusterIP = "10.0.0.10"
+	desired.Spec.ClusterIPs = []string{"10.0.0.10"}
+	desired.Spec.Ports[0].NodePort = 30000
+	desired.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}
+
+	o := lens.NewServiceLens(desired).ApplyObject()
+	svc, ok := o.(*corev1.Service)
+	require.True(t, ok, "apply object type")
+	assert.Equal(t, "edge", svc.Annotations["team"], "recorded annotation should be kept")
+	assert.NotContains(t, svc.Annotations, "cloud", "unrecorded annotation should be dropped")
+	assert.Contains(t, svc.Annotations, opdefault.LastAppliedMetadataAnnotationKey,
+		"last-applied record should be kept")
+	assert.Empty(t, svc.Spec.ClusterIP, "clusterIP should be dropped")
+	assert.Nil(t, svc.Spec.ClusterIPs, "clusterIPs should be dropped")
+	assert.Equal(t, int32(0), svc.Spec.Ports[0].NodePort, "unowned nodeport should be dropped")
+	assert.Empty(t, svc.Status.LoadBalancer.Ingress, "status should be dropped")
+}
+
+// testManager is a manager that provides only a client.
+type testManager struct {
+	manager.Manager
+	cli client.Client
+}
+
+func (m *testManager) GetClient() client.Client { return m.cli }
+
 func testService() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -312,6 +658,10 @@ func testDeployment() *appv1.Deployment {
 	}
 }
 
+func ptrInt32(v int32) *int32 {
+	return &v
+}
+
 func ptrInt64(v int64) *int64 {
 	return &v
 }
@@ -8,23 +8,30 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	"github.com/l7mp/stunner-gateway-operator/internal/event"
 	"github.com/l7mp/stunner-gateway-operator/internal/metrics"
+	"github.com/l7mp/stunner-gateway-operator/internal/recorder"
 	"github.com/l7mp/stunner-gateway-operator/internal/store"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
 )
 
 type UpdaterConfig struct {
 	Manager manager.Manager
-	Logger  logr.Logger
+	// Recorder emits Kubernetes Events for update failures, optional.
+	Recorder recorder.Recorder
+	Logger   logr.Logger
 }
 
 type Updater struct {
 	ctx       context.Context
 	manager   manager.Manager
+	recorder  recorder.Recorder
 	updaterCh chan event.Event
 	opCh      event.EventChannel
 	statsMu   sync.Mutex
@@ -34,8 +41,14 @@ type Updater struct {
 }
 
 func NewUpdater(cfg UpdaterConfig) *Updater {
+	rec := cfg.Recorder
+	if rec == nil {
+		rec = recorder.NewNoopRecorder()
+	}
+
 	return &Updater{
 		manager:         cfg.Manager,
+		recorder:        rec,
 		updaterCh:       make(chan event.Event, 10),
 		stats:           map[string]int64{},
 		ProgressTracker: config.NewProgressTracker(),
@@ -111,7 +124,9 @@ func (u *Updater) Start(ctx context.Context) error {
 				}
 
 				if update.GetRequestAck() {
-					u.opCh.Channel() <- event.NewEventAck(update.Generation)
+					ack := event.NewEventAck(update.Generation)
+					ack.TraceContext = update.TraceContext
+					u.opCh.Channel() <- ack
 				}
 
 				u.ProgressUpdate(-1)
@@ -134,35 +149,67 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	gen := e.Generation
 	u.log.Info("Processing update event", "generation", gen, "update", e.String())
 
+	ctx := u.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Start(e.GetTraceContext(ctx), "Update",
+		trace.WithAttributes(attribute.Int("generation", gen)))
+	defer span.End()
+
 	// run the upsert queue
 	q := e.UpsertQueue
 
 	for _, o := range q.GatewayClasses.Objects() {
-		if err := u.updateStatusObject(o, gen); err != nil {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot update GatewayClass status", "gateway-class", store.DumpObject(o))
 		}
 	}
 
+	for _, o := range q.GatewayConfigs.Objects() {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot update GatewayConfig status", "gateway-config", store.DumpObject(o))
+		}
+	}
+
+	for _, o := range q.Dataplanes.Objects() {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot update Dataplane status", "dataplane", store.DumpObject(o))
+		}
+	}
+
 	for _, o := range q.Gateways.Objects() {
-		if err := u.updateStatusObject(o, gen); err != nil {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot update Gateway status", "gateway", store.DumpObject(o))
 		}
 	}
 
 	for _, o := range q.UDPRoutes.Objects() {
-		if err := u.updateStatusObject(o, gen); err != nil {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot update UDPRoute status", "route", store.DumpObject(o))
 		}
 	}
 
 	for _, o := range q.UDPRoutesV1A2.Objects() {
-		if err := u.updateStatusObject(o, gen); err != nil {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot update UDPRouteV1A2 status", "route", store.DumpObject(o))
 		}
 	}
 
+	for _, o := range q.TCPRoutes.Objects() {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot update TCPRoute status", "route", store.DumpObject(o))
+		}
+	}
+
+	for _, o := range q.TLSRoutes.Objects() {
+		if err := u.updateStatusObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot update TLSRoute status", "route", store.DumpObject(o))
+		}
+	}
+
 	for _, o := range q.Services.Objects() {
-		if op, err := u.upsertResourceObject(o, gen); err != nil {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot update Service", "operation", op,
 				"service", store.DumpObject(o))
 			continue
@@ -170,7 +217,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, o := range q.ConfigMaps.Objects() {
-		if op, err := u.upsertResourceObject(o, gen); err != nil {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot upsert ConfigMap", "operation", op,
 				"config-map", store.DumpObject(o))
 			continue
@@ -178,7 +225,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, o := range q.Deployments.Objects() {
-		if op, err := u.upsertResourceObject(o, gen); err != nil {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot upsert Deployment", "operation", op,
 				"deployment", store.DumpObject(o))
 			continue
@@ -186,17 +233,33 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, o := range q.DaemonSets.Objects() {
-		if op, err := u.upsertResourceObject(o, gen); err != nil {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
 			u.log.Error(err, "Cannot upsert DaemonSet", "operation", op,
 				"daemonSet", store.DumpObject(o))
 			continue
 		}
 	}
 
+	for _, o := range q.PodDisruptionBudgets.Objects() {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot upsert PodDisruptionBudget", "operation", op,
+				"podDisruptionBudget", store.DumpObject(o))
+			continue
+		}
+	}
+
+	for _, o := range q.HorizontalPodAutoscalers.Objects() {
+		if op, err := u.upsertResourceObject(ctx, o, gen); err != nil {
+			u.log.Error(err, "Cannot upsert HorizontalPodAutoscaler", "operation", op,
+				"horizontalPodAutoscaler", store.DumpObject(o))
+			continue
+		}
+	}
+
 	// run the delete queue
 	q = e.DeleteQueue
 	for _, gc := range q.GatewayClasses.Objects() {
-		if err := u.deleteObject(gc, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, gc, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete GatewayClass", "gateway-class",
 				store.DumpObject(gc), "error", err)
 			continue
@@ -204,7 +267,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, gw := range q.Gateways.Objects() {
-		if err := u.deleteObject(gw, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, gw, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete Gateway", "gateway",
 				store.DumpObject(gw), "error", err)
 			continue
@@ -212,7 +275,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, ro := range q.UDPRoutes.Objects() {
-		if err := u.deleteObject(ro, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, ro, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete UDPRoute", "route",
 				store.DumpObject(ro), "error", err)
 			continue
@@ -220,15 +283,31 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, ro := range q.UDPRoutesV1A2.Objects() {
-		if err := u.deleteObject(ro, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, ro, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete UDPRouteV1A2", "route",
 				store.DumpObject(ro), "error", err)
 			continue
 		}
 	}
 
+	for _, ro := range q.TCPRoutes.Objects() {
+		if err := u.deleteObject(ctx, ro, gen); err != nil && !apierrors.IsNotFound(err) {
+			u.log.V(1).Info("Cannot delete TCPRoute", "route",
+				store.DumpObject(ro), "error", err)
+			continue
+		}
+	}
+
+	for _, ro := range q.TLSRoutes.Objects() {
+		if err := u.deleteObject(ctx, ro, gen); err != nil && !apierrors.IsNotFound(err) {
+			u.log.V(1).Info("Cannot delete TLSRoute", "route",
+				store.DumpObject(ro), "error", err)
+			continue
+		}
+	}
+
 	for _, svc := range q.Services.Objects() {
-		if err := u.deleteObject(svc, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, svc, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete Service", "service",
 				store.DumpObject(svc), "error", err)
 			continue
@@ -236,7 +315,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, cm := range q.ConfigMaps.Objects() {
-		if err := u.deleteObject(cm, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, cm, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete config-map", "config-map",
 				store.DumpObject(cm), "error", err)
 			continue
@@ -244,7 +323,7 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, dp := range q.Deployments.Objects() {
-		if err := u.deleteObject(dp, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, dp, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete deployment", "deployment",
 				store.DumpObject(dp), "error", err)
 			continue
@@ -252,13 +331,29 @@ func (u *Updater) ProcessUpdate(e *event.EventUpdate) error {
 	}
 
 	for _, ds := range q.DaemonSets.Objects() {
-		if err := u.deleteObject(ds, gen); err != nil && !apierrors.IsNotFound(err) {
+		if err := u.deleteObject(ctx, ds, gen); err != nil && !apierrors.IsNotFound(err) {
 			u.log.V(1).Info("Cannot delete daemonSet", "daemonSet",
 				store.DumpObject(ds), "error", err)
 			continue
 		}
 	}
 
+	for _, pdb := range q.PodDisruptionBudgets.Objects() {
+		if err := u.deleteObject(ctx, pdb, gen); err != nil && !apierrors.IsNotFound(err) {
+			u.log.V(1).Info("Cannot delete podDisruptionBudget", "podDisruptionBudget",
+				store.DumpObject(pdb), "error", err)
+			continue
+		}
+	}
+
+	for _, hpa := range q.HorizontalPodAutoscalers.Objects() {
+		if err := u.deleteObject(ctx, hpa, gen); err != nil && !apierrors.IsNotFound(err) {
+			u.log.V(1).Info("Cannot delete horizontalPodAutoscaler", "horizontalPodAutoscaler",
+				store.DumpObject(hpa), "error", err)
+			continue
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The l7mp/stunner team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/renderer"
+	"github.com/l7mp/stunner-gateway-operator/internal/store"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// +kubebuilder:webhook:path=/validate-stunner-l7mp-io-v1-gatewayconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=stunner.l7mp.io,resources=gatewayconfigs,verbs=create;update,versions=v1,name=vgatewayconfig.stunner.l7mp.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-stunner-l7mp-io-v1-dataplane,mutating=false,failurePolicy=fail,sideEffects=None,groups=stunner.l7mp.io,resources=dataplanes,verbs=create;update,versions=v1,name=vdataplane.stunner.l7mp.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-stunner-l7mp-io-v1-udproute,mutating=false,failurePolicy=fail,sideEffects=None,groups=stunner.l7mp.io,resources=udproutes,verbs=create;update,versions=v1,name=vudproute.stunner.l7mp.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-stunner-l7mp-io-v1-staticservice,mutating=false,failurePolicy=fail,sideEffects=None,groups=stunner.l7mp.io,resources=staticservices,verbs=create;update,versions=v1,name=vstaticservice.stunner.l7mp.io,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhooks for GatewayConfigs, Dataplanes, UDPRoutes
+// and StaticServices with the webhook server of the manager.
+func SetupWithManager(mgr manager.Manager, log logr.Logger) error {
+	log = log.WithName("webhook")
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &stnrgwv1.GatewayConfig{}).
+		WithValidator(newValidator("GatewayConfig", renderer.ValidateGatewayConfig, log)).
+		Complete(); err != nil {
+		return err
+	}
+	log.Info("registered GatewayConfig validating webhook")
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &stnrgwv1.Dataplane{}).
+		WithValidator(newValidator("Dataplane", renderer.ValidateDataplane, log)).
+		Complete(); err != nil {
+		return err
+	}
+	log.Info("registered Dataplane validating webhook")
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &stnrgwv1.UDPRoute{}).
+		WithValidator(newValidator("UDPRoute", renderer.ValidateUDPRoute, log)).
+		Complete(); err != nil {
+		return err
+	}
+	log.Info("registered UDPRoute validating webhook")
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &stnrgwv1.StaticService{}).
+		WithValidator(newValidator("StaticService", renderer.ValidateStaticService, log)).
+		Complete(); err != nil {
+		return err
+	}
+	log.Info("registered StaticService validating webhook")
+
+	return nil
+}
+
+var _ admission.Validator[*stnrgwv1.GatewayConfig] = &validator[*stnrgwv1.GatewayConfig]{}
+
+// validator rejects objects that would fail in the render pipeline.
+type validator[T client.Object] struct {
+	kind     string
+	validate func(T) field.ErrorList
+	log      logr.Logger
+}
+
+func newValidator[T client.Object](kind string, validate func(T) field.ErrorList, log logr.Logger) *validator[T] {
+	return &validator[T]{kind: kind, validate: validate, log: log}
+}
+
+// ValidateCreate validates an object on creation.
+func (v *validator[T]) ValidateCreate(_ context.Context, obj T) (admission.Warnings, error) {
+	return nil, v.check(obj)
+}
+
+// ValidateUpdate validates an object on update. Objects being deleted are let through so that
+// finalizers can be removed from invalid objects.
+func (v *validator[T]) ValidateUpdate(_ context.Context, _, obj T) (admission.Warnings, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return nil, v.check(obj)
+}
+
+// ValidateDelete lets all deletions through.
+func (v *validator[T]) ValidateDelete(_ context.Context, _ T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *validator[T]) check(obj T) error {
+	errs := v.validate(obj)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	v.log.V(1).Info("Rejecting invalid object", "kind", v.kind, "name", store.GetObjectKey(obj),
+		"errors", errs.ToAggregate().Error())
+
+	return apierrors.NewInvalid(stnrgwv1.GroupVersion.WithKind(v.kind).GroupKind(),
+		obj.GetName(), errs)
+}
@@ -31,24 +31,32 @@ import (
 
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
 	"github.com/l7mp/stunner/pkg/buildinfo"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	"github.com/l7mp/stunner-gateway-operator/internal/debug"
 	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
+	"github.com/l7mp/stunner-gateway-operator/internal/offline"
 	"github.com/l7mp/stunner-gateway-operator/internal/operator"
+	"github.com/l7mp/stunner-gateway-operator/internal/recorder"
 	"github.com/l7mp/stunner-gateway-operator/internal/renderer"
+	"github.com/l7mp/stunner-gateway-operator/internal/tracing"
 	"github.com/l7mp/stunner-gateway-operator/internal/updater"
+	opwebhook "github.com/l7mp/stunner-gateway-operator/internal/webhook"
 	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
@@ -62,6 +70,9 @@ const (
 	envVarPprofAddr      = "STUNNER_GATEWAY_OPERATOR_PPROF_BIND_ADDRESS"
 	envVarLabelFilter    = "STUNNER_GATEWAY_OPERATOR_LABEL_FILTER"
 	envVarCustomerKey    = "CUSTOMER_KEY"
+	envVarOTLPEndpoint   = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envVarPodName        = "STUNNER_GATEWAY_OPERATOR_POD_NAME"
+	envVarPodNamespace   = "STUNNER_GATEWAY_OPERATOR_POD_NAMESPACE"
 )
 
 var (
@@ -74,14 +85,28 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme)) //nolint:staticcheck
 	utilruntime.Must(gwapiv1a2.AddToScheme(scheme))      //nolint:staticcheck
+	utilruntime.Must(gwapiv1b1.AddToScheme(scheme))      //nolint:staticcheck
 	utilruntime.Must(gwapiv1.AddToScheme(scheme))        //nolint:staticcheck
 	utilruntime.Must(stnrgwv1a1.AddToScheme(scheme))     //nolint:staticcheck
 	utilruntime.Must(stnrgwv1.AddToScheme(scheme))       //nolint:staticcheck
 }
 
 func main() {
+	// offline render mode: render the manifests and exit
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		os.Exit(runRender(os.Args[2:]))
+	}
+
 	var controllerName, dataplaneMode, metricsAddr, cdsAddr, throttleTimeout, probeAddr, pprofAddr string
-	var enableLeaderElection, enableEDS, disableEndpontSliceController, enableFinalizer bool
+	var enableLeaderElection, enableEDS, disableEndpontSliceController, enableFinalizer, enableWebhook bool
+	var enableDebugEndpoint bool
+	var webhookPort int
+	var webhookCertDir string
+	var cdsAuthMode, cdsSnapshot, cdsAdvertiseAddr string
+	var otlpEndpoint string
+	var otlpInsecure bool
+	var traceSampleRatio float64
+	var updateMode string
 
 	defaultControllerName := opdefault.DefaultControllerName
 	if name, ok := os.LookupEnv(envVarControllerName); ok {
@@ -97,9 +122,20 @@ func main() {
 	flag.StringVar(&dataplaneMode, "dataplane-mode", opdefault.DefaultDataplaneMode,
 		`Managed dataplane mode: either "managed" (automatic dataplane provisioning using the config discovery service) or "legacy" (dataplane(s) provided by the user).`)
 	flag.StringVar(&cdsAddr, "config-discovery-address", stnrv1.DefaultConfigDiscoveryAddress, `Config discovery server endpoint.`)
+	flag.StringVar(&cdsAdvertiseAddr, "config-discovery-advertise-address", "",
+		`Config discovery server address advertised to the dataplane, e.g., the address of a Service that selects the leader operator pod. Default is to advertise the address of the operator pod.`)
+	flag.StringVar(&cdsAuthMode, "config-discovery-auth", opdefault.DefaultCDSAuthMode,
+		`Config discovery client authentication: either "none" or "pod" (clients are identified by the dataplane pod that owns their source address and receive only the config of the Gateway of the pod).`)
+	flag.StringVar(&cdsSnapshot, "config-discovery-snapshot", "",
+		`Persist the last acknowledged config discovery state and serve it after a restart: either "file:<path>" (on a protected volume) or "secret:<namespace>/<name>". Empty disables persistence.`)
+	flag.StringVar(&updateMode, "update-mode", opdefault.DefaultUpdateMode,
+		`Resource update mode: either "patch" (read the current object and patch the operator-owned fields) or "apply" (server-side apply with the field manager "`+opdefault.DefaultFieldManager+`").`)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&pprofAddr, "pprof-bind-address", "0", "The address the pprof endpoint binds to. Set to \"0\" to disable.")
+	flag.BoolVar(&enableDebugEndpoint, "enable-debug-endpoint", false,
+		fmt.Sprintf("Serve a dump of the operator's internal state at %s on the metrics endpoint.",
+			opdefault.DefaultDebugEndpointPath))
 	flag.BoolVar(&disableEndpontSliceController, "disable-endpontslice-controller", false,
 		"Disable the EndpointSlice controller and fall back to the legacy Endpoints controller.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -107,6 +143,17 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&enableFinalizer, "enable-finalizer", opdefault.DefaultEnableFinalizer,
 		"Clean up allocated resources and invalidate resource statuses on operator exit.")
+	flag.BoolVar(&enableWebhook, "enable-webhook", false,
+		"Enable the validating admission webhook for GatewayConfig, Dataplane, UDPRoute and StaticService resources.")
+	flag.IntVar(&webhookPort, "webhook-port", webhook.DefaultPort, "The port the admission webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"The directory that contains the serving certificate and key (tls.crt and tls.key) for the admission webhook server.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", os.Getenv(envVarOTLPEndpoint),
+		"The OTLP gRPC endpoint to export traces to, as a URL or host:port. Tracing is disabled if empty.")
+	flag.BoolVar(&otlpInsecure, "otlp-insecure", false,
+		"Disable transport security for the OTLP trace exporter.")
+	flag.Float64Var(&traceSampleRatio, "trace-sample-ratio", opdefault.DefaultTraceSampleRatio,
+		"The ratio of the traces to sample, between 0 and 1.")
 
 	opts := zap.Options{
 		Development:     true,
@@ -143,6 +190,14 @@ func main() {
 
 	config.DataplaneMode = config.NewDataplaneMode(dataplaneMode)
 	setupLog.Info("dataplane mode", "mode", config.DataplaneMode.String())
+
+	mode, err := config.NewUpdateMode(updateMode)
+	if err != nil {
+		setupLog.Error(err, "invalid update mode")
+		os.Exit(1)
+	}
+	config.UpdateMode = mode
+	setupLog.Info("update mode", "mode", config.UpdateMode.String())
 	pprofAddr = resolvePprofBindAddress(pprofAddr)
 	setupLog.Info("pprof server", "address", pprofAddr)
 
@@ -164,9 +219,19 @@ func main() {
 		}
 		config.ConfigDiscoveryAddress = net.JoinHostPort(podAddr, port)
 	}
+	if cdsAdvertiseAddr != "" {
+		config.ConfigDiscoveryAddress = cdsAdvertiseAddr
+	}
+
+	authMode, err := config.NewCDSAuthMode(cdsAuthMode)
+	if err != nil {
+		setupLog.Error(err, "invalid config discovery authentication mode")
+		os.Exit(1)
+	}
+	config.CDSAuthMode = authMode
 
 	setupLog.Info("config discovery server", "local-addr", cdsAddr,
-		"remote-addr", config.ConfigDiscoveryAddress)
+		"remote-addr", config.ConfigDiscoveryAddress, "auth", config.CDSAuthMode.String())
 
 	// label filter: when set, env-var replaces the default; set-empty disables filtering
 	if raw, ok := os.LookupEnv(envVarLabelFilter); ok {
@@ -196,6 +261,10 @@ func main() {
 		PprofBindAddress:       pprofAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "92062b70.l7mp.io",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to set up Kubernetes controller manager")
@@ -211,44 +280,131 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableWebhook {
+		setupLog.Info("setting up admission webhooks", "port", webhookPort)
+		if err := opwebhook.SetupWithManager(mgr, logger); err != nil {
+			setupLog.Error(err, "unable to set up admission webhooks")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("setting up license manager")
 	m := licensemgr.NewManager(customerKey, logger)
 
+	setupLog.Info("setting up event recorder")
+	rec := recorder.NewRecorder(recorder.RecorderConfig{
+		EventRecorder: mgr.GetEventRecorder(opdefault.DefaultEventRecorderName),
+		Logger:        logger,
+	})
+
+	setupLog.Info("setting up CDS server", "address", cdsAddr)
+	var cdsAuth config.Authenticator
+	if config.CDSAuthMode == config.CDSAuthModePod {
+		cdsAuth = config.NewPodAuthenticator(mgr.GetAPIReader(), logger)
+	}
+	c, err := config.NewCDSServer(cdsAddr, cdsAuth, logger)
+	if err != nil {
+		setupLog.Error(err, "cannot set up CDS server")
+		os.Exit(1)
+	}
+	if cdsSnapshot != "" {
+		store, err := config.NewSnapshotStore(cdsSnapshot, mgr.GetClient(), mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "invalid config discovery snapshot store")
+			os.Exit(1)
+		}
+		setupLog.Info("config discovery snapshot enabled", "store", store.String())
+		c.SetSnapshotStore(store)
+	}
+
+	var leaderLabeler *config.LeaderLabeler
+	if enableLeaderElection {
+		// standby replicas do not serve config discovery clients
+		c.SetLeaderElection(mgr.Elected())
+
+		podName, podNamespace := os.Getenv(envVarPodName), os.Getenv(envVarPodNamespace)
+		if podName != "" && podNamespace != "" {
+			pod := types.NamespacedName{Namespace: podNamespace, Name: podName}
+			setupLog.Info("setting up config discovery leader label", "pod", pod.String(),
+				"label", opdefault.DefaultCDSLeaderLabelKey)
+			leaderLabeler = config.NewLeaderLabeler(mgr.GetClient(), pod, logger)
+			if err := mgr.Add(leaderLabeler); err != nil {
+				setupLog.Error(err, "unable to set up config discovery leader label")
+				os.Exit(1)
+			}
+		} else {
+			setupLog.Info("operator pod unknown, not labeling the config discovery leader",
+				"env", []string{envVarPodName, envVarPodNamespace})
+		}
+	}
+
 	setupLog.Info("setting up config renderer")
 	r := renderer.NewRenderer(renderer.RendererConfig{
 		Scheme:         scheme,
 		LicenseManager: m,
+		Recorder:       rec,
+		SyncStatus:     c,
 		Logger:         logger,
 	})
 
 	setupLog.Info("setting up updater client")
 	u := updater.NewUpdater(updater.UpdaterConfig{
-		Manager: mgr,
-		Logger:  logger,
+		Manager:  mgr,
+		Recorder: rec,
+		Logger:   logger,
 	})
 
-	setupLog.Info("setting up CDS server", "address", cdsAddr)
-	c := config.NewCDSServer(cdsAddr, logger)
-
 	setupLog.Info("setting up operator")
 	op := operator.NewOperator(operator.OperatorConfig{
 		ControllerName: controllerName,
 		Manager:        mgr,
 		RenderCh:       r.GetRenderChannel(),
 		ConfigCh:       c.GetConfigUpdateChannel(),
+		ConfigAckCh:    c.GetAckChannel(),
 		UpdaterCh:      u.GetUpdaterChannel(),
+		RecordUpdates:  enableDebugEndpoint,
 		Logger:         logger,
 	})
 
+	if enableDebugEndpoint {
+		setupLog.Info("setting up debug endpoint", "path", opdefault.DefaultDebugEndpointPath)
+		if err := mgr.AddMetricsServerExtraHandler(opdefault.DefaultDebugEndpointPath,
+			debug.NewHandler(op, logger)); err != nil {
+			setupLog.Error(err, "unable to set up debug endpoint")
+			os.Exit(1)
+		}
+	}
+
 	m.SetOperatorChannel(op.GetOperatorChannel())
 	r.SetOperatorChannel(op.GetOperatorChannel())
 	u.SetAckChannel(op.GetOperatorChannel())
+	c.SetOperatorChannel(op.GetOperatorChannel())
 	op.SetProgressReporters(r, u, c)
 
 	// create a general context, which will be canceled by the operator
 	mgrCtx, mgrCancel := context.WithCancel(context.Background())
 	defer mgrCancel()
 
+	setupLog.Info("setting up tracing")
+	shutdownTracing, err := tracing.Setup(mgrCtx, tracing.Config{
+		Endpoint:    otlpEndpoint,
+		Insecure:    otlpInsecure,
+		SampleRatio: traceSampleRatio,
+		Version:     version,
+		Logger:      logger,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), opdefault.DefaultTraceShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			setupLog.Error(err, "error flushing traces")
+		}
+	}()
+
 	setupLog.Info("starting the license manager")
 	if err := m.Start(mgrCtx); err != nil {
 		setupLog.Error(err, "error running the license manager")
@@ -267,6 +423,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	if leaderLabeler != nil {
+		// the pod may keep the label from a previous run of the operator
+		if err := leaderLabeler.Reset(mgrCtx); err != nil {
+			setupLog.Error(err, "could not reset config discovery leader label")
+		}
+	}
+
 	setupLog.Info("starting config discovery server")
 	if err := c.Start(mgrCtx); err != nil {
 		setupLog.Error(err, "could not run config discovery server")
@@ -297,3 +460,66 @@ func resolvePprofBindAddress(pprofAddr string) string {
 
 	return pprofAddr
 }
+
+// manifestFlags collects the repeated -f flags.
+type manifestFlags []string
+
+func (m *manifestFlags) String() string     { return strings.Join(*m, ",") }
+func (m *manifestFlags) Set(v string) error { *m = append(*m, v); return nil }
+
+// runRender implements the "render" subcommand: it loads a set of manifests, runs the renderer
+// without connecting to Kubernetes and prints the result.
+func runRender(args []string) int {
+	var manifests manifestFlags
+	var controllerName, dataplaneMode, output string
+	var enableEDS bool
+
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s render -f <file|dir|-> [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Var(&manifests, "f", "Manifest file or directory to load, \"-\" reads from stdin. Can be repeated.")
+	fs.StringVar(&output, "o", offline.OutputYAML, `Output format: either "yaml" or "json".`)
+	fs.StringVar(&controllerName, "controller-name", opdefault.DefaultControllerName,
+		"The conroller name to be used in the GatewayClass resource to bind it to this operator.")
+	fs.StringVar(&dataplaneMode, "dataplane-mode", opdefault.DefaultDataplaneMode,
+		`Managed dataplane mode: either "managed" or "legacy".`)
+	fs.BoolVar(&enableEDS, "endpoint-discovery", opdefault.DefaultEnableEndpointDiscovery,
+		fmt.Sprintf("Enable endpoint discovery, default: %t.", opdefault.DefaultEnableEndpointDiscovery))
+
+	opts := zap.Options{
+		Development:     true,
+		DestWriter:      os.Stderr,
+		Level:           zapcore.ErrorLevel,
+		StacktraceLevel: zapcore.PanicLevel,
+		TimeEncoder:     zapcore.RFC3339NanoTimeEncoder,
+	}
+	opts.BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if len(manifests) == 0 {
+		fmt.Fprintln(os.Stderr, "no manifests: use -f to specify the resources to render")
+		fs.Usage()
+		return 2
+	}
+
+	logger := zap.New(zap.UseFlagOptions(&opts))
+
+	if err := offline.Render(offline.Options{
+		Manifests:               manifests,
+		DataplaneMode:           dataplaneMode,
+		ControllerName:          controllerName,
+		EnableEndpointDiscovery: enableEDS,
+		Output:                  output,
+		Scheme:                  scheme,
+		Logger:                  logger,
+	}, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "render failed: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -71,6 +71,22 @@ const (
 	// to external clients.
 	DefaultServiceType = corev1.ServiceTypeLoadBalancer
 
+	// GatewayConditionDataplaneConfigSynced is the Gateway condition that reports whether the
+	// dataplane pods have picked up the latest config from the config discovery server.
+	GatewayConditionDataplaneConfigSynced = "DataplaneConfigSynced"
+
+	// GatewayReasonDataplaneConfigSynced is used with the DataplaneConfigSynced condition
+	// when all dataplane pods run the latest config.
+	GatewayReasonDataplaneConfigSynced = "Synced"
+
+	// GatewayReasonDataplaneConfigPending is used with the DataplaneConfigSynced condition
+	// when some dataplane pods still run an older config.
+	GatewayReasonDataplaneConfigPending = "Pending"
+
+	// GatewayReasonDataplaneConfigNoClients is used with the DataplaneConfigSynced condition
+	// when no dataplane pod has fetched the config yet.
+	GatewayReasonDataplaneConfigNoClients = "NoClients"
+
 	// // GatewayManagedLabelValue indicates that the object's lifecycle is managed by
 	// // the gateway controller.
 	// GatewayManagedLabelValue = "gateway"
@@ -99,6 +115,62 @@ const (
 	// renders.
 	DefaultThrottleTimeout = 250 * time.Millisecond
 
+	// DefaultEventRecorderName is the name of the component reported in the Kubernetes Events
+	// emitted by the operator.
+	DefaultEventRecorderName = "stunner-gateway-operator"
+
+	// DefaultEventDedupInterval is the time interval during which identical Kubernetes Events
+	// emitted on the same object are suppressed.
+	DefaultEventDedupInterval = 10 * time.Minute
+
+	// DefaultEventRateLimit is the average time interval between subsequent Kubernetes Events
+	// emitted on the same object.
+	DefaultEventRateLimit = 30 * time.Second
+
+	// DefaultEventBurst is the maximum number of Kubernetes Events that can be emitted on the
+	// same object in a burst.
+	DefaultEventBurst = 5
+
+	// DefaultTraceSampleRatio is the default ratio of the traces sampled by the operator.
+	DefaultTraceSampleRatio = 1.0
+
+	// DefaultTraceShutdownTimeout is the time to wait for the pending traces to be exported on
+	// exit.
+	DefaultTraceShutdownTimeout = 5 * time.Second
+
+	// DefaultUpdateMode is the default mode the updater uses to write the resources to
+	// Kubernetes.
+	DefaultUpdateMode = "patch"
+
+	// DefaultFieldManager is the field manager the operator uses for server-side apply.
+	DefaultFieldManager = "stunner-gateway-operator"
+
+	// DefaultCDSAuthMode is the default authentication mode of the config discovery server.
+	DefaultCDSAuthMode = "none"
+
+	// DefaultCDSAuthCacheTTL is the time the config discovery server caches the Gateways of a
+	// successfully authenticated client address. Kept short, since the address of a deleted pod
+	// may be reused by another pod.
+	DefaultCDSAuthCacheTTL = 10 * time.Second
+
+	// DefaultCDSSnapshotKey is the key of the config discovery snapshot in the snapshot
+	// Secret.
+	DefaultCDSSnapshotKey = "snapshot.json"
+
+	// DefaultCDSLeaderLabelKey is the label set on the operator pod that holds the leader
+	// lease, so that the config discovery Service can select the active config discovery
+	// server.
+	DefaultCDSLeaderLabelKey = "stunner.l7mp.io/config-discovery-leader"
+
+	// DefaultCDSLeaderLabelValue is the value of the leader label.
+	DefaultCDSLeaderLabelValue = "true"
+
+	// DefaultCDSLeaderLabelTimeout is the timeout for updating the leader label.
+	DefaultCDSLeaderLabelTimeout = 5 * time.Second
+
+	// DefaultDebugEndpointPath is the path of the debug endpoint on the metrics server.
+	DefaultDebugEndpointPath = "/debug/stunner"
+
 	// DefaultMetricsPortName defines the name of the container-port used to expose the metrics
 	// endpoint (if enabled).
 	DefaultMetricsPortName = "metrics-port"
@@ -133,6 +205,11 @@ const (
 	// managed dataplane feature for a Gateway.
 	ManagedDataplaneDisabledAnnotationValue = "true"
 
+	// DataplaneAnnotationKey is the name(key) of the Gateway annotation that is used to select
+	// the Dataplane for a Gateway, overriding the Dataplane set in the GatewayConfig. The
+	// value is the name of the Dataplane.
+	DataplaneAnnotationKey = "stunner.l7mp.io/dataplane"
+
 	// NodePortAnnotationKey is the name(key) of the Gateway annotation that is used to select
 	// particular nodeports per listener for the LB service, see
 	// https://github.com/l7mp/stunner/issues/137.
@@ -143,6 +220,35 @@ const (
 	// https://github.com/l7mp/stunner/issues/137.
 	TargetPortAnnotationKey = "stunner.l7mp.io/targetport"
 
+	// IPFamilyPolicyAnnotationKey is the name(key) of the Gateway annotation that is used to
+	// set the IP family policy of the LB service. Possible values are "SingleStack",
+	// "PreferDualStack" and "RequireDualStack". Default is to let Kubernetes choose.
+	IPFamilyPolicyAnnotationKey = "stunner.l7mp.io/ip-family-policy"
+
+	// IPFamiliesAnnotationKey is the name(key) of the Gateway annotation that is used to set
+	// the IP families of the LB service, as a comma-separated list of "IPv4" and "IPv6" in
+	// order of preference, e.g., "IPv6,IPv4". Default is to let Kubernetes choose.
+	IPFamiliesAnnotationKey = "stunner.l7mp.io/ip-families"
+
+	// PublicAddressFamilyAnnotationKey is the name(key) of the Gateway annotation that is used
+	// to select the IP family ("IPv4" or "IPv6") of the public address advertised to stunnerd
+	// per listener, given as JSON formatted listener-family key-value pairs. Default is to use
+	// the first public address found for the listener.
+	PublicAddressFamilyAnnotationKey = "stunner.l7mp.io/public-address-family"
+
+	// LastAppliedMetadataAnnotationKey is the name(key) of the annotation the operator uses to
+	// record the set of labels and annotations it applied to a LB service, as a JSON object with
+	// the label and annotation keys. This allows the operator to remove the labels and
+	// annotations it applied earlier but no longer wants (e.g., an annotation removed from the
+	// GatewayConfig), while leaving the ones added by Kubernetes or the cloud provider alone.
+	LastAppliedMetadataAnnotationKey = "stunner.l7mp.io/last-applied-metadata"
+
+	// LastAppliedPodTemplatePatchAnnotationKey is the name(key) of the annotation the operator
+	// uses to record the pod template fields set by the pod template patch of a Dataplane on a
+	// dataplane Deployment or DaemonSet, as a JSON list of field paths. This allows the operator
+	// to reset the fields the patch set earlier but no longer sets.
+	LastAppliedPodTemplatePatchAnnotationKey = "stunner.l7mp.io/last-applied-pod-template-patch"
+
 	// DisableHealthCheckExposeAnnotationKey is the name(key) of the Gateway annotation that is
 	// used to disable the LB service to expose the health-check port. Adding the health-check
 	// service-port seems to be required by some cloud providers for exposing UDP listeners on
@@ -194,6 +300,15 @@ const (
 	// to the CDS server's config patcher to replace the listener address with the node
 	// external IP.
 	NodeAddressPlaceholder = stnrconfv1.DefaultNodeAddressPlaceholder
+
+	// DefaultAutoscalingTargetCPUUtilization is the target average CPU utilization of the
+	// dataplane pods, in percent of the requested CPU, when autoscaling is enabled for a
+	// Dataplane but no metric target is set.
+	DefaultAutoscalingTargetCPUUtilization int32 = 80
+
+	// AllocationsActiveMetricName is the name of the stunnerd metric that counts the active
+	// TURN allocations, exposed as a custom per-pod metric for autoscaling the dataplane.
+	AllocationsActiveMetricName = "stunner_allocations_active"
 )
 
 var (
@@ -29,6 +29,7 @@ import (
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
@@ -314,7 +315,12 @@ func setupBenchmarkSystem(b *testing.B) *benchmarkSystem {
 	})
 
 	cdsAddr := reserveLoopbackAddress(b)
-	cds := config.NewCDSServer(cdsAddr, logger)
+	cds, err := config.NewCDSServer(cdsAddr, nil, logger)
+	if err != nil {
+		cancel()
+		_ = testEnv.Stop()
+		b.Fatalf("failed to create config discovery server: %v", err)
+	}
 
 	op := operator.NewOperator(operator.OperatorConfig{
 		ControllerName: opdefault.DefaultControllerName,
@@ -657,6 +663,10 @@ func mustAddScheme(b *testing.B, scheme *runtime.Scheme) {
 		b.Fatalf("failed to add gateway v1alpha2 scheme: %v", err)
 	}
 
+	if err := gwapiv1b1.AddToScheme(scheme); err != nil { //nolint:staticcheck
+		b.Fatalf("failed to add gateway v1beta1 scheme: %v", err)
+	}
+
 	if err := stnrgwv1.AddToScheme(scheme); err != nil { //nolint:staticcheck
 		b.Fatalf("failed to add stunner gateway scheme: %v", err)
 	}
@@ -44,6 +44,7 @@ import (
 
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/l7mp/stunner-gateway-operator/internal/config"
 	licensemgr "github.com/l7mp/stunner-gateway-operator/internal/licensemanager"
@@ -149,6 +150,8 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	err = gwapiv1a2.AddToScheme(scheme) //nolint:staticcheck
 	Expect(err).NotTo(HaveOccurred())
+	err = gwapiv1b1.AddToScheme(scheme) //nolint:staticcheck
+	Expect(err).NotTo(HaveOccurred())
 
 	// STUNner CRD scheme
 	err = stnrgwv1.AddToScheme(scheme) //nolint:staticcheck
@@ -205,7 +208,8 @@ func initOperator(mgrCtx, opCtx context.Context) {
 	config.ConfigDiscoveryAddress = fmt.Sprintf("127.0.0.1:%d", cdsPort)
 	setupLog.Info("setting up CDS server", "bind-address", cdsBindAddr,
 		"client-address", cdsServerAddr)
-	c := config.NewCDSServer(cdsBindAddr, ctrl.Log)
+	c, err := config.NewCDSServer(cdsBindAddr, nil, ctrl.Log)
+	Expect(err).NotTo(HaveOccurred())
 
 	// make rendering fast!
 	config.ThrottleTimeout = 10 * time.Millisecond
@@ -337,6 +341,10 @@ var _ = Describe("Integration test:", Ordered, func() {
 	// MANAGED
 	// FINALIZER
 	finalizerTest()
+
+	// HA
+	// LEADER ELECTION
+	leaderElectionTest()
 })
 
 func legacyModeEndpointControllerTest() {
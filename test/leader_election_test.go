@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The l7mp/stunner team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/config"
+	"github.com/l7mp/stunner-gateway-operator/internal/event"
+	opdefault "github.com/l7mp/stunner-gateway-operator/pkg/config"
+
+	stnrv1 "github.com/l7mp/stunner/pkg/apis/v1"
+	cdsclient "github.com/l7mp/stunner/pkg/config/client"
+	"github.com/l7mp/stunner/pkg/logger"
+)
+
+const leaderElectionID = "cds-leader-test.l7mp.io"
+
+// cdsReplica is an operator replica reduced to the parts that take part in the config
+// discovery failover: a manager with leader election, a config discovery server and a leader
+// labeler.
+type cdsReplica struct {
+	pod    types.NamespacedName
+	addr   string
+	cds    *config.Server
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startCDSReplica(name string, store config.SnapshotStore) *cdsReplica {
+	leaseDuration, renewDeadline, retryPeriod := 2*time.Second, time.Second, 200*time.Millisecond
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                        scheme,
+		Metrics:                       metricsserver.Options{BindAddress: "0"},
+		LeaderElection:                true,
+		LeaderElectionID:              leaderElectionID,
+		LeaderElectionNamespace:       testNs.GetName(),
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	r := &cdsReplica{
+		pod:  types.NamespacedName{Namespace: testNs.GetName(), Name: name},
+		addr: fmt.Sprintf("127.0.0.1:%d", rand.Intn(1<<15)+1<<15),
+		done: make(chan struct{}),
+	}
+
+	r.cds, err = config.NewCDSServer(r.addr, nil, ctrl.Log.WithName(name))
+	Expect(err).NotTo(HaveOccurred())
+	r.cds.SetSnapshotStore(store)
+	r.cds.SetLeaderElection(mgr.Elected())
+
+	labeler := config.NewLeaderLabeler(mgr.GetClient(), r.pod, ctrl.Log.WithName(name))
+	Expect(mgr.Add(labeler)).To(Succeed())
+
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	Expect(labeler.Reset(ctx)).To(Succeed())
+	Expect(r.cds.Start(ctx)).To(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		defer close(r.done)
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+
+	return r
+}
+
+func (r *cdsReplica) stop() {
+	r.cancel()
+	Eventually(r.done, timeout, interval).Should(BeClosed())
+}
+
+// isLeader returns true if the pod of the replica carries the leader label.
+func (r *cdsReplica) isLeader() bool {
+	pod := &corev1.Pod{}
+	if err := k8sClient.Get(context.Background(), r.pod, pod); err != nil {
+		return false
+	}
+	return pod.GetLabels()[opdefault.DefaultCDSLeaderLabelKey] == opdefault.DefaultCDSLeaderLabelValue
+}
+
+// load queries the config of a Gateway from the config discovery server of the replica.
+func (r *cdsReplica) load(id string) (*stnrv1.StunnerConfig, error) {
+	c, err := cdsclient.New(r.addr, id, "", logger.NewLoggerFactory(stunnerLogLevel))
+	if err != nil {
+		return nil, err
+	}
+	return c.Load()
+}
+
+func leaderElectionTest() {
+	Context("When running two operator replicas with leader election", Ordered, Label("managed"), func() {
+		var r1, r2 *cdsReplica
+		var id string
+		realm := func(r *cdsReplica) string {
+			conf, err := r.load(id)
+			if err != nil || conf == nil {
+				return ""
+			}
+			return conf.Auth.Realm
+		}
+
+		BeforeAll(func() {
+			id = fmt.Sprintf("%s/%s", testNs.GetName(), "gateway-1")
+			for _, name := range []string{"operator-1", "operator-2"} {
+				Expect(k8sClient.Create(context.Background(), &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testNs.GetName(), Name: name},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "manager", Image: "controller:latest"},
+					}},
+				})).Should(Succeed())
+			}
+		})
+
+		AfterAll(func() {
+			for _, r := range []*cdsReplica{r1, r2} {
+				if r != nil {
+					r.cancel()
+				}
+			}
+			for _, name := range []string{"operator-1", "operator-2"} {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: testNs.GetName(), Name: name}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), pod))).Should(Succeed())
+			}
+		})
+
+		It("should elect the first replica as the leader", func() {
+			store := config.NewSecretSnapshotStore(k8sClient, k8sClient,
+				types.NamespacedName{Namespace: testNs.GetName(), Name: "cds-snapshot"})
+			r1 = startCDSReplica("operator-1", store)
+			Eventually(r1.isLeader, timeout, interval).Should(BeTrue())
+
+			r2 = startCDSReplica("operator-2", store)
+			Consistently(r2.isLeader, time.Second, interval).Should(BeFalse())
+		})
+
+		It("should serve the configs only on the leader", func() {
+			e := event.NewEventUpdate(1)
+			e.ConfigQueue = []*stnrv1.StunnerConfig{{
+				ApiVersion: stnrv1.ApiVersion,
+				Admin:      stnrv1.AdminConfig{Name: id},
+				Auth:       stnrv1.AuthConfig{Type: "static", Realm: "leader-1"},
+			}}
+			e.SetRequestAck(true)
+			r1.cds.GetConfigUpdateChannel() <- e
+			r1.cds.GetAckChannel() <- event.NewEventAck(1)
+
+			Eventually(func() string { return realm(r1) }, timeout, interval).Should(Equal("leader-1"))
+
+			_, err := r2.load(id)
+			Expect(err).Should(HaveOccurred(), "standby does not serve")
+		})
+
+		It("should fail over to the standby", func() {
+			r1.stop()
+			r1 = nil
+
+			Eventually(r2.isLeader, timeout, interval).Should(BeTrue())
+
+			// the new leader serves the snapshot published by the previous leader
+			Eventually(func() string { return realm(r2) }, timeout, interval).Should(Equal("leader-1"))
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNs.GetName(),
+				Name: "operator-1"}, pod)).Should(Succeed())
+			Expect(pod.GetLabels()).NotTo(HaveKey(opdefault.DefaultCDSLeaderLabelKey),
+				"label removed from the previous leader")
+		})
+	})
+}
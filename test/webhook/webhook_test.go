@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The l7mp/stunner team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/l7mp/stunner-gateway-operator/internal/testutils"
+
+	stnrgwv1 "github.com/l7mp/stunner-gateway-operator/api/v1"
+)
+
+// expectInvalid checks that the API server rejected an object with the given message.
+func expectInvalid(err error, msg string) {
+	Expect(err).To(HaveOccurred())
+	Expect(apierrors.IsInvalid(err)).To(BeTrue(), "invalid error: %s", err)
+	Expect(err.Error()).To(ContainSubstring(msg))
+}
+
+var _ = Describe("Validating webhook:", Ordered, func() {
+	Context("When creating a GatewayConfig", func() {
+		It("should accept a valid plaintext config", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			Expect(k8sClient.Create(ctx, gwConf)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, gwConf)).Should(Succeed())
+		})
+
+		It("should reject a plaintext config with no password", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			gwConf.SetName("gatewayconfig-no-password")
+			gwConf.Spec.Password = nil
+			expectInvalid(k8sClient.Create(ctx, gwConf), "spec.password")
+		})
+
+		It("should reject a longterm config with no shared secret", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			gwConf.SetName("gatewayconfig-no-secret")
+			atype := "ephemeral"
+			gwConf.Spec.AuthType = &atype
+			gwConf.Spec.SharedSecret = nil
+			expectInvalid(k8sClient.Create(ctx, gwConf), "spec.sharedSecret")
+		})
+
+		It("should reject an unknown auth type", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			gwConf.SetName("gatewayconfig-invalid-authtype")
+			atype := "dummy"
+			gwConf.Spec.AuthType = &atype
+			expectInvalid(k8sClient.Create(ctx, gwConf), "spec.authType")
+		})
+
+		It("should accept an authRef without checking the Secret", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			gwConf.SetName("gatewayconfig-authref")
+			gwConf.Spec.Password = nil
+			gwConf.Spec.AuthRef = &gwapiv1.SecretObjectReference{
+				Name: gwapiv1.ObjectName("dummy-secret"),
+			}
+			Expect(k8sClient.Create(ctx, gwConf)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, gwConf)).Should(Succeed())
+		})
+	})
+
+	Context("When updating a GatewayConfig", func() {
+		It("should reject an update that removes the password", func() {
+			gwConf := testutils.TestGwConfig.DeepCopy()
+			gwConf.SetName("gatewayconfig-update")
+			Expect(k8sClient.Create(ctx, gwConf)).Should(Succeed())
+
+			current := &stnrgwv1.GatewayConfig{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(gwConf), current)).Should(Succeed())
+			current.Spec.Password = nil
+			expectInvalid(k8sClient.Update(ctx, current), "spec.password")
+
+			Expect(k8sClient.Delete(ctx, gwConf)).Should(Succeed())
+		})
+	})
+
+	Context("When creating a StaticService", func() {
+		It("should accept IP addresses and CIDR prefixes", func() {
+			ssvc := testutils.TestStaticSvc.DeepCopy()
+			ssvc.Spec.Prefixes = []string{"10.11.12.13", "10.0.0.0/8", "fd00::/8"}
+			Expect(k8sClient.Create(ctx, ssvc)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, ssvc)).Should(Succeed())
+		})
+
+		It("should reject an invalid prefix", func() {
+			ssvc := testutils.TestStaticSvc.DeepCopy()
+			ssvc.SetName("staticservice-invalid")
+			ssvc.Spec.Prefixes = []string{"10.11.12.13", "10.0.0.0/33"}
+			expectInvalid(k8sClient.Create(ctx, ssvc), "spec.prefixes[1]")
+		})
+	})
+
+	Context("When creating a UDPRoute", func() {
+		It("should accept a valid port range", func() {
+			ro := testutils.TestUDPRoute.DeepCopy()
+			port, endPort := gwapiv1.PortNumber(1), gwapiv1.PortNumber(2)
+			ro.Spec.Rules[0].BackendRefs[0].Port = &port
+			ro.Spec.Rules[0].BackendRefs[0].EndPort = &endPort
+			Expect(k8sClient.Create(ctx, ro)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, ro)).Should(Succeed())
+		})
+
+		It("should reject a backend with endPort < port", func() {
+			ro := testutils.TestUDPRoute.DeepCopy()
+			ro.SetName("udproute-invalid-port-range")
+			port, endPort := gwapiv1.PortNumber(2), gwapiv1.PortNumber(1)
+			ro.Spec.Rules[0].BackendRefs[0].Port = &port
+			ro.Spec.Rules[0].BackendRefs[0].EndPort = &endPort
+			expectInvalid(k8sClient.Create(ctx, ro), "spec.rules[0].backendRefs[0]")
+		})
+	})
+
+	Context("When creating a Dataplane", func() {
+		It("should accept valid offload interfaces", func() {
+			dp := testutils.TestDataplane.DeepCopy()
+			dp.Spec.OffloadEngine = "XDP"
+			dp.Spec.OffloadInterfaces = []string{"eth0", "ens5"}
+			Expect(k8sClient.Create(ctx, dp)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, dp)).Should(Succeed())
+		})
+
+		It("should reject an invalid offload interface", func() {
+			dp := testutils.TestDataplane.DeepCopy()
+			dp.SetName("dataplane-invalid-intf")
+			dp.Spec.OffloadEngine = "XDP"
+			dp.Spec.OffloadInterfaces = []string{"eth0", "eth/1"}
+			expectInvalid(k8sClient.Create(ctx, dp), "spec.offloadInterfaces[1]")
+		})
+	})
+})